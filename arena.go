@@ -0,0 +1,66 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+// #include <stdlib.h>
+// #include "callback.h"
+import "C"
+import (
+	"unsafe"
+)
+
+// arenaChunkSize is the number of vertices stored per chunk.
+const arenaChunkSize = 4096
+
+type arenaChunk [arenaChunkSize][3]C.GLdouble
+
+// vertexArena stores the vertex coordinates handed to gluTessVertex. It
+// allocates C memory in chunks, so a stored location never moves while GLU
+// may still reference it.
+type vertexArena struct {
+	chunks []*arenaChunk
+	used   int // vertices stored in the last chunk
+}
+
+// alloc stores location and returns its address in C memory, which stays
+// valid until the next reset.
+func (a *vertexArena) alloc(location [3]float64) *C.GLdouble {
+	if len(a.chunks) == 0 || a.used == arenaChunkSize {
+		chunk := (*arenaChunk)(C.malloc(C.size_t(unsafe.Sizeof(arenaChunk{}))))
+		if chunk == nil {
+			panic("Out of memory.")
+		}
+		a.chunks = append(a.chunks, chunk)
+		a.used = 0
+	}
+	v := &a.chunks[len(a.chunks)-1][a.used]
+	a.used++
+	for i, x := range location {
+		v[i] = C.GLdouble(x)
+	}
+	return &v[0]
+}
+
+// reset releases all stored locations. The first chunk is kept for the
+// next polygon and the others are freed.
+func (a *vertexArena) reset() {
+	if len(a.chunks) == 0 {
+		return
+	}
+	for _, chunk := range a.chunks[1:] {
+		C.free(unsafe.Pointer(chunk))
+	}
+	a.chunks = a.chunks[:1]
+	a.used = 0
+}
+
+// free releases all memory held by the arena.
+func (a *vertexArena) free() {
+	for _, chunk := range a.chunks {
+		C.free(unsafe.Pointer(chunk))
+	}
+	a.chunks = nil
+	a.used = 0
+}
@@ -0,0 +1,160 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"fmt"
+)
+
+// BooleanOp selects the set operation performed by Boolean.
+type BooleanOp int
+
+const (
+	OpUnion        BooleanOp = iota // points in a or b
+	OpIntersection                  // points in both a and b
+	OpDifference                    // points in a but not in b
+	OpXor                           // points in exactly one of a and b
+)
+
+// Boolean combines the regions a and b, each made of one or more contours
+// interpreted with the given TESS_WINDING_* rule, and returns the boundary
+// of the result. The normal is handed to Tesselator.Normal; a zero normal
+// is computed from the contours of a, falling back to b.
+//
+// Returned contours are counterclockwise around the normal for outer
+// boundaries and clockwise for holes. Vertices created where edges cross
+// are included in the output.
+func Boolean(op BooleanOp, a, b []Contour, windingRule uint32, normal [3]float64) ([]Contour, error) {
+	if normal == [3]float64{} {
+		normal = contourNormal(a)
+	}
+	if normal == [3]float64{} {
+		normal = contourNormal(b)
+	}
+	if normal == [3]float64{} {
+		normal = [3]float64{0, 0, 1}
+	}
+
+	// Reduce each operand to non-overlapping contours with winding number
+	// one inside and zero outside. The winding numbers of the operands then
+	// add up, and each operation is a single winding rule over the sum.
+	a, err := tessellateBoundary(a, windingRule, normal)
+	if err != nil {
+		return nil, err
+	}
+	b, err = tessellateBoundary(b, windingRule, normal)
+	if err != nil {
+		return nil, err
+	}
+
+	var rule uint32
+	switch op {
+	case OpUnion:
+		rule = TESS_WINDING_POSITIVE
+	case OpIntersection:
+		rule = TESS_WINDING_ABS_GEQ_TWO
+	case OpDifference:
+		// Reversing b turns its winding number to -1, cancelling a.
+		for _, c := range b {
+			reverseContour(c)
+		}
+		rule = TESS_WINDING_POSITIVE
+	case OpXor:
+		rule = TESS_WINDING_ODD
+	default:
+		return nil, fmt.Errorf("Invalid boolean operation %d", op)
+	}
+
+	return tessellateBoundary(append(a, b...), rule, normal)
+}
+
+// tessellateBoundary runs the contours through a Tesselator with
+// TESS_BOUNDARY_ONLY set and collects the resulting line loops.
+func tessellateBoundary(contours []Contour, windingRule uint32, normal [3]float64) ([]Contour, error) {
+	b := &contourBuilder{}
+
+	tess := NewTess()
+	defer tess.Delete()
+
+	tess.SetBeginCallback(b.begin)
+	tess.SetVertexCallback(b.vertex)
+	tess.SetEndCallback(b.end)
+	tess.SetCombineCallback(b.combine)
+
+	tess.Property(TESS_WINDING_RULE, float64(windingRule))
+	tess.Property(TESS_BOUNDARY_ONLY, 1)
+	tess.Normal(normal[0], normal[1], normal[2])
+
+	for _, c := range contours {
+		b.locs = append(b.locs, c...)
+	}
+
+	n := 0
+	tess.BeginPolygon(nil)
+	for _, c := range contours {
+		tess.BeginContour()
+		for _, v := range c {
+			tess.Vertex(v, n)
+			n++
+		}
+		tess.EndContour()
+	}
+	if err := tess.EndPolygon(); err != nil {
+		return nil, err
+	}
+	return b.contours, nil
+}
+
+// contourBuilder collects the line loops of a boundary-only Tesselator.
+// Vertex data handed to the tesselator are indices into locs.
+type contourBuilder struct {
+	contours []Contour
+	current  Contour
+	locs     [][3]float64
+}
+
+func (b *contourBuilder) begin(tessType uint32, polygonData interface{}) {
+	b.current = nil
+}
+
+func (b *contourBuilder) vertex(vertexData interface{}, polygonData interface{}) {
+	b.current = append(b.current, b.locs[vertexData.(int)])
+}
+
+func (b *contourBuilder) end(polygonData interface{}) {
+	if len(b.current) > 0 {
+		b.contours = append(b.contours, b.current)
+	}
+	b.current = nil
+}
+
+func (b *contourBuilder) combine(coords [3]float64,
+	vertexData [4]interface{},
+	weight [4]float32,
+	polygonData interface{}) (outData interface{}) {
+
+	b.locs = append(b.locs, coords)
+	return len(b.locs) - 1
+}
+
+// contourNormal returns the Newell normal of the contours, the direction
+// around which their enclosed area winds counterclockwise.
+func contourNormal(contours []Contour) (n [3]float64) {
+	for _, c := range contours {
+		for i, p := range c {
+			q := c[(i+1)%len(c)]
+			n[0] += (p[1] - q[1]) * (p[2] + q[2])
+			n[1] += (p[2] - q[2]) * (p[0] + q[0])
+			n[2] += (p[0] - q[0]) * (p[1] + q[1])
+		}
+	}
+	return
+}
+
+func reverseContour(c Contour) {
+	for i, j := 0, len(c)-1; i < j; i, j = i+1, j-1 {
+		c[i], c[j] = c[j], c[i]
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"testing"
+)
+
+func TestBoolean(t *testing.T) {
+	a := []Contour{{{0, 0, 0}, {2, 0, 0}, {2, 2, 0}, {0, 2, 0}}}
+	// Clockwise, to check that operands are oriented before combining.
+	b := []Contour{{{1, 1, 0}, {1, 3, 0}, {3, 3, 0}, {3, 1, 0}}}
+
+	for _, test := range []struct {
+		op   BooleanOp
+		area float64
+	}{
+		{OpUnion, 7},
+		{OpIntersection, 1},
+		{OpDifference, 3},
+		{OpXor, 6},
+	} {
+		contours, err := Boolean(test.op, a, b, TESS_WINDING_ODD, [3]float64{0, 0, 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if area := contourArea(contours); area != test.area {
+			t.Errorf("Op %d: expected area == %v, got %v\n", test.op, test.area, area)
+		}
+	}
+}
+
+func TestBooleanHole(t *testing.T) {
+	a := []Contour{OuterContour[:], InnerContour[:]}
+	b := []Contour{{{-1, -1, 0}, {1, -1, 0}, {1, 1, 0}, {-1, 1, 0}}}
+
+	// The normal is computed from a when left zero.
+	contours, err := Boolean(OpUnion, a, b, TESS_WINDING_ODD, [3]float64{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area := contourArea(contours); area != 16 {
+		t.Errorf("Expected area == 16, got %v\n", area)
+	}
+}
+
+// contourArea sums the signed areas of the contours in the xy plane.
+func contourArea(contours []Contour) (area float64) {
+	for _, c := range contours {
+		for i, p := range c {
+			q := c[(i+1)%len(c)]
+			area += p[0]*q[1] - q[0]*p[1]
+		}
+	}
+	return area / 2
+}
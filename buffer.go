@@ -0,0 +1,133 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+// #include "callback.h"
+import "C"
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+// Create a new tesselator which buffers the begin, vertex, edge flag and
+// end callbacks on the C side, and calls the handlers in one pass when
+// EndPolygon returns from GLU. This avoids a cgo call per vertex.
+//
+// Combine and error callbacks are still called while GLU runs, so they
+// happen before all other callbacks of the polygon.
+func NewBufferedTess() (tess *Tesselator) {
+	tess = NewTess()
+	tess.buffer = C.newGluEventBuffer(C.uintptr_t(tess.handle))
+	if tess.buffer == nil {
+		panic("Out of memory.")
+	}
+	tess.SetErrorCallback(nil)
+	tess.SetCombineCallback(nil)
+	return
+}
+
+// NewBufferedNurbsRenderer creates a new NURBS object which buffers the
+// data callbacks on the C side, and calls the handlers in one pass when
+// EndSurface or EndCurve returns from GLU. This avoids a cgo call per
+// sample. Error callbacks are still called while GLU runs.
+func NewBufferedNurbsRenderer() *Nurbs {
+	n := NewNurbsRenderer()
+	n.buffer = C.newGluEventBuffer(C.uintptr_t(n.handle))
+	if n.buffer == nil {
+		panic("Out of memory.")
+	}
+	C.setGluNurbsCallbackDataBuffered(n.nurbs, n.buffer)
+	return n
+}
+
+func (tess *Tesselator) setCallback(which uint32) {
+	if tess.buffer != nil {
+		C.setGluTessBufferedCallback(tess.tess, C.GLenum(which))
+	} else {
+		C.setGluTessCallback(tess.tess, C.GLenum(which))
+	}
+}
+
+func (n *Nurbs) setCallback(which uint32) {
+	if n.buffer != nil {
+		C.setGluNurbsBufferedCallback(n.nurbs, C.GLenum(which))
+	} else {
+		C.setGluNurbsCallback(n.nurbs, C.GLenum(which))
+	}
+}
+
+// takeEvents returns the buffered events and empties the buffer. The
+// events stay valid until the buffer is written again.
+func takeEvents(buf *C.gluEventBuffer) []C.gluEvent {
+	if buf.failed != 0 {
+		buf.failed = 0
+		buf.count = 0
+		panic("Out of memory.")
+	}
+	events := unsafe.Slice(buf.events, buf.count)
+	buf.count = 0
+	return events
+}
+
+// replayEvents calls the handlers for the buffered callbacks.
+func (tess *Tesselator) replayEvents() {
+	events := takeEvents(tess.buffer)
+	for i := range events {
+		e := &events[i]
+		switch e.which {
+		case TESS_BEGIN_DATA:
+			if tess.beginData != nil {
+				tess.beginData(uint32(e.value), tess.polyData)
+			}
+		case TESS_VERTEX_DATA:
+			if tess.vertexData != nil {
+				tess.vertexData(cgo.Handle(e.data).Value(), tess.polyData)
+			}
+		case TESS_EDGE_FLAG_DATA:
+			if tess.edgeFlagData != nil {
+				tess.edgeFlagData(e.value != 0, tess.polyData)
+			}
+		case TESS_END_DATA:
+			if tess.endData != nil {
+				tess.endData(tess.polyData)
+			}
+		}
+	}
+}
+
+// replayEvents calls the handlers for the buffered callbacks.
+func (n *Nurbs) replayEvents() {
+	events := takeEvents(n.buffer)
+	for i := range events {
+		e := &events[i]
+		v := (*[4]float32)(unsafe.Pointer(&e.v))
+		switch e.which {
+		case NURBS_BEGIN_DATA:
+			if n.beginData != nil {
+				n.beginData(uint32(e.value), n.polyData)
+			}
+		case NURBS_VERTEX_DATA:
+			if n.vertexData != nil {
+				n.vertexData(v[:3], n.polyData)
+			}
+		case NURBS_NORMAL_DATA:
+			if n.normalData != nil {
+				n.normalData(v[:3], n.polyData)
+			}
+		case NURBS_COLOR_DATA:
+			if n.colorData != nil {
+				n.colorData(v[:], n.polyData)
+			}
+		case NURBS_TEXTURE_COORD_DATA:
+			if n.textureCoordData != nil {
+				n.textureCoordData(v[:], n.polyData)
+			}
+		case NURBS_END_DATA:
+			if n.endData != nil {
+				n.endData(n.polyData)
+			}
+		}
+	}
+}
@@ -2,54 +2,381 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+//go:build cgo
+
 #include "callback.h"
 
+// The trampolines below convert the data pointers GLU hands to callbacks
+// back to the handles they were made from.
+
+static void tessBeginData(GLenum type, void *polygon_data) {
+	goTessBeginData(type, (uintptr_t)polygon_data);
+}
+
+static void tessVertexData(void *vertex_data, void *polygon_data) {
+	goTessVertexData((uintptr_t)vertex_data, (uintptr_t)polygon_data);
+}
+
+static void tessEndData(void *polygon_data) {
+	goTessEndData((uintptr_t)polygon_data);
+}
+
+// GLU hands errors raised before the first gluTessBeginPolygon NULL
+// polygon data, so those are routed to the tesselator whose call is running
+// on the current thread. The calls below set it for their duration, and
+// restore the previous one for calls made from callbacks.
+static __thread uintptr_t tessErrorTarget;
+
+static void tessErrorData(GLenum errorNumber, void *polygon_data) {
+	goTessErrorData(errorNumber, polygon_data == NULL ? tessErrorTarget : (uintptr_t)polygon_data);
+}
+
+static void tessEdgeFlagData(GLboolean flag, void *polygon_data) {
+	goTessEdgeFlagData(flag, (uintptr_t)polygon_data);
+}
+
+static void tessCombineData(GLdouble coords[3], void *vertex_data[4],
+                            GLfloat weight[4], void **outData,
+                            void *polygon_data) {
+	*outData = (void *)goTessCombineData(coords, vertex_data, weight,
+	                                     (uintptr_t)polygon_data);
+}
+
 void setGluTessCallback(GLUtesselator *tess, GLenum which) {
 	switch(which) {
 	case GLU_TESS_BEGIN_DATA:
-		gluTessCallback(tess, which, (void (*)())goTessBeginData);
+		gluTessCallback(tess, which, (void (*)())tessBeginData);
 		break;
 	case GLU_TESS_VERTEX_DATA:
-		gluTessCallback(tess, which, (void (*)())goTessVertexData);
+		gluTessCallback(tess, which, (void (*)())tessVertexData);
 		break;
 	case GLU_TESS_END_DATA:
-		gluTessCallback(tess, which, (void (*)())goTessEndData);
+		gluTessCallback(tess, which, (void (*)())tessEndData);
 		break;
 	case GLU_TESS_ERROR_DATA:
-		gluTessCallback(tess, which, (void (*)())goTessErrorData);
+		gluTessCallback(tess, which, (void (*)())tessErrorData);
 		break;
 	case GLU_TESS_EDGE_FLAG_DATA:
-		gluTessCallback(tess, which, (void (*)())goTessEdgeFlagData);
+		gluTessCallback(tess, which, (void (*)())tessEdgeFlagData);
 		break;
 	case GLU_TESS_COMBINE_DATA:
-		gluTessCallback(tess, which, (void (*)())goTessCombineData);
+		gluTessCallback(tess, which, (void (*)())tessCombineData);
 		break;
 	}
 }
 
+void beginGluTessPolygon(GLUtesselator *tess, uintptr_t polygon_data) {
+	uintptr_t previous = tessErrorTarget;
+	tessErrorTarget = polygon_data;
+	gluTessBeginPolygon(tess, (void *)polygon_data);
+	tessErrorTarget = previous;
+}
+
+void endGluTessPolygon(GLUtesselator *tess, uintptr_t handle) {
+	uintptr_t previous = tessErrorTarget;
+	tessErrorTarget = handle;
+	gluTessEndPolygon(tess);
+	tessErrorTarget = previous;
+}
+
+void beginGluTessContour(GLUtesselator *tess, uintptr_t handle) {
+	uintptr_t previous = tessErrorTarget;
+	tessErrorTarget = handle;
+	gluTessBeginContour(tess);
+	tessErrorTarget = previous;
+}
+
+void endGluTessContour(GLUtesselator *tess, uintptr_t handle) {
+	uintptr_t previous = tessErrorTarget;
+	tessErrorTarget = handle;
+	gluTessEndContour(tess);
+	tessErrorTarget = previous;
+}
+
+void addGluTessVertex(GLUtesselator *tess, GLdouble *location, uintptr_t vertex_data, uintptr_t handle) {
+	uintptr_t previous = tessErrorTarget;
+	tessErrorTarget = handle;
+	gluTessVertex(tess, location, (void *)vertex_data);
+	tessErrorTarget = previous;
+}
+
+void setGluTessProperty(GLUtesselator *tess, GLenum which, GLdouble value, uintptr_t handle) {
+	uintptr_t previous = tessErrorTarget;
+	tessErrorTarget = handle;
+	gluTessProperty(tess, which, value);
+	tessErrorTarget = previous;
+}
+
+static void nurbsBeginData(GLenum type, void *polygon_data) {
+	goNurbsBeginData(type, (uintptr_t)polygon_data);
+}
+
+static void nurbsVertexData(GLfloat *vertex_data, void *polygon_data) {
+	goNurbsVertexData(vertex_data, (uintptr_t)polygon_data);
+}
+
+static void nurbsNormalData(GLfloat *normal_data, void *polygon_data) {
+	goNurbsNormalData(normal_data, (uintptr_t)polygon_data);
+}
+
+static void nurbsColorData(GLfloat *color_data, void *polygon_data) {
+	goNurbsColorData(color_data, (uintptr_t)polygon_data);
+}
+
+static void nurbsTextureCoordData(GLfloat *tex_coord_data, void *polygon_data) {
+	goNurbsTextureCoordData(tex_coord_data, (uintptr_t)polygon_data);
+}
+
+static void nurbsEndData(void *polygon_data) {
+	goNurbsEndData((uintptr_t)polygon_data);
+}
+
+// GLU_NURBS_ERROR callbacks receive no user data, so errors are routed to
+// the NURBS object whose method is running on the current thread.
+static __thread uintptr_t nurbsErrorTarget;
+
+static void nurbsError(GLenum errorNumber) {
+	goNurbsErrorData(errorNumber, nurbsErrorTarget);
+}
+
 void setGluNurbsCallback(GLUnurbs *nurbs, GLenum which) {
 	switch(which) {
 	case GLU_NURBS_BEGIN_DATA:
-		gluNurbsCallback(nurbs, which, (void (*)())goNurbsBeginData);
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsBeginData);
 		break;
 	case GLU_NURBS_VERTEX_DATA:
-		gluNurbsCallback(nurbs, which, (void (*)())goNurbsVertexData);
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsVertexData);
 		break;
 	case GLU_NURBS_NORMAL_DATA:
-		gluNurbsCallback(nurbs, which, (void (*)())goNurbsNormalData);
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsNormalData);
 		break;
 	case GLU_NURBS_COLOR_DATA:
-		gluNurbsCallback(nurbs, which, (void (*)())goNurbsColorData);
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsColorData);
 		break;
 	case GLU_NURBS_TEXTURE_COORD_DATA:
-		gluNurbsCallback(nurbs, which, (void (*)())goNurbsTextureCoordData);
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsTextureCoordData);
 		break;
 	case GLU_NURBS_END_DATA:
-		gluNurbsCallback(nurbs, which, (void (*)())goNurbsEndData);
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsEndData);
 		break;
 	case GLU_NURBS_ERROR:
-		gluNurbsCallback(nurbs, which, (void (*)())goNurbsErrorData);
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsError);
+		break;
+	}
+}
+
+void setGluNurbsCallbackData(GLUnurbs *nurbs, uintptr_t polygon_data) {
+	gluNurbsCallbackData(nurbs, (void *)polygon_data);
+}
+
+uintptr_t setGluNurbsErrorTarget(uintptr_t polygon_data) {
+	uintptr_t previous = nurbsErrorTarget;
+	nurbsErrorTarget = polygon_data;
+	return previous;
+}
+
+// =============================================================================
+
+gluEventBuffer *newGluEventBuffer(uintptr_t handle) {
+	gluEventBuffer *buf = calloc(1, sizeof(gluEventBuffer));
+	if (buf != NULL) {
+		buf->handle = handle;
+	}
+	return buf;
+}
+
+void freeGluEventBuffer(gluEventBuffer *buf) {
+	free(buf->events);
+	free(buf);
+}
+
+// pushEvent appends an event to buf and returns it, or returns NULL if it
+// cannot. GLU passes NULL polygon data to the callbacks of a polygon it
+// began itself because gluTessBeginPolygon was missing; like the
+// unbuffered callbacks, those are dropped.
+static gluEvent *pushEvent(gluEventBuffer *buf, GLenum which) {
+	if (buf == NULL) {
+		return NULL;
+	}
+	if (buf->count == buf->size) {
+		size_t size = buf->size == 0 ? 1024 : 2 * buf->size;
+		gluEvent *events = realloc(buf->events, size * sizeof(gluEvent));
+		if (events == NULL) {
+			buf->failed = 1;
+			return NULL;
+		}
+		buf->events = events;
+		buf->size = size;
+	}
+	gluEvent *e = &buf->events[buf->count++];
+	e->which = which;
+	e->value = 0;
+	e->data = 0;
+	return e;
+}
+
+static uintptr_t bufferHandle(void *polygon_data) {
+	return polygon_data == NULL ? 0 : ((gluEventBuffer *)polygon_data)->handle;
+}
+
+static void tessBeginBuffered(GLenum type, void *polygon_data) {
+	gluEvent *e = pushEvent(polygon_data, GLU_TESS_BEGIN_DATA);
+	if (e != NULL) {
+		e->value = type;
+	}
+}
+
+static void tessVertexBuffered(void *vertex_data, void *polygon_data) {
+	gluEvent *e = pushEvent(polygon_data, GLU_TESS_VERTEX_DATA);
+	if (e != NULL) {
+		e->data = (uintptr_t)vertex_data;
+	}
+}
+
+static void tessEndBuffered(void *polygon_data) {
+	pushEvent(polygon_data, GLU_TESS_END_DATA);
+}
+
+static void tessEdgeFlagBuffered(GLboolean flag, void *polygon_data) {
+	gluEvent *e = pushEvent(polygon_data, GLU_TESS_EDGE_FLAG_DATA);
+	if (e != NULL) {
+		e->value = flag;
+	}
+}
+
+static void tessErrorBuffered(GLenum errorNumber, void *polygon_data) {
+	goTessErrorData(errorNumber, polygon_data == NULL ? tessErrorTarget : bufferHandle(polygon_data));
+}
+
+static void tessCombineBuffered(GLdouble coords[3], void *vertex_data[4],
+                                GLfloat weight[4], void **outData,
+                                void *polygon_data) {
+	*outData = (void *)goTessCombineData(coords, vertex_data, weight,
+	                                     bufferHandle(polygon_data));
+}
+
+void setGluTessBufferedCallback(GLUtesselator *tess, GLenum which) {
+	switch(which) {
+	case GLU_TESS_BEGIN_DATA:
+		gluTessCallback(tess, which, (void (*)())tessBeginBuffered);
+		break;
+	case GLU_TESS_VERTEX_DATA:
+		gluTessCallback(tess, which, (void (*)())tessVertexBuffered);
+		break;
+	case GLU_TESS_END_DATA:
+		gluTessCallback(tess, which, (void (*)())tessEndBuffered);
+		break;
+	case GLU_TESS_ERROR_DATA:
+		gluTessCallback(tess, which, (void (*)())tessErrorBuffered);
+		break;
+	case GLU_TESS_EDGE_FLAG_DATA:
+		gluTessCallback(tess, which, (void (*)())tessEdgeFlagBuffered);
+		break;
+	case GLU_TESS_COMBINE_DATA:
+		gluTessCallback(tess, which, (void (*)())tessCombineBuffered);
 		break;
 	}
 }
 
+void beginGluTessPolygonBuffered(GLUtesselator *tess, gluEventBuffer *buf) {
+	uintptr_t previous = tessErrorTarget;
+	tessErrorTarget = buf->handle;
+	gluTessBeginPolygon(tess, buf);
+	tessErrorTarget = previous;
+}
+
+static void nurbsBeginBuffered(GLenum type, void *polygon_data) {
+	gluEvent *e = pushEvent(polygon_data, GLU_NURBS_BEGIN_DATA);
+	if (e != NULL) {
+		e->value = type;
+	}
+}
+
+static void pushNurbsData(void *polygon_data, GLenum which, GLfloat *v, int n) {
+	gluEvent *e = pushEvent(polygon_data, which);
+	if (e != NULL) {
+		for (int i = 0; i < n; i++) {
+			e->v[i] = v[i];
+		}
+	}
+}
+
+static void nurbsVertexBuffered(GLfloat *vertex_data, void *polygon_data) {
+	gluEventBuffer *buf = polygon_data;
+	if (buf->rational && vertex_data[3] != 0) {
+		GLfloat v[3];
+		for (int i = 0; i < 3; i++) {
+			v[i] = vertex_data[i] / vertex_data[3];
+		}
+		pushNurbsData(polygon_data, GLU_NURBS_VERTEX_DATA, v, 3);
+		return;
+	}
+	pushNurbsData(polygon_data, GLU_NURBS_VERTEX_DATA, vertex_data, 3);
+}
+
+static void nurbsNormalBuffered(GLfloat *normal_data, void *polygon_data) {
+	pushNurbsData(polygon_data, GLU_NURBS_NORMAL_DATA, normal_data, 3);
+}
+
+static void nurbsColorBuffered(GLfloat *color_data, void *polygon_data) {
+	pushNurbsData(polygon_data, GLU_NURBS_COLOR_DATA, color_data, 4);
+}
+
+static void nurbsTextureCoordBuffered(GLfloat *tex_coord_data, void *polygon_data) {
+	pushNurbsData(polygon_data, GLU_NURBS_TEXTURE_COORD_DATA, tex_coord_data, 4);
+}
+
+static void nurbsEndBuffered(void *polygon_data) {
+	pushEvent(polygon_data, GLU_NURBS_END_DATA);
+}
+
+void setGluNurbsBufferedCallback(GLUnurbs *nurbs, GLenum which) {
+	switch(which) {
+	case GLU_NURBS_BEGIN_DATA:
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsBeginBuffered);
+		break;
+	case GLU_NURBS_VERTEX_DATA:
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsVertexBuffered);
+		break;
+	case GLU_NURBS_NORMAL_DATA:
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsNormalBuffered);
+		break;
+	case GLU_NURBS_COLOR_DATA:
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsColorBuffered);
+		break;
+	case GLU_NURBS_TEXTURE_COORD_DATA:
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsTextureCoordBuffered);
+		break;
+	case GLU_NURBS_END_DATA:
+		gluNurbsCallback(nurbs, which, (void (*)())nurbsEndBuffered);
+		break;
+	default:
+		// Errors carry no user data and are not buffered.
+		setGluNurbsCallback(nurbs, which);
+		break;
+	}
+}
+
+void setGluNurbsCallbackDataBuffered(GLUnurbs *nurbs, gluEventBuffer *buf) {
+	gluNurbsCallbackData(nurbs, buf);
+}
+
+// =============================================================================
+
+// GLU_ERROR callbacks of quadrics receive no user data either, and are
+// routed like NURBS errors.
+static __thread uintptr_t quadricErrorTarget;
+
+static void quadricError(GLenum errorNumber) {
+	goQuadricErrorData(errorNumber, quadricErrorTarget);
+}
+
+void setGluQuadricErrorCallback(GLUquadric *quad) {
+	gluQuadricCallback(quad, GLU_ERROR, (void (*)())quadricError);
+}
+
+uintptr_t setGluQuadricErrorTarget(uintptr_t quadric_data) {
+	uintptr_t previous = quadricErrorTarget;
+	quadricErrorTarget = quadric_data;
+	return previous;
+}
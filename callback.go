@@ -10,6 +10,7 @@ package glu
 //#include "callback.h"
 import "C"
 import (
+	"runtime/cgo"
 	"unsafe"
 )
 
@@ -19,11 +20,9 @@ import (
 //
 // =============================================================================
 
-type TessBeginHandler func(tessType uint32, polygonData interface{})
-
 //export goTessBeginData
-func goTessBeginData(tessType C.GLenum, tessPtr unsafe.Pointer) {
-	var tess *Tesselator = (*Tesselator)(tessPtr)
+func goTessBeginData(tessType C.GLenum, tessHandle C.uintptr_t) {
+	tess := tessFromHandle(tessHandle)
 	if tess == nil || tess.beginData == nil {
 		return
 	}
@@ -32,25 +31,20 @@ func goTessBeginData(tessType C.GLenum, tessPtr unsafe.Pointer) {
 
 // ===========================================================================
 
-type TessVertexHandler func(vertexData interface{}, polygonData interface{})
-
 //export goTessVertexData
-func goTessVertexData(vertexDataPtr, tessPtr unsafe.Pointer) {
-	var tess *Tesselator = (*Tesselator)(tessPtr)
+func goTessVertexData(vertexHandle, tessHandle C.uintptr_t) {
+	tess := tessFromHandle(tessHandle)
 	if tess == nil || tess.vertexData == nil {
 		return
 	}
-	var wrapper *vertexDataWrapper = (*vertexDataWrapper)(vertexDataPtr)
-	tess.vertexData(wrapper.data, tess.polyData)
+	tess.vertexData(cgo.Handle(vertexHandle).Value(), tess.polyData)
 }
 
 // ===========================================================================
 
-type TessEndHandler func(polygonData interface{})
-
 //export goTessEndData
-func goTessEndData(tessPtr unsafe.Pointer) {
-	var tess *Tesselator = (*Tesselator)(tessPtr)
+func goTessEndData(tessHandle C.uintptr_t) {
+	tess := tessFromHandle(tessHandle)
 	if tess == nil || tess.endData == nil {
 		return
 	}
@@ -59,12 +53,14 @@ func goTessEndData(tessPtr unsafe.Pointer) {
 
 // ===========================================================================
 
-type TessErrorHandler func(errorNumber uint32, polygonData interface{})
-
 //export goTessErrorData
-func goTessErrorData(errorNumber C.GLenum, tessPtr unsafe.Pointer) {
-	var tess *Tesselator = (*Tesselator)(tessPtr)
-	if tess == nil || tess.errorData == nil {
+func goTessErrorData(errorNumber C.GLenum, tessHandle C.uintptr_t) {
+	tess := tessFromHandle(tessHandle)
+	if tess == nil {
+		return
+	}
+	tess.errs.record(uint32(errorNumber))
+	if tess.errorData == nil {
 		return
 	}
 	tess.errorData(uint32(errorNumber), tess.polyData)
@@ -72,11 +68,9 @@ func goTessErrorData(errorNumber C.GLenum, tessPtr unsafe.Pointer) {
 
 // ===========================================================================
 
-type TessEdgeFlagHandler func(flag bool, polygonData interface{})
-
 //export goTessEdgeFlagData
-func goTessEdgeFlagData(flag C.GLboolean, tessPtr unsafe.Pointer) {
-	var tess *Tesselator = (*Tesselator)(tessPtr)
+func goTessEdgeFlagData(flag C.GLboolean, tessHandle C.uintptr_t) {
+	tess := tessFromHandle(tessHandle)
 	if tess == nil || tess.edgeFlagData == nil {
 		return
 	}
@@ -92,40 +86,50 @@ func goTessEdgeFlagData(flag C.GLboolean, tessPtr unsafe.Pointer) {
 
 // ===========================================================================
 
-type TessCombineHandler func(coords [3]float64,
-	vertexData [4]interface{},
-	weight [4]float32,
-	polygonData interface{}) (outData interface{})
-
 //export goTessCombineData
-func goTessCombineData(coords, vertexData, weight, outData, tessPtr unsafe.Pointer) {
-	var tess *Tesselator = (*Tesselator)(tessPtr)
-	if tess == nil || tess.combineData == nil {
-		return
+func goTessCombineData(coords, vertexData, weight unsafe.Pointer, tessHandle C.uintptr_t) C.uintptr_t {
+	tess := tessFromHandle(tessHandle)
+	if tess == nil {
+		return 0
 	}
 
 	var _coords *[3]float64 = (*[3]float64)(coords)
 	var _weight *[4]float32 = (*[4]float32)(weight)
 
-	var wrappers *[4]*vertexDataWrapper = (*[4]*vertexDataWrapper)(vertexData)
+	var handles *[4]C.uintptr_t = (*[4]C.uintptr_t)(vertexData)
 	var _vertexData [4]interface{}
 
-	for i, wrapper := range *wrappers {
+	for i, h := range *handles {
 		// Work around for https://bugs.freedesktop.org/show_bug.cgi?id=51641
 		// According to documentation, all vertex pointers should be valid.
-		if wrapper == nil {
+		if h == 0 {
 			_vertexData[i] = _vertexData[0]
 		} else {
-			_vertexData[i] = wrapper.data
+			_vertexData[i] = cgo.Handle(h).Value()
 		}
 	}
 
-	out := tess.combineData(*_coords, _vertexData, *_weight, tess.polyData)
-	outWrapper := &vertexDataWrapper{out}
+	var out interface{}
+	if tess.combineData != nil {
+		out = tess.combineData(*_coords, _vertexData, *_weight, tess.polyData)
+	} else if data, ok := interpolateVertexData(*_coords, _vertexData, *_weight); ok {
+		out = data
+	} else {
+		// Let GLU report TESS_NEED_COMBINE_CALLBACK, as if no callback
+		// was set.
+		return 0
+	}
+	return C.uintptr_t(tess.newVertexHandle(out))
+}
 
-	tess.vertData = append(tess.vertData, outWrapper)
-	_outData := (**vertexDataWrapper)(outData)
-	*_outData = outWrapper
+// tessFromHandle returns the Tesselator behind the polygon data handed to
+// a callback. GLU passes NULL to the callbacks of a polygon it began itself
+// because BeginPolygon was missing.
+func tessFromHandle(h C.uintptr_t) *Tesselator {
+	if h == 0 {
+		return nil
+	}
+	return cgo.Handle(h).Value().(*Tesselator)
 }
 
 // =============================================================================
@@ -143,8 +147,8 @@ type NurbsEndDataHandler func(polygonData interface{})
 type NurbsErrorHandler func(errorNumber uint32, polygonData interface{})
 
 //export goNurbsBeginData
-func goNurbsBeginData(tessType C.GLenum, nurbsPtr unsafe.Pointer) {
-	var nurbs *Nurbs = (*Nurbs)(nurbsPtr)
+func goNurbsBeginData(tessType C.GLenum, nurbsHandle C.uintptr_t) {
+	nurbs := nurbsFromHandle(nurbsHandle)
 	if nurbs == nil || nurbs.beginData == nil {
 		return
 	}
@@ -152,18 +156,25 @@ func goNurbsBeginData(tessType C.GLenum, nurbsPtr unsafe.Pointer) {
 }
 
 //export goNurbsVertexData
-func goNurbsVertexData(vertexDataPtr, nurbsPtr unsafe.Pointer) {
-	var nurbs *Nurbs = (*Nurbs)(nurbsPtr)
+func goNurbsVertexData(vertexDataPtr unsafe.Pointer, nurbsHandle C.uintptr_t) {
+	nurbs := nurbsFromHandle(nurbsHandle)
 	if nurbs == nil || nurbs.vertexData == nil {
 		return
 	}
 	var vertex []float32 = (*[3]float32)(vertexDataPtr)[:]
+	if nurbs.rationalCurve {
+		// GLU hands out the homogeneous vertices of rational curves.
+		h := (*[4]float32)(vertexDataPtr)
+		if h[3] != 0 {
+			vertex = []float32{h[0] / h[3], h[1] / h[3], h[2] / h[3]}
+		}
+	}
 	nurbs.vertexData(vertex, nurbs.polyData)
 }
 
 //export goNurbsNormalData
-func goNurbsNormalData(normalDataPtr, nurbsPtr unsafe.Pointer) {
-	var nurbs *Nurbs = (*Nurbs)(nurbsPtr)
+func goNurbsNormalData(normalDataPtr unsafe.Pointer, nurbsHandle C.uintptr_t) {
+	nurbs := nurbsFromHandle(nurbsHandle)
 	if nurbs == nil || nurbs.normalData == nil {
 		return
 	}
@@ -172,8 +183,8 @@ func goNurbsNormalData(normalDataPtr, nurbsPtr unsafe.Pointer) {
 }
 
 //export goNurbsColorData
-func goNurbsColorData(colorDataPtr, nurbsPtr unsafe.Pointer) {
-	var nurbs *Nurbs = (*Nurbs)(nurbsPtr)
+func goNurbsColorData(colorDataPtr unsafe.Pointer, nurbsHandle C.uintptr_t) {
+	nurbs := nurbsFromHandle(nurbsHandle)
 	if nurbs == nil || nurbs.colorData == nil {
 		return
 	}
@@ -182,8 +193,8 @@ func goNurbsColorData(colorDataPtr, nurbsPtr unsafe.Pointer) {
 }
 
 //export goNurbsTextureCoordData
-func goNurbsTextureCoordData(texCoordDataPtr, nurbsPtr unsafe.Pointer) {
-	var nurbs *Nurbs = (*Nurbs)(nurbsPtr)
+func goNurbsTextureCoordData(texCoordDataPtr unsafe.Pointer, nurbsHandle C.uintptr_t) {
+	nurbs := nurbsFromHandle(nurbsHandle)
 	if nurbs == nil || nurbs.textureCoordData == nil {
 		return
 	}
@@ -192,8 +203,8 @@ func goNurbsTextureCoordData(texCoordDataPtr, nurbsPtr unsafe.Pointer) {
 }
 
 //export goNurbsEndData
-func goNurbsEndData(nurbsPtr unsafe.Pointer) {
-	var nurbs *Nurbs = (*Nurbs)(nurbsPtr)
+func goNurbsEndData(nurbsHandle C.uintptr_t) {
+	nurbs := nurbsFromHandle(nurbsHandle)
 	if nurbs == nil || nurbs.endData == nil {
 		return
 	}
@@ -201,14 +212,42 @@ func goNurbsEndData(nurbsPtr unsafe.Pointer) {
 }
 
 //export goNurbsErrorData
-func goNurbsErrorData(errorNumber C.GLenum, nurbsPtr unsafe.Pointer) {
-	var nurbs *Nurbs = (*Nurbs)(nurbsPtr)
+func goNurbsErrorData(errorNumber C.GLenum, nurbsHandle C.uintptr_t) {
+	nurbs := nurbsFromHandle(nurbsHandle)
 	if nurbs == nil || nurbs.errorData == nil {
 		return
 	}
 	nurbs.errorData(uint32(errorNumber), nurbs.polyData)
 }
 
+// nurbsFromHandle returns the Nurbs behind the user data handed to a
+// callback, which is NULL until NurbsCallbackData is called.
+func nurbsFromHandle(h C.uintptr_t) *Nurbs {
+	if h == 0 {
+		return nil
+	}
+	return cgo.Handle(h).Value().(*Nurbs)
+}
+
+// =============================================================================
+//
+// Section: Quadric callbacks
+//
+// =============================================================================
+
+type QuadricErrorHandler func(errorNumber uint32)
+
+//export goQuadricErrorData
+func goQuadricErrorData(errorNumber C.GLenum, quadricHandle C.uintptr_t) {
+	if quadricHandle == 0 {
+		return
+	}
+	q := cgo.Handle(quadricHandle).Value().(*Quadric)
+	if q.errorData != nil {
+		q.errorData(uint32(errorNumber))
+	}
+}
+
 // =============================================================================
 
 // Sets the callback for TESS_BEGIN_DATA.
@@ -217,7 +256,7 @@ func (tess *Tesselator) SetBeginCallback(f TessBeginHandler) {
 		panic("Uninitialised Tesselator. @see glu.NewTess.")
 	}
 	tess.beginData = f
-	C.setGluTessCallback(tess.tess, C.GLenum(TESS_BEGIN_DATA))
+	tess.setCallback(TESS_BEGIN_DATA)
 }
 
 // Sets the callback for TESS_VERTEX_DATA.
@@ -226,7 +265,7 @@ func (tess *Tesselator) SetVertexCallback(f TessVertexHandler) {
 		panic("Uninitialised Tesselator. @see glu.NewTess.")
 	}
 	tess.vertexData = f
-	C.setGluTessCallback(tess.tess, C.GLenum(TESS_VERTEX_DATA))
+	tess.setCallback(TESS_VERTEX_DATA)
 }
 
 // Sets the callback for TESS_END_DATA.
@@ -235,7 +274,7 @@ func (tess *Tesselator) SetEndCallback(f TessEndHandler) {
 		panic("Uninitialised Tesselator. @see glu.NewTess.")
 	}
 	tess.endData = f
-	C.setGluTessCallback(tess.tess, C.GLenum(TESS_END_DATA))
+	tess.setCallback(TESS_END_DATA)
 }
 
 // Sets the callback for TESS_ERROR_DATA.
@@ -244,7 +283,7 @@ func (tess *Tesselator) SetErrorCallback(f TessErrorHandler) {
 		panic("Uninitialised Tesselator. @see glu.NewTess.")
 	}
 	tess.errorData = f
-	C.setGluTessCallback(tess.tess, C.GLenum(TESS_ERROR_DATA))
+	tess.setCallback(TESS_ERROR_DATA)
 }
 
 // Sets the callback for TESS_EDGE_FLAG_DATA.
@@ -253,7 +292,7 @@ func (tess *Tesselator) SetEdgeFlagCallback(f TessEdgeFlagHandler) {
 		panic("Uninitialised Tesselator. @see glu.NewTess.")
 	}
 	tess.edgeFlagData = f
-	C.setGluTessCallback(tess.tess, C.GLenum(TESS_EDGE_FLAG_DATA))
+	tess.setCallback(TESS_EDGE_FLAG_DATA)
 }
 
 // Sets the callback for TESS_COMBINE_DATA.
@@ -262,5 +301,5 @@ func (tess *Tesselator) SetCombineCallback(f TessCombineHandler) {
 		panic("Uninitialised Tesselator. @see glu.NewTess.")
 	}
 	tess.combineData = f
-	C.setGluTessCallback(tess.tess, C.GLenum(TESS_COMBINE_DATA))
+	tess.setCallback(TESS_COMBINE_DATA)
 }
@@ -5,6 +5,9 @@
 #ifndef _CALLBACK_H_
 #define _CALLBACK_H_
 
+#include <stdint.h>
+#include <stdlib.h>
+
 #ifdef __APPLE__
   #define GL_SILENCE_DEPRECATION
   #include <OpenGL/glu.h>
@@ -22,25 +25,68 @@ typedef GLvoid (*_GLUfuncptr)(void);
 typedef void (APIENTRY *_GLUfuncptr)();
 #endif
 
-extern void goTessBeginData(GLenum type, void *polygon_data);
-extern void goTessVertexData(void *vertex_data, void *polygon_data);
-extern void goTessEndData(void *polygon_data);
-extern void goTessErrorData(GLenum errorNumber, void *polygon_data);
-extern void goTessEdgeFlagData(GLboolean flag, void *polygon_data);
-extern void goTessCombineData(void *coords, void *vertex_data,
-                              void *weight, void *outData,
-                              void *polygon_data);
+// Objects on the Go side are identified by runtime/cgo.Handle values, which
+// are passed through GLU's polygon_data and vertex_data pointers.
+
+extern void goTessBeginData(GLenum type, uintptr_t polygon_data);
+extern void goTessVertexData(uintptr_t vertex_data, uintptr_t polygon_data);
+extern void goTessEndData(uintptr_t polygon_data);
+extern void goTessErrorData(GLenum errorNumber, uintptr_t polygon_data);
+extern void goTessEdgeFlagData(GLboolean flag, uintptr_t polygon_data);
+extern uintptr_t goTessCombineData(void *coords, void *vertex_data,
+                                   void *weight, uintptr_t polygon_data);
 
 void setGluTessCallback(GLUtesselator *tess, GLenum which);
+void beginGluTessPolygon(GLUtesselator *tess, uintptr_t polygon_data);
+void endGluTessPolygon(GLUtesselator *tess, uintptr_t handle);
+void beginGluTessContour(GLUtesselator *tess, uintptr_t handle);
+void endGluTessContour(GLUtesselator *tess, uintptr_t handle);
+void addGluTessVertex(GLUtesselator *tess, GLdouble *location, uintptr_t vertex_data, uintptr_t handle);
+void setGluTessProperty(GLUtesselator *tess, GLenum which, GLdouble value, uintptr_t handle);
+
+// A gluEventBuffer records begin, vertex, edge flag and end callbacks so
+// they can be handed to Go in one transfer. It is passed to GLU as polygon
+// data in place of the handle, which it carries for the callbacks that
+// still go to Go directly.
+typedef struct {
+	GLenum which;    // callback, as GLU_TESS_*_DATA or GLU_NURBS_*_DATA
+	GLenum value;    // primitive type or edge flag
+	uintptr_t data;  // tesselator vertex data
+	GLfloat v[4];    // NURBS vertex, normal, color or texture coordinate
+} gluEvent;
+
+typedef struct {
+	uintptr_t handle;
+	gluEvent *events;
+	size_t count;
+	size_t size;
+	int failed;      // set if growing events failed
+	int rational;    // set while vertices of a MAP1_VERTEX_4 curve are homogeneous
+} gluEventBuffer;
 
-extern void goNurbsBeginData(GLenum type, void *polygon_data);
-extern void goNurbsVertexData(void *vertex_data, void *polygon_data);
-extern void goNurbsNormalData(void *normal_data, void *polygon_data);
-extern void goNurbsColorData(void *color_data, void *polygon_data);
-extern void goNurbsTextureCoordData(void *tex_coord_data, void *polygon_data);
-extern void goNurbsEndData(void *polygon_data);
-extern void goNurbsErrorData(GLenum errorNumber, void *polygon_data);
+gluEventBuffer *newGluEventBuffer(uintptr_t handle);
+void freeGluEventBuffer(gluEventBuffer *buf);
+
+void setGluTessBufferedCallback(GLUtesselator *tess, GLenum which);
+void beginGluTessPolygonBuffered(GLUtesselator *tess, gluEventBuffer *buf);
+
+extern void goNurbsBeginData(GLenum type, uintptr_t polygon_data);
+extern void goNurbsVertexData(void *vertex_data, uintptr_t polygon_data);
+extern void goNurbsNormalData(void *normal_data, uintptr_t polygon_data);
+extern void goNurbsColorData(void *color_data, uintptr_t polygon_data);
+extern void goNurbsTextureCoordData(void *tex_coord_data, uintptr_t polygon_data);
+extern void goNurbsEndData(uintptr_t polygon_data);
+extern void goNurbsErrorData(GLenum errorNumber, uintptr_t polygon_data);
 
 void setGluNurbsCallback(GLUnurbs *nurbs, GLenum which);
+void setGluNurbsCallbackData(GLUnurbs *nurbs, uintptr_t polygon_data);
+uintptr_t setGluNurbsErrorTarget(uintptr_t polygon_data);
+void setGluNurbsBufferedCallback(GLUnurbs *nurbs, GLenum which);
+void setGluNurbsCallbackDataBuffered(GLUnurbs *nurbs, gluEventBuffer *buf);
+
+extern void goQuadricErrorData(GLenum errorNumber, uintptr_t quadric_data);
+
+void setGluQuadricErrorCallback(GLUquadric *quad);
+uintptr_t setGluQuadricErrorTarget(uintptr_t quadric_data);
 
 #endif // _CALLBACK_H_
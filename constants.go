@@ -25,10 +25,80 @@ const (
 	TESS_WINDING_NEGATIVE    = 100133
 	TESS_WINDING_ABS_GEQ_TWO = 100134
 
+	// TessError
+	TESS_ERROR1                = 100151
+	TESS_ERROR2                = 100152
+	TESS_ERROR3                = 100153
+	TESS_ERROR4                = 100154
+	TESS_ERROR5                = 100155
+	TESS_ERROR6                = 100156
+	TESS_ERROR7                = 100157
+	TESS_ERROR8                = 100158
+	TESS_MISSING_BEGIN_POLYGON = 100151
+	TESS_MISSING_BEGIN_CONTOUR = 100152
+	TESS_MISSING_END_POLYGON   = 100153
+	TESS_MISSING_END_CONTOUR   = 100154
+	TESS_COORD_TOO_LARGE       = 100155
+	TESS_NEED_COMBINE_CALLBACK = 100156
+
+	// ErrorCode
+	INVALID_ENUM      = 100900
+	INVALID_VALUE     = 100901
+	OUT_OF_MEMORY     = 100902
+	INVALID_OPERATION = 100904
+
+	// Primitive
+	POINTS         = 0x0000
+	LINES          = 0x0001
+	LINE_LOOP      = 0x0002
+	LINE_STRIP     = 0x0003
+	TRIANGLES      = 0x0004
+	TRIANGLE_STRIP = 0x0005
+	TRIANGLE_FAN   = 0x0006
+	QUADS          = 0x0007
+	QUAD_STRIP     = 0x0008
+	POLYGON        = 0x0009
+
 	// NurbsProperty
-	SAMPLING_TOLERANCE = 100203
-	DISPLAY_MODE       = 100204
-	FILL               = 100012
+	AUTO_LOAD_MATRIX     = 100200
+	CULLING              = 100201
+	PARAMETRIC_TOLERANCE = 100202
+	SAMPLING_TOLERANCE   = 100203
+	DISPLAY_MODE         = 100204
+	SAMPLING_METHOD      = 100205
+	U_STEP               = 100206
+	V_STEP               = 100207
+
+	// QuadricDrawStyle
+	POINT      = 100010
+	LINE       = 100011
+	SILHOUETTE = 100013
+
+	// QuadricNormal
+	SMOOTH = 100000
+	FLAT   = 100001
+	NONE   = 100002
+
+	// QuadricOrientation
+	OUTSIDE = 100020
+	INSIDE  = 100021
+
+	// QuadricCallback
+	ERROR = 100103
+
+	// NurbsDisplay
+	FILL            = 100012
+	OUTLINE_POLYGON = 100240
+	OUTLINE_PATCH   = 100241
+
+	// NurbsSampling
+	OBJECT_PARAMETRIC_ERROR     = 100208
+	OBJECT_PARAMETRIC_ERROR_EXT = 100208
+	OBJECT_PATH_LENGTH          = 100209
+	OBJECT_PATH_LENGTH_EXT      = 100209
+	PATH_LENGTH                 = 100215
+	PARAMETRIC_ERROR            = 100216
+	DOMAIN_DISTANCE             = 100217
 
 	// NurbsCallback
 	NURBS_ERROR                      = 100103
@@ -104,5 +174,26 @@ const (
 	NURBS_RENDERER      = 100162
 	NURBS_RENDERER_EXT  = 100162
 	// Map
-	MAP1_VERTEX_3 = 0x0D97
+	MAP1_COLOR_4         = 0x0D90
+	MAP1_INDEX           = 0x0D91
+	MAP1_NORMAL          = 0x0D92
+	MAP1_TEXTURE_COORD_1 = 0x0D93
+	MAP1_TEXTURE_COORD_2 = 0x0D94
+	MAP1_TEXTURE_COORD_3 = 0x0D95
+	MAP1_TEXTURE_COORD_4 = 0x0D96
+	MAP1_VERTEX_3        = 0x0D97
+	MAP1_VERTEX_4        = 0x0D98
+	MAP2_COLOR_4         = 0x0DB0
+	MAP2_INDEX           = 0x0DB1
+	MAP2_NORMAL          = 0x0DB2
+	MAP2_TEXTURE_COORD_1 = 0x0DB3
+	MAP2_TEXTURE_COORD_2 = 0x0DB4
+	MAP2_TEXTURE_COORD_3 = 0x0DB5
+	MAP2_TEXTURE_COORD_4 = 0x0DB6
+	MAP2_VERTEX_3        = 0x0DB7
+	MAP2_VERTEX_4        = 0x0DB8
+
+	// NurbsTrim
+	MAP1_TRIM_2 = 100210
+	MAP1_TRIM_3 = 100211
 )
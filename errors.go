@@ -0,0 +1,116 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"fmt"
+)
+
+// TessError is an error reported by a Tesselator. Contour is the index of
+// the contour within the polygon and Vertex the index of the vertex within
+// that contour at which the error happened, or -1 if the error is not tied
+// to one, as for errors found by EndPolygon.
+type TessError struct {
+	Code    uint32
+	Contour int
+	Vertex  int
+}
+
+// Sentinel errors for use with errors.Is. A TessError matches the sentinel
+// with the same Code, wherever it happened.
+var (
+	ErrTessMissingBeginPolygon = &TessError{TESS_MISSING_BEGIN_POLYGON, -1, -1}
+	ErrTessMissingBeginContour = &TessError{TESS_MISSING_BEGIN_CONTOUR, -1, -1}
+	ErrTessMissingEndPolygon   = &TessError{TESS_MISSING_END_POLYGON, -1, -1}
+	ErrTessMissingEndContour   = &TessError{TESS_MISSING_END_CONTOUR, -1, -1}
+	ErrTessCoordTooLarge       = &TessError{TESS_COORD_TOO_LARGE, -1, -1}
+	ErrTessNeedCombineCallback = &TessError{TESS_NEED_COMBINE_CALLBACK, -1, -1}
+	ErrInvalidEnum             = &TessError{INVALID_ENUM, -1, -1}
+	ErrInvalidValue            = &TessError{INVALID_VALUE, -1, -1}
+	ErrOutOfMemory             = &TessError{OUT_OF_MEMORY, -1, -1}
+)
+
+func (e *TessError) Error() string {
+	text, err := ErrorString(e.Code)
+	if err != nil {
+		text = fmt.Sprintf("Tessellation error %d", e.Code)
+	}
+	switch {
+	case e.Contour < 0:
+		return text
+	case e.Vertex < 0:
+		return fmt.Sprintf("%s (contour %d)", text, e.Contour)
+	}
+	return fmt.Sprintf("%s (contour %d, vertex %d)", text, e.Contour, e.Vertex)
+}
+
+// Is reports whether target is a TessError with the same code.
+func (e *TessError) Is(target error) bool {
+	t, ok := target.(*TessError)
+	return ok && t.Code == e.Code
+}
+
+// NurbsError is an error reported by a Nurbs object through its
+// NURBS_ERROR callback.
+type NurbsError struct {
+	Code uint32
+}
+
+func (e *NurbsError) Error() string {
+	text, err := ErrorString(e.Code)
+	if err != nil {
+		text = fmt.Sprintf("NURBS error %d", e.Code)
+	}
+	return text
+}
+
+// tessErrors records the errors reported by a tesselator along with the
+// position in the polygon where they happened.
+type tessErrors struct {
+	contour    int // index of the current contour, -1 before the first
+	vertex     int // index of the vertex being added, -1 outside Vertex
+	nextVertex int
+
+	first error // first error since BeginPolygon
+	last  error // first error since the last call to take
+}
+
+func (e *tessErrors) reset() {
+	e.contour = -1
+	e.vertex = -1
+	e.nextVertex = 0
+	e.first = nil
+}
+
+func (e *tessErrors) beginContour() {
+	e.contour++
+	e.nextVertex = 0
+}
+
+func (e *tessErrors) beginVertex() {
+	e.vertex = e.nextVertex
+	e.nextVertex++
+}
+
+func (e *tessErrors) endVertex() {
+	e.vertex = -1
+}
+
+func (e *tessErrors) record(code uint32) {
+	err := &TessError{code, e.contour, e.vertex}
+	if e.first == nil {
+		e.first = err
+	}
+	if e.last == nil {
+		e.last = err
+	}
+}
+
+// take returns the first error recorded since it was last called.
+func (e *tessErrors) take() error {
+	err := e.last
+	e.last = nil
+	return err
+}
@@ -0,0 +1,96 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTessErrorCoordTooLarge(t *testing.T) {
+	tess := NewTess()
+	defer tess.Delete()
+
+	tess.BeginPolygon(nil)
+	tess.BeginContour()
+	tess.Vertex([3]float64{0, 0, 0}, nil)
+	tess.EndContour()
+	tess.BeginContour()
+	tess.Vertex([3]float64{0, 0, 0}, nil)
+	err := tess.VertexChecked([3]float64{1e200, 0, 0}, nil)
+	if !errors.Is(err, ErrTessCoordTooLarge) {
+		t.Errorf("Expected ErrTessCoordTooLarge from Vertex, got %v\n", err)
+	}
+	tess.EndContour()
+
+	err = tess.EndPolygon()
+	if !errors.Is(err, ErrTessCoordTooLarge) {
+		t.Fatalf("Expected ErrTessCoordTooLarge from EndPolygon, got %v\n", err)
+	}
+	var tessErr *TessError
+	if !errors.As(err, &tessErr) || tessErr.Contour != 1 || tessErr.Vertex != 1 {
+		t.Errorf("Expected error at contour 1, vertex 1, got %v\n", err)
+	}
+
+	// Errors do not carry over to the next polygon.
+	tess.BeginPolygon(nil)
+	if err := tess.EndPolygon(); err != nil {
+		t.Errorf("Expected no error, got %v\n", err)
+	}
+}
+
+func TestTessErrorMissingEndContour(t *testing.T) {
+	tess := NewTess()
+	defer tess.Delete()
+
+	tess.BeginPolygon(nil)
+	tess.BeginContour()
+	tess.Vertex([3]float64{0, 0, 0}, nil)
+	err := tess.EndPolygon()
+	if !errors.Is(err, ErrTessMissingEndContour) {
+		t.Errorf("Expected ErrTessMissingEndContour, got %v\n", err)
+	}
+	if errors.Is(err, ErrTessMissingBeginContour) {
+		t.Errorf("Error %v matches the wrong sentinel\n", err)
+	}
+}
+
+func TestTessErrorMissingBeginPolygon(t *testing.T) {
+	tess := NewTess()
+	defer tess.Delete()
+
+	// GLU reports this error without polygon data.
+	err := tess.BeginContourChecked()
+	if !errors.Is(err, ErrTessMissingBeginPolygon) {
+		t.Errorf("Expected ErrTessMissingBeginPolygon, got %v\n", err)
+	}
+	tess.Vertex([3]float64{0, 0, 0}, nil)
+	tess.Vertex([3]float64{1, 0, 0}, nil)
+	tess.Vertex([3]float64{0, 1, 0}, nil)
+	tess.EndContour()
+	if err := tess.EndPolygon(); !errors.Is(err, ErrTessMissingBeginPolygon) {
+		t.Errorf("Expected ErrTessMissingBeginPolygon from EndPolygon, got %v\n", err)
+	}
+}
+
+func TestTessErrorProperty(t *testing.T) {
+	tess := NewTess()
+	defer tess.Delete()
+
+	tess.BeginPolygon(nil)
+	err := tess.PropertyChecked(TESS_TOLERANCE, 42)
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Expected ErrInvalidValue, got %v\n", err)
+	}
+	tess.EndPolygon()
+}
+
+func TestTessErrorString(t *testing.T) {
+	err := &TessError{TESS_COORD_TOO_LARGE, 2, 3}
+	expected := "a coordinate is too large (contour 2, vertex 3)"
+	if err.Error() != expected {
+		t.Errorf("Expected %q, got %q\n", expected, err.Error())
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+)
+
+// Curve flattening. Each function appends points approximating a curve to
+// dst, leaving out its start point, so that no point of the curve is
+// farther than tolerance from the resulting polyline.
+
+// maxFlattenDepth bounds the recursive subdivision of Bezier curves, at
+// 2^16 segments per curve.
+const maxFlattenDepth = 16
+
+// flattenQuad appends the quadratic Bezier curve p0, p1, p2.
+func flattenQuad(dst [][2]float64, p0, p1, p2 [2]float64, tolerance float64) [][2]float64 {
+	// Raise the degree, as the cubic case has the flatness test.
+	c1 := [2]float64{p0[0] + 2*(p1[0]-p0[0])/3, p0[1] + 2*(p1[1]-p0[1])/3}
+	c2 := [2]float64{p2[0] + 2*(p1[0]-p2[0])/3, p2[1] + 2*(p1[1]-p2[1])/3}
+	return flattenCubic(dst, p0, c1, c2, p2, tolerance)
+}
+
+// flattenCubic appends the cubic Bezier curve p0, p1, p2, p3.
+func flattenCubic(dst [][2]float64, p0, p1, p2, p3 [2]float64, tolerance float64) [][2]float64 {
+	return flattenCubicDepth(dst, p0, p1, p2, p3, tolerance, 0)
+}
+
+func flattenCubicDepth(dst [][2]float64, p0, p1, p2, p3 [2]float64, tolerance float64, depth int) [][2]float64 {
+	// The curve lies in the hull of its control points, so it is flat
+	// enough when the inner control points are close to the chord.
+	if depth == maxFlattenDepth ||
+		(segmentDistance(p1, p0, p3) <= tolerance && segmentDistance(p2, p0, p3) <= tolerance) {
+		return append(dst, p3)
+	}
+
+	// Split at t = 0.5 with de Casteljau's algorithm.
+	p01 := midpoint(p0, p1)
+	p12 := midpoint(p1, p2)
+	p23 := midpoint(p2, p3)
+	p012 := midpoint(p01, p12)
+	p123 := midpoint(p12, p23)
+	m := midpoint(p012, p123)
+
+	dst = flattenCubicDepth(dst, p0, p01, p012, m, tolerance, depth+1)
+	return flattenCubicDepth(dst, m, p123, p23, p3, tolerance, depth+1)
+}
+
+// arcSegments returns the number of segments needed to approximate an arc
+// of a circle of the given radius spanning sweep radians.
+func arcSegments(radius, sweep, tolerance float64) int {
+	sweep = math.Abs(sweep)
+	// A chord spanning angle a deviates from the arc by r*(1-cos(a/2)).
+	step := math.Pi / 2
+	if tolerance < radius {
+		step = math.Min(step, 2*math.Acos(1-tolerance/radius))
+	}
+	n := math.Ceil(sweep / step)
+	if !(n <= 1<<maxFlattenDepth) {
+		return 1 << maxFlattenDepth
+	}
+	if n < 1 {
+		return 1
+	}
+	return int(n)
+}
+
+// appendArc appends n points evenly spaced along the arc of the ellipse
+// with center c, radii rx and ry and x axis rotated by phi, from angle
+// theta spanning sweep radians.
+func appendArc(dst [][2]float64, c [2]float64, rx, ry, phi, theta, sweep float64, n int) [][2]float64 {
+	sinPhi, cosPhi := math.Sincos(phi)
+	for i := 1; i <= n; i++ {
+		sin, cos := math.Sincos(theta + sweep*float64(i)/float64(n))
+		x, y := rx*cos, ry*sin
+		dst = append(dst, [2]float64{c[0] + cosPhi*x - sinPhi*y, c[1] + sinPhi*x + cosPhi*y})
+	}
+	return dst
+}
+
+// arcCenter converts an elliptical arc from p1 to p2 given in SVG endpoint
+// parameterization to center parameterization, as described in the SVG
+// specification, appendix F.6.5. Radii too small to reach p2 are scaled
+// up. It returns false if the arc is a straight line or empty.
+func arcCenter(p1, p2 [2]float64, rx, ry, phi float64, large, sweep bool) (c [2]float64, rx2, ry2, theta, delta float64, ok bool) {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if p1 == p2 || rx == 0 || ry == 0 {
+		return
+	}
+
+	sinPhi, cosPhi := math.Sincos(phi)
+	dx, dy := (p1[0]-p2[0])/2, (p1[1]-p2[1])/2
+	x1 := cosPhi*dx + sinPhi*dy
+	y1 := -sinPhi*dx + cosPhi*dy
+
+	if l := x1*x1/(rx*rx) + y1*y1/(ry*ry); l > 1 {
+		rx *= math.Sqrt(l)
+		ry *= math.Sqrt(l)
+	}
+
+	num := rx*rx*ry*ry - rx*rx*y1*y1 - ry*ry*x1*x1
+	den := rx*rx*y1*y1 + ry*ry*x1*x1
+	coef := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		coef = -coef
+	}
+	cx1 := coef * rx * y1 / ry
+	cy1 := -coef * ry * x1 / rx
+
+	c = [2]float64{
+		cosPhi*cx1 - sinPhi*cy1 + (p1[0]+p2[0])/2,
+		sinPhi*cx1 + cosPhi*cy1 + (p1[1]+p2[1])/2,
+	}
+	theta = math.Atan2((y1-cy1)/ry, (x1-cx1)/rx)
+	delta = math.Atan2((-y1-cy1)/ry, (-x1-cx1)/rx) - theta
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+	return c, rx, ry, theta, delta, true
+}
+
+func midpoint(a, b [2]float64) [2]float64 {
+	return [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+}
+
+// segmentDistance returns the distance from p to the segment ab.
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / l2
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
@@ -0,0 +1,212 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// glTF 2.0 constants.
+const (
+	gltfFloat        = 5126
+	gltfUnsignedInt  = 5125
+	gltfArrayBuffer  = 34962
+	gltfElementArray = 34963
+	gltfTriangles    = 4
+
+	glbMagic     = 0x46546C67 // "glTF"
+	glbChunkJSON = 0x4E4F534A // "JSON"
+	glbChunkBIN  = 0x004E4942 // "BIN\0"
+)
+
+// WriteGLTF writes the mesh as a glTF 2.0 asset made of a JSON document,
+// written to w, and a binary buffer, written to bin. The document refers
+// to the buffer by uri, the file name of bin relative to the document.
+// Values are stored in single precision. Texture coordinates are flipped
+// to the glTF convention of v pointing down the texture.
+func WriteGLTF(w, bin io.Writer, uri string, m *Mesh) error {
+	doc, data, err := gltfDocument(m, uri)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(doc); err != nil {
+		return err
+	}
+	_, err = bin.Write(data)
+	return err
+}
+
+// WriteGLB writes the mesh as a binary glTF 2.0 file, like WriteGLTF.
+func WriteGLB(w io.Writer, m *Mesh) error {
+	doc, data, err := gltfDocument(m, "")
+	if err != nil {
+		return err
+	}
+	// Chunks are padded to four bytes, the JSON chunk with spaces.
+	for len(doc)%4 != 0 {
+		doc = append(doc, ' ')
+	}
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+
+	var buf bytes.Buffer
+	header := []uint32{glbMagic, 2, uint32(12 + 8 + len(doc) + 8 + len(data))}
+	binary.Write(&buf, binary.LittleEndian, header)
+	binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(doc)), glbChunkJSON})
+	buf.Write(doc)
+	binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(data)), glbChunkBIN})
+	buf.Write(data)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+type gltfAsset struct {
+	Asset       map[string]string `json:"asset"`
+	Scene       int               `json:"scene"`
+	Scenes      []gltfScene       `json:"scenes"`
+	Nodes       []gltfNode        `json:"nodes"`
+	Meshes      []gltfMesh        `json:"meshes"`
+	Accessors   []gltfAccessor    `json:"accessors"`
+	BufferViews []gltfBufferView  `json:"bufferViews"`
+	Buffers     []gltfBuffer      `json:"buffers"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Mesh int `json:"mesh"`
+}
+
+type gltfMesh struct {
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Mode       int            `json:"mode"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri,omitempty"`
+}
+
+// gltfDocument returns the JSON document and binary buffer of a glTF
+// asset holding the mesh.
+func gltfDocument(m *Mesh, uri string) ([]byte, []byte, error) {
+	if err := m.validate(); err != nil {
+		return nil, nil, err
+	}
+	if len(m.Indices) == 0 {
+		// Empty buffers are not allowed.
+		return nil, nil, fmt.Errorf("Mesh has no triangles")
+	}
+
+	a := &gltfAsset{
+		Asset:  map[string]string{"version": "2.0", "generator": "glu"},
+		Scenes: []gltfScene{{Nodes: []int{0}}},
+		Nodes:  []gltfNode{{Mesh: 0}},
+	}
+	prim := gltfPrimitive{Attributes: make(map[string]int), Mode: gltfTriangles}
+	var data bytes.Buffer
+
+	// add appends a buffer view and an accessor for values, all of which
+	// take four bytes, and returns the index of the accessor.
+	add := func(values interface{}, count int, typ string, componentType, target int) int {
+		view := gltfBufferView{ByteOffset: data.Len(), Target: target}
+		binary.Write(&data, binary.LittleEndian, values)
+		view.ByteLength = data.Len() - view.ByteOffset
+		a.BufferViews = append(a.BufferViews, view)
+		a.Accessors = append(a.Accessors, gltfAccessor{
+			BufferView:    len(a.BufferViews) - 1,
+			ComponentType: componentType,
+			Count:         count,
+			Type:          typ,
+		})
+		return len(a.Accessors) - 1
+	}
+
+	n := len(m.Positions)
+	positions := make([][3]float32, n)
+	min := [3]float32{float32(math.Inf(1)), float32(math.Inf(1)), float32(math.Inf(1))}
+	max := [3]float32{float32(math.Inf(-1)), float32(math.Inf(-1)), float32(math.Inf(-1))}
+	for i, p := range m.Positions {
+		for k := range p {
+			v := float32(p[k])
+			positions[i][k] = v
+			if v < min[k] {
+				min[k] = v
+			}
+			if v > max[k] {
+				max[k] = v
+			}
+		}
+	}
+	prim.Attributes["POSITION"] = add(positions, n, "VEC3", gltfFloat, gltfArrayBuffer)
+	// Bounds are required for positions.
+	acc := &a.Accessors[prim.Attributes["POSITION"]]
+	acc.Min, acc.Max = min[:], max[:]
+
+	if len(m.Normals) > 0 {
+		normals := make([][3]float32, n)
+		for i, v := range m.Normals {
+			normals[i] = [3]float32{float32(v[0]), float32(v[1]), float32(v[2])}
+		}
+		prim.Attributes["NORMAL"] = add(normals, n, "VEC3", gltfFloat, gltfArrayBuffer)
+	}
+	if len(m.UVs) > 0 {
+		uvs := make([][2]float32, n)
+		for i, v := range m.UVs {
+			uvs[i] = [2]float32{float32(v[0]), float32(1 - v[1])}
+		}
+		prim.Attributes["TEXCOORD_0"] = add(uvs, n, "VEC2", gltfFloat, gltfArrayBuffer)
+	}
+	if len(m.Tangents) > 0 {
+		// Flipping t flips the bitangent too.
+		tangents := make([][4]float32, n)
+		for i, v := range m.Tangents {
+			tangents[i] = [4]float32{float32(v[0]), float32(v[1]), float32(v[2]), float32(-v[3])}
+		}
+		prim.Attributes["TANGENT"] = add(tangents, n, "VEC4", gltfFloat, gltfArrayBuffer)
+	}
+	if len(m.Colors) > 0 {
+		prim.Attributes["COLOR_0"] = add(m.Colors, n, "VEC4", gltfFloat, gltfArrayBuffer)
+	}
+	prim.Indices = add(m.Indices, len(m.Indices), "SCALAR", gltfUnsignedInt, gltfElementArray)
+
+	a.Meshes = []gltfMesh{{Primitives: []gltfPrimitive{prim}}}
+	a.Buffers = []gltfBuffer{{ByteLength: data.Len(), URI: uri}}
+
+	doc, err := json.Marshal(a)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, data.Bytes(), nil
+}
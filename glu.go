@@ -21,10 +21,6 @@ import (
 	"unsafe"
 )
 
-type Float interface {
-	~float64 | ~float32
-}
-
 func ptr(v interface{}) unsafe.Pointer {
 
 	if v == nil {
@@ -147,22 +143,42 @@ func Project(projX, projY, projZ float64, model, proj *[16]float64, view *[4]int
 	return float64(ox), float64(oy), float64(oz)
 }
 
+// NewQuadric creates a new quadric object for Sphere, Cylinder, Disk and
+// PartialDisk. Errors on it are dropped.
+//
+// Deprecated: Use NewQuadricObject, which can be deleted and configured.
 func NewQuadric() unsafe.Pointer {
 	return unsafe.Pointer(C.gluNewQuadric())
 }
 
+// Sphere draws a sphere with the quadric q.
+//
+// Deprecated: Use Quadric.Sphere.
 func Sphere(q unsafe.Pointer, radius float32, slices, stacks int) {
+	defer bindQuadric(q)()
 	C.gluSphere((*C.GLUquadric)(q), C.GLdouble(radius), C.GLint(slices), C.GLint(stacks))
 }
 
+// Cylinder draws a cylinder with the quadric q.
+//
+// Deprecated: Use Quadric.Cylinder.
 func Cylinder(q unsafe.Pointer, base, top, height float32, slices, stacks int) {
+	defer bindQuadric(q)()
 	C.gluCylinder((*C.GLUquadric)(q), C.GLdouble(base), C.GLdouble(top), C.GLdouble(height), C.GLint(slices), C.GLint(stacks))
 }
 
+// Disk draws a disk with the quadric q.
+//
+// Deprecated: Use Quadric.Disk.
 func Disk(q unsafe.Pointer, inner, outer float32, slices, loops int) {
+	defer bindQuadric(q)()
 	C.gluDisk((*C.GLUquadric)(q), C.GLdouble(inner), C.GLdouble(outer), C.GLint(slices), C.GLint(loops))
 }
 
+// PartialDisk draws a partial disk with the quadric q.
+//
+// Deprecated: Use Quadric.PartialDisk.
 func PartialDisk(q unsafe.Pointer, inner, outer float32, slices, loops int, startAngle, sweepAngle float32) {
+	defer bindQuadric(q)()
 	C.gluPartialDisk((*C.GLUquadric)(q), C.GLdouble(inner), C.GLdouble(outer), C.GLint(slices), C.GLint(loops), C.GLdouble(startAngle), C.GLdouble(sweepAngle))
 }
@@ -0,0 +1,43 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+
+package glu
+
+import (
+	"errors"
+)
+
+// errorStrings holds the strings libGLU returns from gluErrorString for
+// the codes the pure Go backend can report.
+var errorStrings = map[uint32]string{
+	0:      "no error",
+	0x0500: "invalid enumerant",
+	0x0501: "invalid value",
+	0x0502: "invalid operation",
+	0x0503: "stack overflow",
+	0x0504: "stack underflow",
+	0x0505: "out of memory",
+
+	INVALID_ENUM:      "invalid enumerant",
+	INVALID_VALUE:     "invalid value",
+	OUT_OF_MEMORY:     "out of memory",
+	INVALID_OPERATION: "invalid operation",
+
+	TESS_MISSING_BEGIN_POLYGON: "gluTessBeginPolygon() must precede a gluTessEndPolygon()",
+	TESS_MISSING_BEGIN_CONTOUR: "gluTessBeginContour() must precede a gluTessEndContour()",
+	TESS_MISSING_END_POLYGON:   "gluTessEndPolygon() must follow a gluTessBeginPolygon()",
+	TESS_MISSING_END_CONTOUR:   "gluTessEndContour() must follow a gluTessBeginContour()",
+	TESS_COORD_TOO_LARGE:       "a coordinate is too large",
+	TESS_NEED_COMBINE_CALLBACK: "need combine callback",
+}
+
+func ErrorString(error uint32) (string, error) {
+	e, ok := errorStrings[error]
+	if !ok {
+		return "", errors.New("Invalid GL error code")
+	}
+	return e, nil
+}
@@ -0,0 +1,22 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+// Tesselator callbacks, shared by the cgo and the pure Go backend.
+
+type TessBeginHandler func(tessType uint32, polygonData interface{})
+
+type TessVertexHandler func(vertexData interface{}, polygonData interface{})
+
+type TessEndHandler func(polygonData interface{})
+
+type TessErrorHandler func(errorNumber uint32, polygonData interface{})
+
+type TessEdgeFlagHandler func(flag bool, polygonData interface{})
+
+type TessCombineHandler func(coords [3]float64,
+	vertexData [4]interface{},
+	weight [4]float32,
+	polygonData interface{}) (outData interface{})
@@ -0,0 +1,149 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+)
+
+// ContourNode is a contour in the tree returned by ContourTree. Outer
+// contours are counterclockwise around the normal and holes clockwise.
+// The children of an outer contour are its holes, and the children of a
+// hole are the outer contours lying inside it.
+type ContourNode struct {
+	Contour  Contour
+	Hole     bool
+	Parent   *ContourNode
+	Children []*ContourNode
+}
+
+// ContourTree resolves the possibly overlapping contours under a
+// TESS_WINDING_* rule and returns the outer contours that lie in no hole,
+// with the contours nested in them as descendants. The normal is handed to
+// Tesselator.Normal; a zero normal is computed from the contours.
+func ContourTree(contours []Contour, windingRule uint32, normal [3]float64) ([]*ContourNode, error) {
+	if normal == [3]float64{} {
+		normal = contourNormal(contours)
+	}
+	if normal == [3]float64{} {
+		normal = [3]float64{0, 0, 1}
+	}
+
+	contours, err := tessellateBoundary(contours, windingRule, normal)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPlaneProjection(normal)
+	nodes := make([]*ContourNode, len(contours))
+	areas := make([]float64, len(contours))
+	for i, c := range contours {
+		areas[i] = p.area(c)
+		nodes[i] = &ContourNode{Contour: c, Hole: areas[i] < 0}
+	}
+
+	// The parent of a contour is the smallest contour of the opposite
+	// orientation containing it. Contours do not cross, so containment
+	// is decided by any vertex not lying on the other contour.
+	var roots []*ContourNode
+	for i, n := range nodes {
+		parent := -1
+		for j, m := range nodes {
+			if m.Hole == n.Hole || math.Abs(areas[j]) <= math.Abs(areas[i]) {
+				continue
+			}
+			if parent >= 0 && math.Abs(areas[j]) >= math.Abs(areas[parent]) {
+				continue
+			}
+			if p.contains(m.Contour, n.Contour) {
+				parent = j
+			}
+		}
+		if parent < 0 {
+			roots = append(roots, n)
+			continue
+		}
+		n.Parent = nodes[parent]
+		nodes[parent].Children = append(nodes[parent].Children, n)
+	}
+	return roots, nil
+}
+
+// planeProjection maps locations onto the coordinate plane most
+// perpendicular to a normal, keeping counterclockwise order around it.
+type planeProjection struct {
+	s, t int
+	sign float64
+}
+
+func newPlaneProjection(normal [3]float64) planeProjection {
+	i := 0
+	if math.Abs(normal[1]) > math.Abs(normal[0]) {
+		i = 1
+	}
+	if math.Abs(normal[2]) > math.Abs(normal[i]) {
+		i = 2
+	}
+	p := planeProjection{(i + 1) % 3, (i + 2) % 3, 1}
+	if normal[i] < 0 {
+		p.sign = -1
+	}
+	return p
+}
+
+func (p planeProjection) point(v [3]float64) (s, t float64) {
+	return v[p.s], p.sign * v[p.t]
+}
+
+// area returns the signed area of c, positive if c is counterclockwise.
+func (p planeProjection) area(c Contour) float64 {
+	area := 0.0
+	for i := range c {
+		s0, t0 := p.point(c[i])
+		s1, t1 := p.point(c[(i+1)%len(c)])
+		area += s0*t1 - s1*t0
+	}
+	return area / 2
+}
+
+// contains reports whether inner lies inside outer, given that the two
+// contours do not cross.
+func (p planeProjection) contains(outer, inner Contour) bool {
+	for _, v := range inner {
+		switch p.side(outer, v) {
+		case 1:
+			return true
+		case -1:
+			return false
+		}
+	}
+	// All vertices are on outer, so the contours are the same.
+	return false
+}
+
+// side returns 1 if v is inside c, -1 if it is outside and 0 if it lies on
+// its boundary.
+func (p planeProjection) side(c Contour, v [3]float64) int {
+	s, t := p.point(v)
+	inside := false
+	for i := range c {
+		s0, t0 := p.point(c[i])
+		s1, t1 := p.point(c[(i+1)%len(c)])
+
+		cross := (s1-s0)*(t-t0) - (t1-t0)*(s-s0)
+		if cross == 0 &&
+			math.Min(s0, s1) <= s && s <= math.Max(s0, s1) &&
+			math.Min(t0, t1) <= t && t <= math.Max(t0, t1) {
+			return 0
+		}
+		if (t0 > t) != (t1 > t) && s < s0+(t-t0)*(s1-s0)/(t1-t0) {
+			inside = !inside
+		}
+	}
+	if inside {
+		return 1
+	}
+	return -1
+}
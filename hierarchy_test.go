@@ -0,0 +1,75 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"testing"
+)
+
+func square(x, y, size float64) Contour {
+	return Contour{{x, y, 0}, {x + size, y, 0}, {x + size, y + size, 0}, {x, y + size, 0}}
+}
+
+func TestContourTree(t *testing.T) {
+	contours := []Contour{
+		square(0, 0, 10), // outer
+		square(1, 1, 8),  // hole in it
+		square(3, 3, 4),  // island in the hole
+		square(4, 4, 2),  // hole in the island
+		square(20, 0, 2), // separate outer
+	}
+
+	roots, err := ContourTree(contours, TESS_WINDING_ODD, [3]float64{0, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 2 {
+		t.Fatalf("Expected 2 roots, got %v\n", len(roots))
+	}
+
+	// Walk down the nested squares, checking orientation at each level.
+	n := roots[0]
+	if len(n.Children) == 0 {
+		n = roots[1]
+	}
+	for depth, size := range []float64{100, 64, 16, 4} {
+		area := contourArea([]Contour{n.Contour})
+		hole := depth%2 == 1
+		if hole {
+			area = -area
+		}
+		if n.Hole != hole || area != size {
+			t.Errorf("Depth %v: expected hole == %v and area %v, got %v and %v\n",
+				depth, hole, size, n.Hole, area)
+		}
+		if depth < 3 {
+			if len(n.Children) != 1 {
+				t.Fatalf("Depth %v: expected 1 child, got %v\n", depth, len(n.Children))
+			}
+			if n.Children[0].Parent != n {
+				t.Errorf("Depth %v: child does not link to its parent\n", depth)
+			}
+			n = n.Children[0]
+		} else if len(n.Children) != 0 {
+			t.Errorf("Depth %v: expected no children, got %v\n", depth, len(n.Children))
+		}
+	}
+}
+
+func TestContourTreeOverlap(t *testing.T) {
+	// Two overlapping squares merge into a single outer contour.
+	contours := []Contour{square(0, 0, 2), square(1, 1, 2)}
+
+	roots, err := ContourTree(contours, TESS_WINDING_NONZERO, [3]float64{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 1 || roots[0].Hole || len(roots[0].Children) != 0 {
+		t.Fatalf("Expected a single outer contour, got %v roots\n", len(roots))
+	}
+	if area := contourArea([]Contour{roots[0].Contour}); area != 7 {
+		t.Errorf("Expected area == 7, got %v\n", area)
+	}
+}
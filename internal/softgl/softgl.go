@@ -0,0 +1,254 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && cgo && softgl
+
+// Package softgl provides an offscreen OpenGL compatibility context for
+// tests, through EGL on Mesa's software rasterizer, and records what GLU
+// draws with it. It is only built with the softgl tag, so that the tests
+// of package glu need EGL only when asked to.
+package softgl
+
+// #cgo LDFLAGS: -lEGL -lGL
+//
+// #include <stdlib.h>
+// #include <EGL/egl.h>
+// #include <EGL/eglext.h>
+// #include <GL/gl.h>
+//
+// static EGLDisplay surfacelessDisplay(void) {
+// 	PFNEGLGETPLATFORMDISPLAYEXTPROC getPlatformDisplay =
+// 		(PFNEGLGETPLATFORMDISPLAYEXTPROC)eglGetProcAddress("eglGetPlatformDisplayEXT");
+// 	if (getPlatformDisplay == NULL) {
+// 		return EGL_NO_DISPLAY;
+// 	}
+// 	return getPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, EGL_DEFAULT_DISPLAY, NULL);
+// }
+//
+// static int makeContext(EGLDisplay display, EGLint width, EGLint height,
+//                        EGLSurface *surface, EGLContext *context) {
+// 	if (!eglInitialize(display, NULL, NULL) || !eglBindAPI(EGL_OPENGL_API)) {
+// 		return 0;
+// 	}
+// 	EGLint attribs[] = {
+// 		EGL_SURFACE_TYPE, EGL_PBUFFER_BIT,
+// 		EGL_RENDERABLE_TYPE, EGL_OPENGL_BIT,
+// 		EGL_NONE,
+// 	};
+// 	EGLConfig config;
+// 	EGLint count;
+// 	if (!eglChooseConfig(display, attribs, &config, 1, &count) || count == 0) {
+// 		return 0;
+// 	}
+// 	EGLint size[] = {EGL_WIDTH, width, EGL_HEIGHT, height, EGL_NONE};
+// 	*surface = eglCreatePbufferSurface(display, config, size);
+// 	*context = eglCreateContext(display, config, EGL_NO_CONTEXT, NULL);
+// 	if (*surface == EGL_NO_SURFACE || *context == EGL_NO_CONTEXT) {
+// 		return 0;
+// 	}
+// 	return eglMakeCurrent(display, *surface, *surface, *context);
+// }
+import "C"
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+// OpenGL enums used by the tests.
+const (
+	MODELVIEW         = C.GL_MODELVIEW
+	PROJECTION        = C.GL_PROJECTION
+	TEXTURE           = C.GL_TEXTURE
+	MODELVIEW_MATRIX  = C.GL_MODELVIEW_MATRIX
+	PROJECTION_MATRIX = C.GL_PROJECTION_MATRIX
+	TEXTURE_MATRIX    = C.GL_TEXTURE_MATRIX
+)
+
+// Context is a current OpenGL context with a pbuffer of its own.
+type Context struct {
+	display C.EGLDisplay
+	surface C.EGLSurface
+	context C.EGLContext
+	width   int
+	height  int
+}
+
+// New creates a context and makes it current. The calling goroutine is
+// locked to its thread until Delete is called.
+func New(width, height int) (*Context, error) {
+	runtime.LockOSThread()
+	c := &Context{display: C.surfacelessDisplay(), width: width, height: height}
+	if c.display == C.EGLDisplay(C.EGL_NO_DISPLAY) ||
+		C.makeContext(c.display, C.EGLint(width), C.EGLint(height), &c.surface, &c.context) == 0 {
+		C.eglTerminate(c.display)
+		runtime.UnlockOSThread()
+		return nil, errors.New("No EGL software rendering")
+	}
+	C.glViewport(0, 0, C.GLsizei(width), C.GLsizei(height))
+	return c, nil
+}
+
+// Delete releases the context.
+func (c *Context) Delete() {
+	C.eglMakeCurrent(c.display, nil, nil, nil)
+	C.eglDestroyContext(c.display, c.context)
+	C.eglDestroySurface(c.display, c.surface)
+	C.eglTerminate(c.display)
+	runtime.UnlockOSThread()
+}
+
+// LoadIdentity resets the matrix of the given mode.
+func (c *Context) LoadIdentity(mode uint32) {
+	C.glMatrixMode(C.GLenum(mode))
+	C.glLoadIdentity()
+}
+
+// Frustum multiplies the current matrix by a perspective projection.
+func (c *Context) Frustum(left, right, bottom, top, zNear, zFar float64) {
+	C.glFrustum(C.GLdouble(left), C.GLdouble(right), C.GLdouble(bottom), C.GLdouble(top), C.GLdouble(zNear), C.GLdouble(zFar))
+}
+
+// Ortho multiplies the current matrix by a parallel projection.
+func (c *Context) Ortho(left, right, bottom, top, zNear, zFar float64) {
+	C.glOrtho(C.GLdouble(left), C.GLdouble(right), C.GLdouble(bottom), C.GLdouble(top), C.GLdouble(zNear), C.GLdouble(zFar))
+}
+
+// MatrixMode selects the matrix the other matrix methods act on.
+func (c *Context) MatrixMode(mode uint32) {
+	C.glMatrixMode(C.GLenum(mode))
+}
+
+// PushMatrix pushes a copy of the current matrix.
+func (c *Context) PushMatrix() {
+	C.glPushMatrix()
+}
+
+// PopMatrix pops the current matrix.
+func (c *Context) PopMatrix() {
+	C.glPopMatrix()
+}
+
+// Translate multiplies the current matrix by a translation.
+func (c *Context) Translate(x, y, z float64) {
+	C.glTranslated(C.GLdouble(x), C.GLdouble(y), C.GLdouble(z))
+}
+
+// Rotate multiplies the current matrix by a rotation.
+func (c *Context) Rotate(angle, x, y, z float64) {
+	C.glRotated(C.GLdouble(angle), C.GLdouble(x), C.GLdouble(y), C.GLdouble(z))
+}
+
+// Scale multiplies the current matrix by a scaling.
+func (c *Context) Scale(x, y, z float64) {
+	C.glScaled(C.GLdouble(x), C.GLdouble(y), C.GLdouble(z))
+}
+
+// MultMatrix multiplies the current matrix by m.
+func (c *Context) MultMatrix(m *[16]float64) {
+	C.glMultMatrixd((*C.GLdouble)(unsafe.Pointer(&m[0])))
+}
+
+// Matrix returns the matrix named by pname, as glGetDoublev does.
+func (c *Context) Matrix(pname uint32) [16]float64 {
+	var m [16]float64
+	C.glGetDoublev(C.GLenum(pname), (*C.GLdouble)(unsafe.Pointer(&m[0])))
+	return m
+}
+
+// LightNormals turns lighting on or off. When on, the color of a vertex is
+// the positive part of sign times its normal, with x in red, y in green
+// and z in blue.
+func (c *Context) LightNormals(on bool, sign float32) {
+	if !on {
+		C.glDisable(C.GL_LIGHTING)
+		return
+	}
+	C.glMatrixMode(C.GL_MODELVIEW)
+	C.glPushMatrix()
+	C.glLoadIdentity()
+	black := [4]C.GLfloat{0, 0, 0, 1}
+	white := [4]C.GLfloat{1, 1, 1, 1}
+	C.glLightModelfv(C.GL_LIGHT_MODEL_AMBIENT, &black[0])
+	C.glMaterialfv(C.GL_FRONT_AND_BACK, C.GL_AMBIENT, &black[0])
+	C.glMaterialfv(C.GL_FRONT_AND_BACK, C.GL_SPECULAR, &black[0])
+	C.glMaterialfv(C.GL_FRONT_AND_BACK, C.GL_EMISSION, &black[0])
+	C.glMaterialfv(C.GL_FRONT_AND_BACK, C.GL_DIFFUSE, &white[0])
+	for i := 0; i < 3; i++ {
+		light := C.GLenum(C.GL_LIGHT0 + i)
+		var direction, color [4]C.GLfloat
+		direction[i] = C.GLfloat(sign)
+		color[i], color[3] = 1, 1
+		C.glLightfv(light, C.GL_POSITION, &direction[0])
+		C.glLightfv(light, C.GL_DIFFUSE, &color[0])
+		C.glLightfv(light, C.GL_AMBIENT, &black[0])
+		C.glLightfv(light, C.GL_SPECULAR, &black[0])
+		C.glEnable(light)
+	}
+	C.glEnable(C.GL_LIGHTING)
+	C.glPopMatrix()
+}
+
+// Vertex is a vertex recorded in feedback mode, in window coordinates.
+type Vertex struct {
+	Position [3]float64
+	Color    [4]float64
+	TexCoord [4]float64
+}
+
+// Feedback calls draw in feedback mode and returns the triangles it
+// produced, with the given number of values of room. Window coordinates
+// are those of the viewport covering the pbuffer.
+func (c *Context) Feedback(size int, draw func()) ([][3]Vertex, error) {
+	buf := (*C.GLfloat)(C.malloc(C.size_t(size) * C.sizeof_GLfloat))
+	defer C.free(unsafe.Pointer(buf))
+	C.glFeedbackBuffer(C.GLsizei(size), C.GL_3D_COLOR_TEXTURE, buf)
+	C.glRenderMode(C.GL_FEEDBACK)
+	draw()
+	n := int(C.glRenderMode(C.GL_RENDER))
+	if n < 0 {
+		return nil, errors.New("Feedback buffer overflow")
+	}
+	values := unsafe.Slice((*float32)(unsafe.Pointer(buf)), n)
+
+	var tris [][3]Vertex
+	vertex := func(v []float32) Vertex {
+		var out Vertex
+		for k := range out.Position {
+			out.Position[k] = float64(v[k])
+		}
+		for k := range out.Color {
+			out.Color[k] = float64(v[3+k])
+			out.TexCoord[k] = float64(v[7+k])
+		}
+		return out
+	}
+	const vertexSize = 11
+	for i := 0; i < len(values); {
+		token := int(values[i])
+		i++
+		switch token {
+		case C.GL_POLYGON_TOKEN:
+			count := int(values[i])
+			i++
+			var poly []Vertex
+			for k := 0; k < count; k++ {
+				poly = append(poly, vertex(values[i:i+vertexSize]))
+				i += vertexSize
+			}
+			for k := 1; k+1 < count; k++ {
+				tris = append(tris, [3]Vertex{poly[0], poly[k], poly[k+1]})
+			}
+		case C.GL_POINT_TOKEN, C.GL_BITMAP_TOKEN, C.GL_DRAW_PIXEL_TOKEN, C.GL_COPY_PIXEL_TOKEN:
+			i += vertexSize
+		case C.GL_LINE_TOKEN, C.GL_LINE_RESET_TOKEN:
+			i += 2 * vertexSize
+		case C.GL_PASS_THROUGH_TOKEN:
+			i++
+		default:
+			return nil, errors.New("Unknown feedback token")
+		}
+	}
+	return tris, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+// Interpolatable is implemented by vertex data that a Tesselator can blend
+// on its own. When no TessCombineHandler is set and the vertex data of all
+// combined vertices are Interpolatable, the data of the new vertex is
+// obtained by calling Interpolate on the first of them.
+//
+// vertices holds up to four source vertices, with weights summing to one.
+// Missing vertices are replaced by the first one, with a weight of zero.
+// Interpolate should return a value of the same type as the vertices.
+type Interpolatable interface {
+	Interpolate(location [3]float64, vertices [4]Interpolatable, weights [4]float32) Interpolatable
+}
+
+// AttribVertex is Interpolatable vertex data holding the location of a
+// vertex and attributes such as colors, texture coordinates or normals.
+// All vertices of a polygon should have the same number of attributes.
+type AttribVertex struct {
+	Location [3]float64
+	Attribs  []float32
+}
+
+// Interpolate returns a new *AttribVertex at location whose attributes are
+// the weighted sum of those of the vertices. Vertices that are not
+// *AttribVertex, or are nil, are left out of the sum, and only as many
+// attributes as the shortest Attribs of the others has are interpolated.
+// v itself is only used through vertices, and may be nil.
+func (v *AttribVertex) Interpolate(location [3]float64, vertices [4]Interpolatable, weights [4]float32) Interpolatable {
+	var sources [4]*AttribVertex
+	n := -1
+	for i, vertex := range vertices {
+		a, ok := vertex.(*AttribVertex)
+		if !ok || a == nil || weights[i] == 0 {
+			continue
+		}
+		sources[i] = a
+		if n < 0 || len(a.Attribs) < n {
+			n = len(a.Attribs)
+		}
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	out := &AttribVertex{
+		Location: location,
+		Attribs:  make([]float32, n),
+	}
+	for i, a := range sources {
+		if a == nil {
+			continue
+		}
+		for j := range out.Attribs {
+			out.Attribs[j] += weights[i] * a.Attribs[j]
+		}
+	}
+	return out
+}
+
+// interpolateVertexData combines vertex data for a Tesselator without a
+// TessCombineHandler. Missing entries of data must already be replaced by
+// data[0]. ok is false unless all data are Interpolatable.
+func interpolateVertexData(coords [3]float64, data [4]interface{}, weight [4]float32) (out interface{}, ok bool) {
+	var vertices [4]Interpolatable
+	for i, d := range data {
+		if vertices[i], ok = d.(Interpolatable); !ok {
+			return nil, false
+		}
+	}
+	return vertices[0].Interpolate(coords, vertices, weight), true
+}
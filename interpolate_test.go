@@ -0,0 +1,146 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTesselatorInterpolate(t *testing.T) {
+	// A bow tie, whose edges cross at (1, 1). The attribute is x + 10y,
+	// which is linear and so is exactly 11 at the crossing.
+	bowTie := [][3]float64{{0, 0, 0}, {2, 2, 0}, {2, 0, 0}, {0, 2, 0}}
+
+	var vertices []*AttribVertex
+
+	tess := NewTess()
+	defer tess.Delete()
+
+	tess.SetVertexCallback(func(vertexData interface{}, polygonData interface{}) {
+		vertices = append(vertices, vertexData.(*AttribVertex))
+	})
+	tess.Normal(0, 0, 1)
+
+	tess.BeginPolygon(nil)
+	tess.BeginContour()
+	for _, v := range bowTie {
+		tess.Vertex(v, &AttribVertex{Location: v, Attribs: []float32{float32(v[0] + 10*v[1])}})
+	}
+	tess.EndContour()
+	if err := tess.EndPolygon(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(vertices) != 6 {
+		t.Fatalf("Expected 6 vertices, got %v\n", len(vertices))
+	}
+	crossings := 0
+	for _, v := range vertices {
+		if v.Location == [3]float64{1, 1, 0} {
+			crossings++
+			if v.Attribs[0] != 11 {
+				t.Errorf("Expected attribute == 11, got %v\n", v.Attribs[0])
+			}
+		}
+	}
+	if crossings != 2 {
+		t.Errorf("Expected 2 vertices at the crossing, got %v\n", crossings)
+	}
+}
+
+func TestTesselatorNeedCombine(t *testing.T) {
+	tess := NewTess()
+	defer tess.Delete()
+
+	tess.Normal(0, 0, 1)
+
+	// Vertex data that cannot be interpolated still needs a handler.
+	tess.BeginPolygon(nil)
+	tess.BeginContour()
+	for i, v := range StarContour {
+		tess.Vertex(v, i)
+	}
+	tess.EndContour()
+	if err := tess.EndPolygon(); !errors.Is(err, ErrTessNeedCombineCallback) {
+		t.Errorf("Expected ErrTessNeedCombineCallback, got %v\n", err)
+	}
+}
+
+// otherVertex is Interpolatable vertex data of another type than
+// AttribVertex.
+type otherVertex struct{}
+
+func (otherVertex) Interpolate(location [3]float64, vertices [4]Interpolatable, weights [4]float32) Interpolatable {
+	return otherVertex{}
+}
+
+func TestAttribVertexInterpolateMixed(t *testing.T) {
+	a := &AttribVertex{Attribs: []float32{1, 2, 3}}
+	b := &AttribVertex{Attribs: []float32{3, 4}}
+	out := a.Interpolate([3]float64{1, 2, 3},
+		[4]Interpolatable{a, b, otherVertex{}, (*AttribVertex)(nil)},
+		[4]float32{0.25, 0.25, 0.25, 0.25}).(*AttribVertex)
+	if out.Location != [3]float64{1, 2, 3} || len(out.Attribs) != 2 || out.Attribs[0] != 1 || out.Attribs[1] != 1.5 {
+		t.Errorf("Expected attributes [1 1.5] at (1, 2, 3), got %v at %v\n", out.Attribs, out.Location)
+	}
+
+	// Attributes of different lengths meet where the bow tie crosses.
+	tess := NewTess()
+	defer tess.Delete()
+
+	var crossing *AttribVertex
+	tess.SetVertexCallback(func(vertexData interface{}, polygonData interface{}) {
+		if v := vertexData.(*AttribVertex); v.Location == [3]float64{1, 1, 0} {
+			crossing = v
+		}
+	})
+	tess.Normal(0, 0, 1)
+	tess.BeginPolygon(nil)
+	tess.BeginContour()
+	for i, v := range [][3]float64{{0, 0, 0}, {2, 2, 0}, {2, 0, 0}, {0, 2, 0}} {
+		tess.Vertex(v, &AttribVertex{Location: v, Attribs: make([]float32, 1+i%2)})
+	}
+	tess.EndContour()
+	if err := tess.EndPolygon(); err != nil {
+		t.Fatal(err)
+	}
+	if crossing == nil || len(crossing.Attribs) != 1 {
+		t.Errorf("Expected one attribute at the crossing, got %v\n", crossing)
+	}
+}
+
+func TestAttribVertexInterpolateNil(t *testing.T) {
+	var a *AttribVertex
+	b := &AttribVertex{Attribs: []float32{2, 4}}
+	out := a.Interpolate([3]float64{}, [4]Interpolatable{a, b, a, a}, [4]float32{0.5, 0.5, 0, 0}).(*AttribVertex)
+	if len(out.Attribs) != 2 || out.Attribs[0] != 1 || out.Attribs[1] != 2 {
+		t.Errorf("Expected attributes [1 2], got %v\n", out.Attribs)
+	}
+
+	// Vertex data that is a nil *AttribVertex is combined too.
+	tess := NewTess()
+	defer tess.Delete()
+
+	var crossing *AttribVertex
+	tess.SetVertexCallback(func(vertexData interface{}, polygonData interface{}) {
+		if v := vertexData.(*AttribVertex); v != nil {
+			crossing = v
+		}
+	})
+	tess.Normal(0, 0, 1)
+	tess.BeginPolygon(nil)
+	tess.BeginContour()
+	for _, v := range [][3]float64{{0, 0, 0}, {2, 2, 0}, {2, 0, 0}, {0, 2, 0}} {
+		tess.Vertex(v, a)
+	}
+	tess.EndContour()
+	if err := tess.EndPolygon(); err != nil {
+		t.Fatal(err)
+	}
+	if crossing == nil || crossing.Location != [3]float64{1, 1, 0} || len(crossing.Attribs) != 0 {
+		t.Errorf("Expected a vertex without attributes at the crossing, got %v\n", crossing)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+)
+
+type Float interface {
+	~float64 | ~float32
+}
+
+// The matrix builders return the matrices that Perspective, LookAt,
+// PickMatrix, gluOrtho2D and glFrustum multiply onto the current OpenGL
+// matrix, in the column-major order of glLoadMatrix and uniforms. They
+// repeat the arithmetic of GLU, and of Mesa for the parts computed by
+// OpenGL, in the precision GLU uses or else in that of T, so that with
+// float32 they give the values OpenGL stores. Where GLU or OpenGL would
+// reject the arguments and leave the matrix alone, they return the
+// identity.
+
+// PerspectiveMatrix returns the matrix of Perspective, for a field of view
+// of fovy degrees in y, a width of aspect times the height, and depths
+// from zNear to zFar.
+func PerspectiveMatrix[T Float](fovy, aspect, zNear, zFar T) [16]T {
+	// GLU works in double precision.
+	radians := float64(fovy) / 2 * math.Pi / 180
+	deltaZ := float64(zFar) - float64(zNear)
+	sine := math.Sin(radians)
+	if deltaZ == 0 || sine == 0 || aspect == 0 {
+		return identityMatrix[T]()
+	}
+	cotangent := math.Cos(radians) / sine
+
+	var m [16]T
+	m[0] = T(cotangent / float64(aspect))
+	m[5] = T(cotangent)
+	m[10] = T(-(float64(zFar) + float64(zNear)) / deltaZ)
+	m[11] = -1
+	m[14] = T(-2 * float64(zNear) * float64(zFar) / deltaZ)
+	return m
+}
+
+// LookAtMatrix returns the matrix of LookAt, which moves the eye to the
+// origin looking down the negative z axis, with up projected on the
+// positive y axis. Like GLU, it computes the rotation in single precision.
+func LookAtMatrix[T Float](eyeX, eyeY, eyeZ, centerX, centerY, centerZ, upX, upY, upZ T) [16]T {
+	m := lookAtRotation(eyeX, eyeY, eyeZ, centerX, centerY, centerZ, upX, upY, upZ)
+	translateMatrix(&m, T(-float64(eyeX)), T(-float64(eyeY)), T(-float64(eyeZ)))
+	return m
+}
+
+// lookAtRotation returns the rotation gluLookAt multiplies onto the
+// current matrix before it translates by the negated eye.
+func lookAtRotation[T Float](eyeX, eyeY, eyeZ, centerX, centerY, centerZ, upX, upY, upZ T) [16]T {
+	forward := [3]float32{
+		float32(float64(centerX) - float64(eyeX)),
+		float32(float64(centerY) - float64(eyeY)),
+		float32(float64(centerZ) - float64(eyeZ)),
+	}
+	up := [3]float32{float32(upX), float32(upY), float32(upZ)}
+	forward = normalize32(forward)
+	side := normalize32(cross32(forward, up))
+	up = cross32(side, forward)
+
+	m := identityMatrix[T]()
+	for k := 0; k < 3; k++ {
+		m[4*k] = T(side[k])
+		m[4*k+1] = T(up[k])
+		m[4*k+2] = T(-forward[k])
+	}
+	return m
+}
+
+// PickingMatrix returns the matrix of PickMatrix, which maps the region of
+// the viewport view of size delX by delY centered on x, y to the whole
+// viewport.
+func PickingMatrix[T Float](x, y, delX, delY T, view *[4]int32) [16]T {
+	if delX <= 0 || delY <= 0 {
+		return identityMatrix[T]()
+	}
+	m := identityMatrix[T]()
+	m[0] = T(float64(view[2]) / float64(delX))
+	m[5] = T(float64(view[3]) / float64(delY))
+	m[12] = T((float64(view[2]) - 2*(float64(x)-float64(view[0]))) / float64(delX))
+	m[13] = T((float64(view[3]) - 2*(float64(y)-float64(view[1]))) / float64(delY))
+	return m
+}
+
+// Ortho2DMatrix returns the matrix of gluOrtho2D, which maps the rectangle
+// from left, bottom to right, top to the viewport, for depths from -1 to
+// 1.
+func Ortho2DMatrix[T Float](left, right, bottom, top T) [16]T {
+	return orthoMatrix(left, right, bottom, top, -1, 1)
+}
+
+// FrustumMatrix returns the matrix of glFrustum, for the pyramid from the
+// eye through the rectangle from left, bottom to right, top in the plane
+// z = -zNear, cut at depths zNear and zFar.
+func FrustumMatrix[T Float](left, right, bottom, top, zNear, zFar T) [16]T {
+	if zNear <= 0 || zFar <= 0 || zNear == zFar || left == right || bottom == top {
+		return identityMatrix[T]()
+	}
+	var m [16]T
+	m[0] = 2 * zNear / (right - left)
+	m[5] = 2 * zNear / (top - bottom)
+	m[8] = (right + left) / (right - left)
+	m[9] = (top + bottom) / (top - bottom)
+	m[10] = -(zFar + zNear) / (zFar - zNear)
+	m[11] = -1
+	m[14] = -(2 * zFar * zNear) / (zFar - zNear)
+	return m
+}
+
+// orthoMatrix returns the matrix of glOrtho.
+func orthoMatrix[T Float](left, right, bottom, top, zNear, zFar T) [16]T {
+	if left == right || bottom == top || zNear == zFar {
+		return identityMatrix[T]()
+	}
+	m := identityMatrix[T]()
+	m[0] = 2 / (right - left)
+	m[5] = 2 / (top - bottom)
+	m[10] = -2 / (zFar - zNear)
+	m[12] = -(right + left) / (right - left)
+	m[13] = -(top + bottom) / (top - bottom)
+	m[14] = -(zFar + zNear) / (zFar - zNear)
+	return m
+}
+
+// translateMatrix multiplies m by a translation in place, in the order
+// Mesa's glTranslate adds the terms.
+func translateMatrix[T Float](m *[16]T, x, y, z T) {
+	for k := 0; k < 4; k++ {
+		m[12+k] = T(m[k]*x) + T(m[4+k]*y) + T(m[8+k]*z) + m[12+k]
+	}
+}
+
+func identityMatrix[T Float]() [16]T {
+	return [16]T{0: 1, 5: 1, 10: 1, 15: 1}
+}
+
+// normalize32 and cross32 are the single precision helpers of gluLookAt.
+// The conversions keep products from being fused with sums.
+
+func normalize32(v [3]float32) [3]float32 {
+	r := float32(math.Sqrt(float64(float32(v[0]*v[0]) + float32(v[1]*v[1]) + float32(v[2]*v[2]))))
+	if r == 0 {
+		return v
+	}
+	return [3]float32{v[0] / r, v[1] / r, v[2] / r}
+}
+
+func cross32(a, b [3]float32) [3]float32 {
+	return [3]float32{
+		float32(a[1]*b[2]) - float32(a[2]*b[1]),
+		float32(a[2]*b[0]) - float32(a[0]*b[2]),
+		float32(a[0]*b[1]) - float32(a[1]*b[0]),
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MatrixMode selects a matrix of a MatrixStack. The values are those of
+// the OpenGL enums.
+type MatrixMode uint32
+
+const (
+	MatrixModelView  MatrixMode = 0x1700 // GL_MODELVIEW
+	MatrixProjection MatrixMode = 0x1701 // GL_PROJECTION
+	MatrixTexture    MatrixMode = 0x1702 // GL_TEXTURE
+)
+
+// MatrixStack keeps the modelview, projection and texture matrix stacks of
+// fixed function OpenGL in memory, for code that builds its matrices with
+// glPushMatrix, glPopMatrix and GLU but draws with shaders, which take the
+// matrices from Top as uniforms. The methods act on the stack of the
+// current mode, like the OpenGL functions they are named after, and with
+// float32 compute the same matrices as Mesa.
+//
+// The zero value has an identity matrix on each stack and the modelview
+// mode selected. Stacks are not limited in depth.
+type MatrixStack[T Float] struct {
+	mode   int
+	stacks [3][][16]T
+}
+
+// SetMode selects the stack the other methods act on.
+func (s *MatrixStack[T]) SetMode(mode MatrixMode) error {
+	if mode < MatrixModelView || mode > MatrixTexture {
+		return fmt.Errorf("Invalid matrix mode %d", mode)
+	}
+	s.mode = int(mode - MatrixModelView)
+	return nil
+}
+
+// Mode returns the current mode.
+func (s *MatrixStack[T]) Mode() MatrixMode {
+	return MatrixModelView + MatrixMode(s.mode)
+}
+
+// Depth returns the number of matrices on the current stack.
+func (s *MatrixStack[T]) Depth() int {
+	s.top()
+	return len(s.stacks[s.mode])
+}
+
+// Top returns the current matrix, in column-major order.
+func (s *MatrixStack[T]) Top() [16]T {
+	return *s.top()
+}
+
+// Push pushes a copy of the current matrix.
+func (s *MatrixStack[T]) Push() {
+	m := *s.top()
+	s.stacks[s.mode] = append(s.stacks[s.mode], m)
+}
+
+// Pop pops the current matrix, making the one pushed with it current. It
+// fails if the stack holds a single matrix.
+func (s *MatrixStack[T]) Pop() error {
+	if s.Depth() == 1 {
+		return errors.New("Matrix stack underflow")
+	}
+	s.stacks[s.mode] = s.stacks[s.mode][:len(s.stacks[s.mode])-1]
+	return nil
+}
+
+// LoadIdentity replaces the current matrix with the identity.
+func (s *MatrixStack[T]) LoadIdentity() {
+	*s.top() = identityMatrix[T]()
+}
+
+// Load replaces the current matrix with m.
+func (s *MatrixStack[T]) Load(m [16]T) {
+	*s.top() = m
+}
+
+// Mult multiplies the current matrix by m on the right.
+func (s *MatrixStack[T]) Mult(m [16]T) {
+	top := s.top()
+	a := *top
+	for j := 0; j < 4; j++ {
+		for i := 0; i < 4; i++ {
+			top[4*j+i] = T(a[i]*m[4*j]) + T(a[4+i]*m[4*j+1]) + T(a[8+i]*m[4*j+2]) + T(a[12+i]*m[4*j+3])
+		}
+	}
+}
+
+// Translate multiplies the current matrix by a translation by x, y, z.
+func (s *MatrixStack[T]) Translate(x, y, z T) {
+	translateMatrix(s.top(), x, y, z)
+}
+
+// Rotate multiplies the current matrix by a counterclockwise rotation of
+// angle degrees about the axis x, y, z. An axis shorter than 1e-4 leaves
+// the matrix unchanged.
+func (s *MatrixStack[T]) Rotate(angle, x, y, z T) {
+	// Mesa rounds the angle in radians to single precision too.
+	sin, cos := math.Sincos(float64(T(float64(angle) * math.Pi / 180)))
+	sine, cosine := T(sin), T(cos)
+	m := identityMatrix[T]()
+	at := func(row, col int) *T { return &m[4*col+row] }
+
+	// Like Mesa, rotate about the coordinate axes without normalizing, so
+	// that the other axis stays exact.
+	axis, sign := -1, T(1)
+	switch {
+	case y == 0 && z == 0 && x != 0:
+		axis, sign = 0, x
+	case z == 0 && x == 0 && y != 0:
+		axis, sign = 1, y
+	case x == 0 && y == 0 && z != 0:
+		axis, sign = 2, z
+	}
+	if axis >= 0 {
+		a, b := (axis+1)%3, (axis+2)%3
+		*at(a, a), *at(b, b) = cosine, cosine
+		if sign < 0 {
+			*at(a, b), *at(b, a) = sine, -sine
+		} else {
+			*at(a, b), *at(b, a) = -sine, sine
+		}
+		s.Mult(m)
+		return
+	}
+
+	mag := T(math.Sqrt(float64(T(x*x) + T(y*y) + T(z*z))))
+	if mag <= 1e-4 {
+		return
+	}
+	x, y, z = x/mag, y/mag, z/mag
+	oneC := 1 - cosine
+	xy, yz, zx := T(oneC*T(x*y)), T(oneC*T(y*z)), T(oneC*T(z*x))
+	xs, ys, zs := T(x*sine), T(y*sine), T(z*sine)
+	*at(0, 0), *at(0, 1), *at(0, 2) = T(oneC*T(x*x))+cosine, xy-zs, zx+ys
+	*at(1, 0), *at(1, 1), *at(1, 2) = xy+zs, T(oneC*T(y*y))+cosine, yz-xs
+	*at(2, 0), *at(2, 1), *at(2, 2) = zx-ys, yz+xs, T(oneC*T(z*z))+cosine
+	s.Mult(m)
+}
+
+// Scale multiplies the current matrix by a scaling by x, y, z.
+func (s *MatrixStack[T]) Scale(x, y, z T) {
+	top := s.top()
+	for k := 0; k < 4; k++ {
+		top[k] *= x
+		top[4+k] *= y
+		top[8+k] *= z
+	}
+}
+
+// Perspective multiplies the current matrix by PerspectiveMatrix.
+func (s *MatrixStack[T]) Perspective(fovy, aspect, zNear, zFar T) {
+	s.Mult(PerspectiveMatrix(fovy, aspect, zNear, zFar))
+}
+
+// LookAt multiplies the current matrix by LookAtMatrix.
+func (s *MatrixStack[T]) LookAt(eyeX, eyeY, eyeZ, centerX, centerY, centerZ, upX, upY, upZ T) {
+	s.Mult(lookAtRotation(eyeX, eyeY, eyeZ, centerX, centerY, centerZ, upX, upY, upZ))
+	s.Translate(T(-float64(eyeX)), T(-float64(eyeY)), T(-float64(eyeZ)))
+}
+
+// PickMatrix multiplies the current matrix by PickingMatrix.
+func (s *MatrixStack[T]) PickMatrix(x, y, delX, delY T, view *[4]int32) {
+	s.Mult(PickingMatrix(x, y, delX, delY, view))
+}
+
+// Ortho2D multiplies the current matrix by Ortho2DMatrix.
+func (s *MatrixStack[T]) Ortho2D(left, right, bottom, top T) {
+	s.Mult(Ortho2DMatrix(left, right, bottom, top))
+}
+
+// top returns the current matrix, putting an identity matrix on an empty
+// stack first.
+func (s *MatrixStack[T]) top() *[16]T {
+	stack := &s.stacks[s.mode]
+	if len(*stack) == 0 {
+		*stack = append(*stack, identityMatrix[T]())
+	}
+	return &(*stack)[len(*stack)-1]
+}
@@ -0,0 +1,57 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrixStack(t *testing.T) {
+	var s MatrixStack[float32]
+	identity := identityMatrix[float32]()
+	if s.Mode() != MatrixModelView || s.Depth() != 1 || s.Top() != identity {
+		t.Fatalf("Expected the identity on the modelview stack, got %v in mode %#x with depth %d\n", s.Top(), s.Mode(), s.Depth())
+	}
+	if err := s.Pop(); err == nil {
+		t.Errorf("Expected an underflow\n")
+	}
+	if err := s.SetMode(MatrixTexture + 1); err == nil || s.Mode() != MatrixModelView {
+		t.Errorf("Expected an invalid mode to be rejected\n")
+	}
+
+	s.Translate(1, 2, 3)
+	s.Push()
+	s.Rotate(90, 0, 0, 1)
+	s.Scale(2, 2, 2)
+	if s.Depth() != 2 {
+		t.Errorf("Expected a depth of 2, got %d\n", s.Depth())
+	}
+	// The last transformation applies first.
+	m := s.Top()
+	p := [3]float32{1, 0, 0}
+	var q [3]float32
+	for k := range q {
+		q[k] = m[k]*p[0] + m[4+k]*p[1] + m[8+k]*p[2] + m[12+k]
+	}
+	if math.Abs(float64(q[0]-1)) > 1e-6 || q[1] != 4 || q[2] != 3 {
+		t.Errorf("Expected (1, 0, 0) to go to (1, 4, 3), got %v\n", q)
+	}
+
+	if err := s.SetMode(MatrixProjection); err != nil {
+		t.Fatal(err)
+	}
+	s.Ortho2D(0, 2, 0, 2)
+	if s.Depth() != 1 || s.Top() != Ortho2DMatrix[float32](0, 2, 0, 2) {
+		t.Errorf("Expected a projection stack of its own, got %v with depth %d\n", s.Top(), s.Depth())
+	}
+	s.SetMode(MatrixModelView)
+	if err := s.Pop(); err != nil {
+		t.Fatal(err)
+	}
+	if m := s.Top(); m[12] != 1 || m[13] != 2 || m[14] != 3 || m[0] != 1 {
+		t.Errorf("Expected the translation back, got %v\n", m)
+	}
+}
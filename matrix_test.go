@@ -0,0 +1,49 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrices(t *testing.T) {
+	transform := func(m [16]float64, p [3]float64) [4]float64 {
+		var q [4]float64
+		for k := range q {
+			q[k] = m[k]*p[0] + m[4+k]*p[1] + m[8+k]*p[2] + m[12+k]
+		}
+		return q
+	}
+	near := func(a, b [4]float64) bool {
+		for k := range a {
+			if math.Abs(a[k]-b[k]) > 1e-6 {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, test := range []struct {
+		name   string
+		m      [16]float64
+		p      [3]float64
+		expect [4]float64
+	}{
+		{"eye", LookAtMatrix(1.0, 2, 3, 1, 2, -7, 0, 1, 0), [3]float64{1, 2, 3}, [4]float64{0, 0, 0, 1}},
+		{"center", LookAtMatrix(1.0, 2, 3, 4, 6, 3, 0, 0, 1), [3]float64{4, 6, 3}, [4]float64{0, 0, -5, 1}},
+		{"up", LookAtMatrix(1.0, 2, 3, 4, 6, 3, 0, 0, 1), [3]float64{1, 2, 5}, [4]float64{0, 2, 0, 1}},
+		{"perspective near", PerspectiveMatrix(90, 2, 1, 10.0), [3]float64{2, 1, -1}, [4]float64{1, 1, -1, 1}},
+		{"perspective far", PerspectiveMatrix(90, 2, 1, 10.0), [3]float64{-20, 10, -10}, [4]float64{-10, 10, 10, 10}},
+		{"frustum", FrustumMatrix(-1, 3, 0, 2, 2, 4.0), [3]float64{3, 2, -2}, [4]float64{2, 2, -2, 2}},
+		{"ortho 2d", Ortho2DMatrix(0, 640, 480, 0.0), [3]float64{160, 120, 0}, [4]float64{-0.5, 0.5, 0, 1}},
+		{"pick", PickingMatrix(30, 40, 4, 8.0, &[4]int32{10, 20, 100, 200}), [3]float64{-0.64, -0.84, 0}, [4]float64{-1, -1, 0, 1}},
+		{"invalid frustum", FrustumMatrix(-1, 1, -1, 1, 0, 1.0), [3]float64{1, 2, 3}, [4]float64{1, 2, 3, 1}},
+	} {
+		if q := transform(test.m, test.p); !near(q, test.expect) {
+			t.Errorf("%s: expected %v to go to %v, got %v\n", test.name, test.p, test.expect, q)
+		}
+	}
+}
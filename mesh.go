@@ -0,0 +1,83 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"fmt"
+	"math"
+)
+
+// Mesh is an indexed triangle mesh. Every three consecutive entries of
+// Indices reference the Positions of one triangle.
+//
+// Normals, UVs, Tangents and Colors are optional. When present they hold
+// one entry per position. UVs follow the OpenGL convention of t pointing
+// up the texture. Tangents point along increasing s, with a fourth
+// component of 1 or -1 by which to multiply the cross product of normal
+// and tangent to get the direction of increasing t. Colors are RGBA in the
+// range 0 to 1.
+type Mesh struct {
+	Positions [][3]float64
+	Normals   [][3]float64
+	UVs       [][2]float64
+	Tangents  [][4]float64
+	Colors    [][4]float32
+	Indices   []uint32
+}
+
+// TriangleCount returns the number of triangles in the mesh.
+func (m *Mesh) TriangleCount() int {
+	return len(m.Indices) / 3
+}
+
+// Triangle returns the positions of the i-th triangle.
+func (m *Mesh) Triangle(i int) [3][3]float64 {
+	return [3][3]float64{
+		m.Positions[m.Indices[3*i]],
+		m.Positions[m.Indices[3*i+1]],
+		m.Positions[m.Indices[3*i+2]],
+	}
+}
+
+// validate checks that the attributes and indices are consistent, as
+// required before writing the mesh to a file.
+func (m *Mesh) validate() error {
+	n := len(m.Positions)
+	if len(m.Normals) != 0 && len(m.Normals) != n {
+		return fmt.Errorf("Mesh has %d normals for %d positions", len(m.Normals), n)
+	}
+	if len(m.UVs) != 0 && len(m.UVs) != n {
+		return fmt.Errorf("Mesh has %d UVs for %d positions", len(m.UVs), n)
+	}
+	if len(m.Tangents) != 0 && len(m.Tangents) != n {
+		return fmt.Errorf("Mesh has %d tangents for %d positions", len(m.Tangents), n)
+	}
+	if len(m.Colors) != 0 && len(m.Colors) != n {
+		return fmt.Errorf("Mesh has %d colors for %d positions", len(m.Colors), n)
+	}
+	if len(m.Indices)%3 != 0 {
+		return fmt.Errorf("Mesh has %d indices, not a multiple of 3", len(m.Indices))
+	}
+	for _, i := range m.Indices {
+		if int(i) >= n {
+			return fmt.Errorf("Mesh index %d out of range", i)
+		}
+	}
+	return nil
+}
+
+// faceNormal returns the unit normal of the i-th triangle, counterclockwise
+// facing, or zero for a degenerate triangle.
+func (m *Mesh) faceNormal(i int) [3]float64 {
+	t := m.Triangle(i)
+	u := [3]float64{t[1][0] - t[0][0], t[1][1] - t[0][1], t[1][2] - t[0][2]}
+	v := [3]float64{t[2][0] - t[0][0], t[2][1] - t[0][1], t[2][2] - t[0][2]}
+	n := [3]float64{u[1]*v[2] - u[2]*v[1], u[2]*v[0] - u[0]*v[2], u[0]*v[1] - u[1]*v[0]}
+	l := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])
+	if l == 0 {
+		return [3]float64{}
+	}
+	return [3]float64{n[0] / l, n[1] / l, n[2] / l}
+}
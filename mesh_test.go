@@ -0,0 +1,256 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testMesh returns a mesh of four triangles with all attributes.
+func testMesh() *Mesh {
+	return &Mesh{
+		Positions: [][3]float64{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}, {0.1, 0.2, 1.3}, {1e-9, -2, 3.25}},
+		Normals:   [][3]float64{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 0, 1}, {0, 1, 0}, {0, 1, 0}},
+		UVs:       [][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0.5, 0.25}, {0.75, 0.125}},
+		Colors:    [][4]float32{{1, 0, 0, 1}, {0, 1, 0, 1}, {0, 0, 1, 1}, {1, 1, 1, 1}, {0, 0, 0, 1}, {1, 1, 0, 1}},
+		Indices:   []uint32{0, 1, 2, 0, 2, 3, 3, 2, 4, 2, 5, 4},
+	}
+}
+
+// sameTriangles checks that b has the triangles of a, within tolerance.
+func sameTriangles(t *testing.T, name string, a, b *Mesh, tolerance float64) {
+	if a.TriangleCount() != b.TriangleCount() {
+		t.Errorf("%v: expected %v triangles, got %v\n", name, a.TriangleCount(), b.TriangleCount())
+		return
+	}
+	for i := 0; i < a.TriangleCount(); i++ {
+		ta, tb := a.Triangle(i), b.Triangle(i)
+		for j := range ta {
+			for k := range ta[j] {
+				if math.Abs(ta[j][k]-tb[j][k]) > tolerance {
+					t.Errorf("%v: triangle %v: expected %v, got %v\n", name, i, ta, tb)
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestMeshOBJ(t *testing.T) {
+	m := testMesh()
+	var buf bytes.Buffer
+	if err := WriteOBJ(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadOBJ(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Colors lose their alpha, which is read back as one.
+	if !reflect.DeepEqual(m, read) {
+		t.Errorf("Expected %v, got %v\n", m, read)
+	}
+
+	// Separate indices for each attribute, negative indices and quads.
+	obj := `# comment
+o quad
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0 1 0
+vt 0 0
+vt 1 1
+vn 0 0 1
+s off
+f 1/1/1 2/1/1 3/2/1 -1/2/-1
+`
+	read, err = ReadOBJ(strings.NewReader(obj))
+	if err != nil {
+		t.Fatal(err)
+	}
+	quad := &Mesh{
+		Positions: [][3]float64{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+		Indices:   []uint32{0, 1, 2, 0, 2, 3},
+	}
+	sameTriangles(t, "OBJ quad", quad, read, 0)
+	if len(read.UVs) != 4 || read.UVs[2] != [2]float64{1, 1} || len(read.Normals) != 4 {
+		t.Errorf("Unexpected attributes %v %v\n", read.UVs, read.Normals)
+	}
+
+	for _, obj := range []string{"v 1 2\n", "v 0 0 0\nf 1 2 3\n", "v 0 0 0\nf 1 1\n", "v a b c\n"} {
+		if _, err := ReadOBJ(strings.NewReader(obj)); err == nil {
+			t.Errorf("Expected an error reading %q\n", obj)
+		}
+	}
+}
+
+func TestMeshSTL(t *testing.T) {
+	m := testMesh()
+	positions := &Mesh{Positions: m.Positions, Indices: m.Indices}
+
+	var ascii, bin bytes.Buffer
+	if err := WriteSTL(&ascii, m); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSTLBinary(&bin, m); err != nil {
+		t.Fatal(err)
+	}
+	if n := bin.Len(); n != 84+50*m.TriangleCount() {
+		t.Errorf("Expected %v bytes, got %v\n", 84+50*m.TriangleCount(), n)
+	}
+	if !strings.Contains(ascii.String(), "facet normal 0 0 1\n") {
+		t.Errorf("Expected facet normals\n")
+	}
+
+	read, err := ReadSTL(&ascii)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(positions, read) {
+		t.Errorf("Expected %v, got %v\n", positions, read)
+	}
+	read, err = ReadSTL(&bin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameTriangles(t, "binary STL", positions, read, 1e-6)
+	if len(read.Positions) != len(m.Positions) {
+		t.Errorf("Expected shared vertices, got %v\n", len(read.Positions))
+	}
+
+	if _, err := ReadSTL(strings.NewReader("solid x\nvertex 1 2\n")); err == nil {
+		t.Errorf("Expected an error\n")
+	}
+}
+
+func TestMeshPLY(t *testing.T) {
+	m := testMesh()
+	for _, write := range []func(*bytes.Buffer, *Mesh) error{
+		func(b *bytes.Buffer, m *Mesh) error { return WritePLY(b, m) },
+		func(b *bytes.Buffer, m *Mesh) error { return WritePLYBinary(b, m) },
+	} {
+		var buf bytes.Buffer
+		if err := write(&buf, m); err != nil {
+			t.Fatal(err)
+		}
+		read, err := ReadPLY(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Positions are exact and the other attributes single precision.
+		if !reflect.DeepEqual(m.Positions, read.Positions) || !reflect.DeepEqual(m.Indices, read.Indices) {
+			t.Errorf("Expected %v, got %v\n", m, read)
+		}
+		if !reflect.DeepEqual(m.Normals, read.Normals) || !reflect.DeepEqual(m.UVs, read.UVs) ||
+			!reflect.DeepEqual(m.Colors, read.Colors) {
+			t.Errorf("Expected attributes %v, got %v\n", m, read)
+		}
+	}
+
+	// A big endian file with float colors, an extra element and a quad.
+	var buf bytes.Buffer
+	buf.WriteString("ply\nformat binary_big_endian 1.0\n" +
+		"element vertex 4\nproperty float x\nproperty float y\nproperty float z\n" +
+		"property float red\nproperty float green\nproperty float blue\n" +
+		"element face 1\nproperty list uchar int vertex_index\nproperty short flags\n" +
+		"element edge 1\nproperty int vertex1\nproperty int vertex2\nend_header\n")
+	for _, p := range [][3]float32{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}} {
+		binary.Write(&buf, binary.BigEndian, p)
+		binary.Write(&buf, binary.BigEndian, [3]float32{0.5, 0.5, 0.5})
+	}
+	binary.Write(&buf, binary.BigEndian, uint8(4))
+	binary.Write(&buf, binary.BigEndian, []int32{0, 1, 2, 3})
+	binary.Write(&buf, binary.BigEndian, int16(7))
+	binary.Write(&buf, binary.BigEndian, []int32{0, 1})
+	read, err := ReadPLY(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Indices, []uint32{0, 1, 2, 0, 2, 3}) ||
+		read.Colors[1] != [4]float32{0.5, 0.5, 0.5, 1} {
+		t.Errorf("Unexpected mesh %v\n", read)
+	}
+
+	for _, ply := range []string{
+		"ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nend_header\n0\n",
+		"ply\nformat ascii 1.0\nelement vertex 1\nproperty quad x\nend_header\n0\n",
+		"ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nproperty float y\nproperty float z\nend_header\n0 0 0\n",
+		"ply\nformat ascii 1.0\nelement vertex 0\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n3 0 1 2\n",
+	} {
+		if _, err := ReadPLY(strings.NewReader(ply)); err == nil {
+			t.Errorf("Expected an error reading %q\n", ply)
+		}
+	}
+}
+
+func TestMeshGLTF(t *testing.T) {
+	m := testMesh()
+	var doc, bin, glb bytes.Buffer
+	if err := WriteGLTF(&doc, &bin, "mesh.bin", m); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteGLB(&glb, m); err != nil {
+		t.Fatal(err)
+	}
+
+	var a gltfAsset
+	if err := json.Unmarshal(doc.Bytes(), &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.Buffers[0].URI != "mesh.bin" || a.Buffers[0].ByteLength != bin.Len() {
+		t.Errorf("Unexpected buffer %v\n", a.Buffers)
+	}
+	attrs := a.Meshes[0].Primitives[0].Attributes
+	if len(attrs) != 4 {
+		t.Errorf("Expected 4 attributes, got %v\n", attrs)
+	}
+	pos := a.Accessors[attrs["POSITION"]]
+	if pos.Count != 6 || !reflect.DeepEqual(pos.Min, []float32{0, -2, 0}) || !reflect.DeepEqual(pos.Max, []float32{1, 1, 3.25}) {
+		t.Errorf("Unexpected position accessor %v\n", pos)
+	}
+
+	// Read back the flipped texture coordinates and the indices.
+	view := a.BufferViews[a.Accessors[attrs["TEXCOORD_0"]].BufferView]
+	uvs := make([][2]float32, 6)
+	binary.Read(bytes.NewReader(bin.Bytes()[view.ByteOffset:]), binary.LittleEndian, uvs)
+	if uvs[4] != [2]float32{0.5, 0.75} {
+		t.Errorf("Expected a flipped UV, got %v\n", uvs[4])
+	}
+	view = a.BufferViews[a.Accessors[a.Meshes[0].Primitives[0].Indices].BufferView]
+	indices := make([]uint32, len(m.Indices))
+	binary.Read(bytes.NewReader(bin.Bytes()[view.ByteOffset:]), binary.LittleEndian, indices)
+	if !reflect.DeepEqual(indices, m.Indices) {
+		t.Errorf("Expected indices %v, got %v\n", m.Indices, indices)
+	}
+
+	// The GLB holds the same document and buffer, padded.
+	data := glb.Bytes()
+	var header [5]uint32
+	binary.Read(bytes.NewReader(data), binary.LittleEndian, &header)
+	if header[0] != glbMagic || header[1] != 2 || int(header[2]) != len(data) || header[4] != glbChunkJSON {
+		t.Fatalf("Unexpected GLB header %x\n", header)
+	}
+	jsonChunk := data[20 : 20+header[3]]
+	if !bytes.Equal(bytes.TrimRight(jsonChunk, " "), bytes.Replace(doc.Bytes(), []byte(`,"uri":"mesh.bin"`), nil, 1)) {
+		t.Errorf("Unexpected GLB JSON chunk %s\n", jsonChunk)
+	}
+	binChunk := data[20+header[3]+8:]
+	if !bytes.Equal(binChunk, bin.Bytes()) {
+		t.Errorf("Unexpected GLB binary chunk\n")
+	}
+
+	if err := WriteGLB(&glb, &Mesh{}); err == nil {
+		t.Errorf("Expected an error for an empty mesh\n")
+	}
+	if err := WriteGLB(&glb, &Mesh{Positions: m.Positions, Indices: []uint32{0, 1, 6}}); err == nil {
+		t.Errorf("Expected an error for an invalid index\n")
+	}
+}
@@ -9,13 +9,28 @@ package glu
 // #include <stdlib.h>
 // #include "callback.h"
 import "C"
-import "unsafe"
+import (
+	"runtime"
+	"runtime/cgo"
+	"unsafe"
+)
 
 // Nurbs holds the GLUnurbs object.
 type Nurbs struct {
 	nurbs *C.GLUnurbs
 	polyData interface{}
 
+	// handle identifies the object to callbacks, as GLU's user data.
+	handle cgo.Handle
+
+	// buffer records callbacks for NewBufferedNurbsRenderer, and is GLU's
+	// user data in its place.
+	buffer *C.gluEventBuffer
+
+	// rationalCurve is set while a curve of type MAP1_VERTEX_4 is defined,
+	// whose vertices GLU passes to callbacks in homogeneous coordinates.
+	rationalCurve bool
+
 	beginData    NurbsBeginDataHandler
 	vertexData   NurbsVertexDataHandler
 	normalData   NurbsNormalDataHandler
@@ -34,27 +49,37 @@ func NewNurbsRenderer() *Nurbs {
 	if n.nurbs == nil {
 		panic("Out of memory or GLU not initialized.")
 	}
+	n.handle = cgo.NewHandle(n)
+	C.setGluNurbsCallbackData(n.nurbs, C.uintptr_t(n.handle))
 
 	return n
 }
 
 // NurbsProperty sets a NURBS property.
 func (n *Nurbs) NurbsProperty(property uint32, value float32) {
+	defer n.bind()()
 	C.gluNurbsProperty(n.nurbs, C.GLenum(property), C.GLfloat(value))
 }
 
 // BeginSurface begins a NURBS surface definition.
 func (n *Nurbs) BeginSurface() {
+	defer n.bind()()
+	n.setRationalCurve(false)
 	C.gluBeginSurface(n.nurbs)
 }
 
 // EndSurface ends a NURBS surface definition.
 func (n *Nurbs) EndSurface() {
+	defer n.bind()()
 	C.gluEndSurface(n.nurbs)
+	if n.buffer != nil {
+		n.replayEvents()
+	}
 }
 
 // NurbsSurface defines a NURBS surface.
 func (n *Nurbs) NurbsSurface(sKnotCount int, sKnots []float32, tKnotCount int, tKnots []float32, sStride int, tStride int, ctlarray []float32, sOrder int, tOrder int, type0 uint32) {
+	defer n.bind()()
 	C.gluNurbsSurface(
 		n.nurbs,
 		C.GLint(sKnotCount),
@@ -70,34 +95,59 @@ func (n *Nurbs) NurbsSurface(sKnotCount int, sKnots []float32, tKnotCount int, t
 	)
 }
 
+// NurbsSurfaceChecked is like NurbsSurface, but first checks the knots,
+// orders, strides and map type against the slices and returns an error
+// instead of letting GLU read past them.
+func (n *Nurbs) NurbsSurfaceChecked(sKnotCount int, sKnots []float32, tKnotCount int, tKnots []float32, sStride int, tStride int, ctlarray []float32, sOrder int, tOrder int, type0 uint32) error {
+	err := checkNurbsSurface(sKnotCount, sKnots, tKnotCount, tKnots, sStride, tStride, ctlarray, sOrder, tOrder, type0)
+	if err != nil {
+		return err
+	}
+	n.NurbsSurface(sKnotCount, sKnots, tKnotCount, tKnots, sStride, tStride, ctlarray, sOrder, tOrder, type0)
+	return nil
+}
+
 // BeginCurve begins a NURBS curve definition.
 func (n *Nurbs) BeginCurve() {
+	defer n.bind()()
+	n.setRationalCurve(false)
 	C.gluBeginCurve(n.nurbs)
 }
 
 // BeginTrim begins a NURBS trim definition.
 func (n *Nurbs) BeginTrim() {
+	defer n.bind()()
 	C.gluBeginTrim(n.nurbs)
 }
 
 // Delete deletes the NURBS object.
 func (n *Nurbs) Delete() {
 	C.gluDeleteNurbsRenderer(n.nurbs)
+	if n.buffer != nil {
+		C.freeGluEventBuffer(n.buffer)
+	}
+	n.handle.Delete()
 	n.nurbs = nil
 }
 
 // EndCurve ends a NURBS curve definition.
 func (n *Nurbs) EndCurve() {
+	defer n.bind()()
 	C.gluEndCurve(n.nurbs)
+	if n.buffer != nil {
+		n.replayEvents()
+	}
 }
 
 // EndTrim ends a NURBS trim definition.
 func (n *Nurbs) EndTrim() {
+	defer n.bind()()
 	C.gluEndTrim(n.nurbs)
 }
 
 // GetNurbsProperty returns a NURBS property value.
 func (n *Nurbs) GetNurbsProperty(property uint32) float32 {
+	defer n.bind()()
 	var value C.GLfloat
 	C.gluGetNurbsProperty(n.nurbs, C.GLenum(property), &value)
 	return float32(value)
@@ -105,6 +155,7 @@ func (n *Nurbs) GetNurbsProperty(property uint32) float32 {
 
 // LoadSamplingMatrices loads the sampling matrices.
 func (n *Nurbs) LoadSamplingMatrices(model, perspective *[16]float32, view *[4]int32) {
+	defer n.bind()()
 	C.gluLoadSamplingMatrices(
 		n.nurbs,
 		(*C.GLfloat)(unsafe.Pointer(model)),
@@ -115,6 +166,13 @@ func (n *Nurbs) LoadSamplingMatrices(model, perspective *[16]float32, view *[4]i
 
 // NurbsCurve defines a NURBS curve.
 func (n *Nurbs) NurbsCurve(knotCount int, knots []float32, stride int, control []float32, order int, type0 uint32) {
+	defer n.bind()()
+	switch type0 {
+	case MAP1_VERTEX_3:
+		n.setRationalCurve(false)
+	case MAP1_VERTEX_4:
+		n.setRationalCurve(true)
+	}
 	C.gluNurbsCurve(
 		n.nurbs,
 		C.GLint(knotCount),
@@ -126,8 +184,20 @@ func (n *Nurbs) NurbsCurve(knotCount int, knots []float32, stride int, control [
 	)
 }
 
+// NurbsCurveChecked is like NurbsCurve, but first checks the knots,
+// order, stride and map type against the slices and returns an error
+// instead of letting GLU read past them.
+func (n *Nurbs) NurbsCurveChecked(knotCount int, knots []float32, stride int, control []float32, order int, type0 uint32) error {
+	if err := checkNurbsCurve(knotCount, knots, stride, control, order, type0); err != nil {
+		return err
+	}
+	n.NurbsCurve(knotCount, knots, stride, control, order, type0)
+	return nil
+}
+
 // PwlCurve defines a piecewise-linear curve.
 func (n *Nurbs) PwlCurve(count int, data []float32, stride int, type0 uint32) {
+	defer n.bind()()
 	C.gluPwlCurve(
 		n.nurbs,
 		C.GLint(count),
@@ -137,10 +207,42 @@ func (n *Nurbs) PwlCurve(count int, data []float32, stride int, type0 uint32) {
 	)
 }
 
+// PwlCurveChecked is like PwlCurve, but first checks the count, stride and
+// type against data and returns an error instead of letting GLU read past
+// it.
+func (n *Nurbs) PwlCurveChecked(count int, data []float32, stride int, type0 uint32) error {
+	if err := checkPwlCurve(count, data, stride, type0); err != nil {
+		return err
+	}
+	n.PwlCurve(count, data, stride, type0)
+	return nil
+}
+
+func (n *Nurbs) setRationalCurve(rational bool) {
+	n.rationalCurve = rational
+	if n.buffer != nil {
+		n.buffer.rational = 0
+		if rational {
+			n.buffer.rational = 1
+		}
+	}
+}
+
 // NurbsCallbackData sets the user data for the callbacks.
 func (n *Nurbs) NurbsCallbackData(userData interface{}) {
 	n.polyData = userData
-	C.gluNurbsCallbackData(n.nurbs, unsafe.Pointer(n))
+}
+
+// bind makes n the target of NURBS_ERROR callbacks, which GLU raises
+// without user data, until the returned function restores the previous
+// target. The calling goroutine stays on its thread in between.
+func (n *Nurbs) bind() func() {
+	runtime.LockOSThread()
+	previous := C.setGluNurbsErrorTarget(C.uintptr_t(n.handle))
+	return func() {
+		C.setGluNurbsErrorTarget(previous)
+		runtime.UnlockOSThread()
+	}
 }
 
 // SetBeginCallback sets the callback for NURBS_BEGIN_DATA.
@@ -149,7 +251,7 @@ func (n *Nurbs) SetBeginCallback(f NurbsBeginDataHandler) {
 		panic("Uninitialised Nurbs. @see glu.NewNurbsRenderer.")
 	}
 	n.beginData = f
-	C.setGluNurbsCallback(n.nurbs, C.GLenum(NURBS_BEGIN_DATA))
+	n.setCallback(NURBS_BEGIN_DATA)
 }
 
 // SetVertexCallback sets the callback for NURBS_VERTEX_DATA.
@@ -158,7 +260,7 @@ func (n *Nurbs) SetVertexCallback(f NurbsVertexDataHandler) {
 		panic("Uninitialised Nurbs. @see glu.NewNurbsRenderer.")
 	}
 	n.vertexData = f
-	C.setGluNurbsCallback(n.nurbs, C.GLenum(NURBS_VERTEX_DATA))
+	n.setCallback(NURBS_VERTEX_DATA)
 }
 
 // SetNormalCallback sets the callback for NURBS_NORMAL_DATA.
@@ -167,7 +269,7 @@ func (n *Nurbs) SetNormalCallback(f NurbsNormalDataHandler) {
 		panic("Uninitialised Nurbs. @see glu.NewNurbsRenderer.")
 	}
 	n.normalData = f
-	C.setGluNurbsCallback(n.nurbs, C.GLenum(NURBS_NORMAL_DATA))
+	n.setCallback(NURBS_NORMAL_DATA)
 }
 
 // SetColorCallback sets the callback for NURBS_COLOR_DATA.
@@ -176,7 +278,7 @@ func (n *Nurbs) SetColorCallback(f NurbsColorDataHandler) {
 		panic("Uninitialised Nurbs. @see glu.NewNurbsRenderer.")
 	}
 	n.colorData = f
-	C.setGluNurbsCallback(n.nurbs, C.GLenum(NURBS_COLOR_DATA))
+	n.setCallback(NURBS_COLOR_DATA)
 }
 
 // SetTextureCoordCallback sets the callback for NURBS_TEXTURE_COORD_DATA.
@@ -185,7 +287,7 @@ func (n *Nurbs) SetTextureCoordCallback(f NurbsTextureCoordDataHandler) {
 		panic("Uninitialised Nurbs. @see glu.NewNurbsRenderer.")
 	}
 	n.textureCoordData = f
-	C.setGluNurbsCallback(n.nurbs, C.GLenum(NURBS_TEXTURE_COORD_DATA))
+	n.setCallback(NURBS_TEXTURE_COORD_DATA)
 }
 
 // SetEndCallback sets the callback for NURBS_END_DATA.
@@ -194,7 +296,7 @@ func (n *Nurbs) SetEndCallback(f NurbsEndDataHandler) {
 		panic("Uninitialised Nurbs. @see glu.NewNurbsRenderer.")
 	}
 	n.endData = f
-	C.setGluNurbsCallback(n.nurbs, C.GLenum(NURBS_END_DATA))
+	n.setCallback(NURBS_END_DATA)
 }
 
 // SetErrorCallback sets the callback for NURBS_ERROR.
@@ -203,5 +305,5 @@ func (n *Nurbs) SetErrorCallback(f NurbsErrorHandler) {
 		panic("Uninitialised Nurbs. @see glu.NewNurbsRenderer.")
 	}
 	n.errorData = f
-	C.setGluNurbsCallback(n.nurbs, C.GLenum(NURBS_ERROR))
+	n.setCallback(NURBS_ERROR)
 }
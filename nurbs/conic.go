@@ -0,0 +1,79 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"math"
+)
+
+// Circle returns the circle of the given center and radius in the plane
+// z = center[2], counterclockwise from the positive x axis.
+func Circle[T Float](center [3]T, radius T) *Curve[T] {
+	return Ellipse(center, radius, radius)
+}
+
+// Ellipse returns the ellipse of the given center and radii along x and y
+// in the plane z = center[2], counterclockwise from the positive x axis.
+func Ellipse[T Float](center [3]T, rx, ry T) *Curve[T] {
+	return EllipticalArc(center, [3]T{1, 0, 0}, [3]T{0, 1, 0}, rx, ry, 0, 2*math.Pi)
+}
+
+// Arc returns the arc of the circle of the given center and radius in the
+// plane z = center[2] from angle start to angle end, in radians from the
+// positive x axis. The arc is clockwise if end is less than start.
+func Arc[T Float](center [3]T, radius, start, end T) *Curve[T] {
+	return EllipticalArc(center, [3]T{1, 0, 0}, [3]T{0, 1, 0}, radius, radius, start, end)
+}
+
+// EllipticalArc returns the points center + rx cos(a) xAxis + ry sin(a)
+// yAxis for angles a from start to end, in radians, where xAxis and yAxis
+// are orthogonal unit vectors. Sweeps beyond a full turn are cut to one.
+//
+// The result is an exact rational quadratic curve of type MAP1_VERTEX_4,
+// with one segment per quarter turn or less and a parameter domain of
+// [0, 1]. This is algorithm A7.1 of The NURBS Book.
+func EllipticalArc[T Float](center, xAxis, yAxis [3]T, rx, ry, start, end T) *Curve[T] {
+	a0 := float64(start)
+	sweep := math.Max(-2*math.Pi, math.Min(2*math.Pi, float64(end-start)))
+	arcs := int(math.Ceil(math.Abs(sweep)/(math.Pi/2) - 1e-9))
+	if arcs < 1 {
+		arcs = 1
+	}
+	step := sweep / float64(arcs)
+	w := math.Cos(step / 2)
+
+	// point returns the point at angle a, scaled away from the center by
+	// 1/scale.
+	point := func(a, scale float64) [3]float64 {
+		x, y := float64(rx)*math.Cos(a)/scale, float64(ry)*math.Sin(a)/scale
+		var p [3]float64
+		for k := range p {
+			p[k] = float64(center[k]) + x*float64(xAxis[k]) + y*float64(yAxis[k])
+		}
+		return p
+	}
+
+	c := &Curve[T]{Stride: 4, Order: 3, Type: map1Vertex4}
+	c.Knots = append(c.Knots, 0, 0, 0)
+	c.Control = appendHomogeneous(c.Control, point(a0, 1), 1)
+	for i := 1; i <= arcs; i++ {
+		a := a0 + float64(i)*step
+		c.Control = appendHomogeneous(c.Control, point(a-step/2, w), w)
+		c.Control = appendHomogeneous(c.Control, point(a, 1), 1)
+		u := T(float64(i) / float64(arcs))
+		if i == arcs {
+			u = 1
+		}
+		c.Knots = append(c.Knots, u, u)
+	}
+	c.Knots = append(c.Knots, 1)
+	return c
+}
+
+// appendHomogeneous appends the point p with weight w in homogeneous
+// coordinates.
+func appendHomogeneous[T Float](dst []T, p [3]float64, w float64) []T {
+	return append(dst, T(p[0]*w), T(p[1]*w), T(p[2]*w), T(w))
+}
@@ -0,0 +1,112 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEllipticalArc(t *testing.T) {
+	center := [3]float64{1, 2, 3}
+	// An ellipse tilted about the x axis.
+	xAxis := [3]float64{1, 0, 0}
+	yAxis := [3]float64{0, math.Sqrt2 / 2, math.Sqrt2 / 2}
+
+	tests := []struct {
+		start, end float64
+		segments   int
+	}{
+		{0, 2 * math.Pi, 4},
+		{0, math.Pi / 2, 1},
+		{0.3, 0.4, 1},
+		{-1, 2, 2},
+		{2, -1, 2},
+		{0, 7, 4},
+	}
+	for _, test := range tests {
+		c := EllipticalArc(center, xAxis, yAxis, 3, 2, test.start, test.end)
+		if err := c.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if n := (len(c.Knots) - c.Order - 1) / 2; n != test.segments {
+			t.Errorf("%v to %v: expected %v segments, got %v\n", test.start, test.end, test.segments, n)
+		}
+		sweep := math.Max(-2*math.Pi, math.Min(2*math.Pi, test.end-test.start))
+		for _, a := range []float64{test.start, test.start + sweep} {
+			expected := [3]float64{1 + 3*math.Cos(a), 2 + 2*math.Sin(a)*yAxis[1], 3 + 2*math.Sin(a)*yAxis[2]}
+			u := 0.0
+			if a != test.start {
+				u = 1
+			}
+			if p := c.Point(u); !near(p, expected, 1e-12) {
+				t.Errorf("%v to %v: expected %v at %v, got %v\n", test.start, test.end, expected, u, p)
+			}
+		}
+
+		// Every point lies on the ellipse, in order of angle.
+		prev := test.start
+		for i := 0; i <= 100; i++ {
+			p := c.Point(float64(i) / 100)
+			x := (p[0] - 1) / 3
+			y := ((p[1]-2)*yAxis[1] + (p[2]-3)*yAxis[2]) / 2
+			if d := (p[1] - 2) - (p[2] - 3); math.Abs(d) > 1e-12 {
+				t.Errorf("Expected a point in the plane of the ellipse, got %v\n", p)
+			}
+			if r := math.Hypot(x, y); math.Abs(r-1) > 1e-12 {
+				t.Errorf("Expected a point on the ellipse, got %v\n", p)
+			}
+			a := math.Atan2(y, x)
+			a += 2 * math.Pi * math.Round((prev-a)/(2*math.Pi))
+			if (sweep > 0 && a < prev-1e-12) || (sweep < 0 && a > prev+1e-12) {
+				t.Errorf("Expected angles in order, got %v after %v\n", a, prev)
+			}
+			prev = a
+		}
+	}
+}
+
+func TestCircle(t *testing.T) {
+	c := Circle([3]float32{1, -1, 0.5}, 2)
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Type != map1Vertex4 || c.Stride != 4 {
+		t.Errorf("Expected a rational curve, got type 0x%X\n", c.Type)
+	}
+	for i := 0; i <= 64; i++ {
+		p := c.Point(float32(i) / 64)
+		r := math.Hypot(float64(p[0]-1), float64(p[1]+1))
+		if math.Abs(r-2) > 1e-5 || p[2] != 0.5 {
+			t.Errorf("Expected a point on the circle, got %v\n", p)
+		}
+	}
+
+	// The tangent turns counterclockwise.
+	if d := c.Tangent(0); !near([3]float64{float64(d[0]), float64(d[1]), float64(d[2])}, [3]float64{0, 1, 0}, 1e-6) {
+		t.Errorf("Expected a counterclockwise circle, got tangent %v\n", d)
+	}
+}
+
+func TestEllipseAndArc(t *testing.T) {
+	e := Ellipse([3]float64{}, 4, 1)
+	for i := 0; i <= 64; i++ {
+		p := e.Point(float64(i) / 64)
+		if v := p[0]*p[0]/16 + p[1]*p[1]; math.Abs(v-1) > 1e-12 {
+			t.Errorf("Expected a point on the ellipse, got %v\n", p)
+		}
+	}
+
+	a := Arc([3]float64{}, 1, math.Pi, math.Pi/2)
+	if p := a.Point(0); !near(p, [3]float64{-1, 0, 0}, 1e-12) {
+		t.Errorf("Expected the arc to start at (-1, 0), got %v\n", p)
+	}
+	if p := a.Point(1); !near(p, [3]float64{0, 1, 0}, 1e-12) {
+		t.Errorf("Expected the arc to end at (0, 1), got %v\n", p)
+	}
+	if p := a.Point(0.5); p[0] >= 0 || p[1] <= 0 {
+		t.Errorf("Expected a clockwise arc, got %v halfway\n", p)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"fmt"
+	"math"
+)
+
+// Curve is a NURBS curve. Control point i starts at Control[i*Stride], and
+// there are len(Knots)-Order of them.
+type Curve[T Float] struct {
+	Knots   []T
+	Stride  int
+	Control []T
+	Order   int
+	Type    uint32 // a MAP1_* type
+}
+
+// Validate checks that the knots, stride, control points and order are
+// consistent with each other and with the map type.
+func (c *Curve[T]) Validate() error {
+	dim := dimension(c.Type)
+	if dim == 0 {
+		return fmt.Errorf("Invalid curve type 0x%X", c.Type)
+	}
+	n, err := validateKnots(c.Knots, c.Order, "")
+	if err != nil {
+		return err
+	}
+	if c.Stride < dim {
+		return fmt.Errorf("Invalid stride %d for %d coordinates", c.Stride, dim)
+	}
+	if need := (n-1)*c.Stride + dim; len(c.Control) < need {
+		return fmt.Errorf("Insufficient control data: %d values for %d control points", len(c.Control), n)
+	}
+	return nil
+}
+
+// Domain returns the parameter range of the curve.
+func (c *Curve[T]) Domain() (T, T) {
+	return c.Knots[c.Order-1], c.Knots[len(c.Knots)-c.Order]
+}
+
+// Evaluate returns the coordinates of the curve and of its first n
+// derivatives at u, indexed by derivative and coordinate. Coordinates of
+// a rational curve are homogeneous. Parameters outside the domain are
+// clamped to it. The curve must be valid.
+func (c *Curve[T]) Evaluate(u T, n int) [][]T {
+	return convert[T](c.evaluate(u, n))
+}
+
+func (c *Curve[T]) evaluate(u T, n int) [][]float64 {
+	dim := dimension(c.Type)
+	knots := toFloat64(c.Knots)
+	count, p := len(knots)-c.Order, c.Order-1
+	x := clamp(float64(u), knots[p], knots[count])
+	span := findSpan(knots, count, p, x)
+	basis := basisDerivs(knots, span, p, x, n)
+
+	ders := make([][]float64, n+1)
+	for k := range ders {
+		ders[k] = make([]float64, dim)
+		for j, b := range basis[k] {
+			cp := c.Control[(span-p+j)*c.Stride:]
+			for i := range ders[k] {
+				ders[k][i] += b * float64(cp[i])
+			}
+		}
+	}
+	return ders
+}
+
+// Point returns the point of a MAP1_VERTEX_3 or MAP1_VERTEX_4 curve at u.
+func (c *Curve[T]) Point(u T) [3]T {
+	return vec[T](c.vertexDerivs(u, 0)[0])
+}
+
+// Derivatives returns the point and the first and second derivatives of a
+// MAP1_VERTEX_3 or MAP1_VERTEX_4 curve at u.
+func (c *Curve[T]) Derivatives(u T) (p, d1, d2 [3]T) {
+	ders := c.vertexDerivs(u, 2)
+	return vec[T](ders[0]), vec[T](ders[1]), vec[T](ders[2])
+}
+
+// Tangent returns the unit tangent of a MAP1_VERTEX_3 or MAP1_VERTEX_4
+// curve at u, or zero where the derivative vanishes.
+func (c *Curve[T]) Tangent(u T) [3]T {
+	return vec[T](normalize(c.vertexDerivs(u, 1)[1]))
+}
+
+// vertexDerivs returns the Euclidean point and first n derivatives.
+func (c *Curve[T]) vertexDerivs(u T, n int) [][3]float64 {
+	ders := c.evaluate(u, n)
+	if c.Type != map1Vertex4 {
+		out := make([][3]float64, n+1)
+		for k := range out {
+			copy(out[k][:], ders[k])
+		}
+		return out
+	}
+	return rationalDerivs(ders)
+}
+
+// rationalDerivs returns the derivatives of the projection of the
+// homogeneous curve derivatives ders, as in algorithm A4.2 of The NURBS
+// Book.
+func rationalDerivs(ders [][]float64) [][3]float64 {
+	out := make([][3]float64, len(ders))
+	for k := range ders {
+		v := [3]float64{ders[k][0], ders[k][1], ders[k][2]}
+		for i := 1; i <= k; i++ {
+			b := binomial(k, i) * ders[i][3]
+			for j := range v {
+				v[j] -= b * out[k-i][j]
+			}
+		}
+		for j := range v {
+			out[k][j] = v[j] / ders[0][3]
+		}
+	}
+	return out
+}
+
+func convert[T Float](ders [][]float64) [][]T {
+	out := make([][]T, len(ders))
+	for k, d := range ders {
+		out[k] = make([]T, len(d))
+		for i, x := range d {
+			out[k][i] = T(x)
+		}
+	}
+	return out
+}
+
+func vec[T Float](v [3]float64) [3]T {
+	return [3]T{T(v[0]), T(v[1]), T(v[2])}
+}
+
+func clamp(x, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, x))
+}
+
+func normalize(v [3]float64) [3]float64 {
+	l := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	if l == 0 {
+		return v
+	}
+	return [3]float64{v[0] / l, v[1] / l, v[2] / l}
+}
@@ -0,0 +1,164 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"math"
+	"testing"
+)
+
+// quarterCircle is a rational quadratic from (1, 0) to (0, 1).
+var quarterCircle = Curve[float64]{
+	Knots:  []float64{0, 0, 0, 1, 1, 1},
+	Stride: 4,
+	Control: []float64{
+		1, 0, 0, 1,
+		math.Sqrt2 / 2, math.Sqrt2 / 2, 0, math.Sqrt2 / 2,
+		0, 1, 0, 1,
+	},
+	Order: 3,
+	Type:  map1Vertex4,
+}
+
+func near(a, b [3]float64, tolerance float64) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// checkCurveDerivatives compares the derivatives of c with central
+// differences.
+func checkCurveDerivatives(t *testing.T, c *Curve[float64], u float64) {
+	const h = 1e-5
+	_, d1, d2 := c.Derivatives(u)
+	p0, p1 := c.Point(u-h), c.Point(u+h)
+	_, d1a, _ := c.Derivatives(u - h)
+	_, d1b, _ := c.Derivatives(u + h)
+	var fd1, fd2 [3]float64
+	for i := range fd1 {
+		fd1[i] = (p1[i] - p0[i]) / (2 * h)
+		fd2[i] = (d1b[i] - d1a[i]) / (2 * h)
+	}
+	if !near(d1, fd1, 1e-6) || !near(d2, fd2, 1e-5) {
+		t.Errorf("At %v: expected derivatives %v %v, got %v %v\n", u, fd1, fd2, d1, d2)
+	}
+}
+
+func TestCurveBezier(t *testing.T) {
+	p := [4][3]float64{{0, 0, 0}, {1, 2, 0}, {3, 2, 1}, {4, 0, -1}}
+	c := &Curve[float64]{
+		Knots:   []float64{0, 0, 0, 0, 1, 1, 1, 1},
+		Stride:  3,
+		Control: []float64{0, 0, 0, 1, 2, 0, 3, 2, 1, 4, 0, -1},
+		Order:   4,
+		Type:    map1Vertex3,
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []float64{0, 0.25, 0.5, 0.9, 1} {
+		s := 1 - u
+		var b, d1, d2 [3]float64
+		for i := range b {
+			b[i] = s*s*s*p[0][i] + 3*s*s*u*p[1][i] + 3*s*u*u*p[2][i] + u*u*u*p[3][i]
+			d1[i] = 3 * (s*s*(p[1][i]-p[0][i]) + 2*s*u*(p[2][i]-p[1][i]) + u*u*(p[3][i]-p[2][i]))
+			d2[i] = 6 * (s*(p[2][i]-2*p[1][i]+p[0][i]) + u*(p[3][i]-2*p[2][i]+p[1][i]))
+		}
+		pt, e1, e2 := c.Derivatives(u)
+		if !near(pt, b, 1e-12) || !near(e1, d1, 1e-12) || !near(e2, d2, 1e-12) {
+			t.Errorf("At %v: expected %v %v %v, got %v %v %v\n", u, b, d1, d2, pt, e1, e2)
+		}
+	}
+}
+
+func TestCurveRational(t *testing.T) {
+	c := &quarterCircle
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i <= 20; i++ {
+		u := float64(i) / 20
+		p := c.Point(u)
+		if r := math.Hypot(p[0], p[1]); math.Abs(r-1) > 1e-15 {
+			t.Errorf("At %v: expected radius 1, got %v\n", u, r)
+		}
+		tan := c.Tangent(u)
+		if d := tan[0]*p[0] + tan[1]*p[1]; math.Abs(d) > 1e-12 {
+			t.Errorf("At %v: expected a tangent perpendicular to the radius, got %v\n", u, tan)
+		}
+		if u > 0 && u < 1 {
+			checkCurveDerivatives(t, c, u)
+		}
+	}
+
+	// The same circle in single precision.
+	c32 := &Curve[float32]{Knots: []float32{0, 0, 0, 1, 1, 1}, Stride: 4, Order: 3, Type: map1Vertex4}
+	for _, v := range quarterCircle.Control {
+		c32.Control = append(c32.Control, float32(v))
+	}
+	p := c32.Point(0.3)
+	if r := math.Hypot(float64(p[0]), float64(p[1])); math.Abs(r-1) > 1e-6 {
+		t.Errorf("Expected radius 1, got %v\n", r)
+	}
+	if h := c32.Evaluate(0.5, 1); len(h) != 2 || len(h[0]) != 4 {
+		t.Errorf("Expected homogeneous coordinates, got %v\n", h)
+	}
+}
+
+func TestCurveSpans(t *testing.T) {
+	c := &Curve[float64]{
+		Knots:   []float64{0, 0, 0, 0, 1, 2, 2, 3, 3, 3, 3},
+		Stride:  5,
+		Control: make([]float64, 7*5),
+		Order:   4,
+		Type:    map1Vertex3,
+	}
+	for i := 0; i < 7; i++ {
+		copy(c.Control[i*5:], []float64{float64(i), float64(i * i % 5), 1})
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if lo, hi := c.Domain(); lo != 0 || hi != 3 {
+		t.Errorf("Expected domain 0, 3, got %v, %v\n", lo, hi)
+	}
+	for _, u := range []float64{0.1, 0.5, 1, 1.5, 2.5, 2.9} {
+		// The basis functions sum to one.
+		if p := c.Point(u); math.Abs(p[2]-1) > 1e-12 {
+			t.Errorf("At %v: expected z 1, got %v\n", u, p[2])
+		}
+		if u != 1 && u != 2 {
+			checkCurveDerivatives(t, c, u)
+		}
+	}
+	// Parameters outside the domain are clamped.
+	if p := c.Point(3); p != [3]float64{6, 1, 1} {
+		t.Errorf("Expected the last control point, got %v\n", p)
+	}
+	if p := c.Point(-1); p != [3]float64{0, 0, 1} {
+		t.Errorf("Expected the first control point, got %v\n", p)
+	}
+}
+
+func TestCurveValidate(t *testing.T) {
+	valid := quarterCircle
+	for _, c := range []Curve[float64]{
+		{Knots: valid.Knots, Stride: 4, Control: valid.Control, Order: 3, Type: 0},
+		{Knots: valid.Knots, Stride: 4, Control: valid.Control, Order: 0, Type: map1Vertex4},
+		{Knots: valid.Knots[:5], Stride: 4, Control: valid.Control, Order: 3, Type: map1Vertex4},
+		{Knots: []float64{0, 0, 1, 0, 1, 1}, Stride: 4, Control: valid.Control, Order: 3, Type: map1Vertex4},
+		{Knots: []float64{0, 0, 0, 0, 0, 0}, Stride: 4, Control: valid.Control, Order: 3, Type: map1Vertex4},
+		{Knots: valid.Knots, Stride: 3, Control: valid.Control, Order: 3, Type: map1Vertex4},
+		{Knots: valid.Knots, Stride: 4, Control: valid.Control[:11], Order: 3, Type: map1Vertex4},
+		{},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected an error for %v\n", c)
+		}
+	}
+}
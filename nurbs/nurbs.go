@@ -0,0 +1,194 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package nurbs evaluates NURBS curves and surfaces in pure Go. Curves and
+// surfaces use the layout of knots, strides, control points, orders and
+// map types taken by glu.Nurbs.NurbsCurve and glu.Nurbs.NurbsSurface.
+// Maps of type MAP1_VERTEX_4 and MAP2_VERTEX_4 are rational, with control
+// points given in homogeneous coordinates (wx, wy, wz, w). Circle, Sphere
+// and the other constructors build exact rational conics and surfaces of
+// revolution in that form.
+package nurbs
+
+import (
+	"fmt"
+)
+
+type Float interface {
+	~float64 | ~float32
+}
+
+// Map types, as defined by OpenGL. Each MAP2_* type follows the matching
+// MAP1_* type by mapSurfaceDistance.
+const (
+	map1Color4         = 0x0D90
+	map1Index          = 0x0D91
+	map1Normal         = 0x0D92
+	map1TextureCoord1  = 0x0D93
+	map1TextureCoord4  = 0x0D96
+	map1Vertex3        = 0x0D97
+	map1Vertex4        = 0x0D98
+	map2Vertex3        = 0x0DB7
+	map2Vertex4        = 0x0DB8
+	mapSurfaceDistance = 0x20
+)
+
+// dimension returns the number of coordinates of a control point of the
+// MAP1_* type, or zero for an unknown type.
+func dimension(typ uint32) int {
+	switch {
+	case typ == map1Color4:
+		return 4
+	case typ == map1Index:
+		return 1
+	case typ == map1Normal, typ == map1Vertex3:
+		return 3
+	case typ >= map1TextureCoord1 && typ <= map1TextureCoord4:
+		return int(typ-map1TextureCoord1) + 1
+	case typ == map1Vertex4:
+		return 4
+	}
+	return 0
+}
+
+// validateKnots checks a knot vector for the given order and returns the
+// number of control points it requires.
+func validateKnots[T Float](knots []T, order int, dir string) (int, error) {
+	if order < 1 {
+		return 0, fmt.Errorf("Invalid %sorder %d", dir, order)
+	}
+	n := len(knots) - order
+	if n < order {
+		return 0, fmt.Errorf("Insufficient number of %sknots: %d for order %d", dir, len(knots), order)
+	}
+	for i := 1; i < len(knots); i++ {
+		if knots[i] < knots[i-1] {
+			return 0, fmt.Errorf("Decreasing %sknot %v at index %d", dir, knots[i], i)
+		}
+	}
+	if !(knots[order-1] < knots[n]) {
+		return 0, fmt.Errorf("Empty %sparameter domain", dir)
+	}
+	return n, nil
+}
+
+// findSpan returns the index i of the knot span [knots[i], knots[i+1])
+// holding u, for n control points of degree p. The first or last span of
+// the domain is returned for its end points.
+func findSpan(knots []float64, n, p int, u float64) int {
+	if u >= knots[n] {
+		i := n - 1
+		for i > p && knots[i] == knots[i+1] {
+			i--
+		}
+		return i
+	}
+	if u <= knots[p] {
+		i := p
+		for i < n-1 && knots[i] == knots[i+1] {
+			i++
+		}
+		return i
+	}
+	lo, hi := p, n
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if u < knots[mid] {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return lo
+}
+
+// basisDerivs returns the values and first n derivatives of the p+1 basis
+// functions of degree p which are nonzero in the given span, at u. This is
+// algorithm A2.3 of The NURBS Book.
+func basisDerivs(knots []float64, span, p int, u float64, n int) [][]float64 {
+	ders := make([][]float64, n+1)
+	for k := range ders {
+		ders[k] = make([]float64, p+1)
+	}
+
+	ndu := make([][]float64, p+1)
+	for j := range ndu {
+		ndu[j] = make([]float64, p+1)
+	}
+	left := make([]float64, p+1)
+	right := make([]float64, p+1)
+	ndu[0][0] = 1
+	for j := 1; j <= p; j++ {
+		left[j] = u - knots[span+1-j]
+		right[j] = knots[span+j] - u
+		saved := 0.0
+		for r := 0; r < j; r++ {
+			// Lower triangle holds knot differences.
+			ndu[j][r] = right[r+1] + left[j-r]
+			temp := ndu[r][j-1] / ndu[j][r]
+			ndu[r][j] = saved + right[r+1]*temp
+			saved = left[j-r] * temp
+		}
+		ndu[j][j] = saved
+	}
+	for j := 0; j <= p; j++ {
+		ders[0][j] = ndu[j][p]
+	}
+
+	a := [2][]float64{make([]float64, p+1), make([]float64, p+1)}
+	for r := 0; r <= p; r++ {
+		s1, s2 := 0, 1
+		a[0][0] = 1
+		for k := 1; k <= n && k <= p; k++ {
+			d := 0.0
+			rk, pk := r-k, p-k
+			if r >= k {
+				a[s2][0] = a[s1][0] / ndu[pk+1][rk]
+				d = a[s2][0] * ndu[rk][pk]
+			}
+			j1, j2 := 1, k-1
+			if rk < -1 {
+				j1 = -rk
+			}
+			if r-1 > pk {
+				j2 = p - r
+			}
+			for j := j1; j <= j2; j++ {
+				a[s2][j] = (a[s1][j] - a[s1][j-1]) / ndu[pk+1][rk+j]
+				d += a[s2][j] * ndu[rk+j][pk]
+			}
+			if r <= pk {
+				a[s2][k] = -a[s1][k-1] / ndu[pk+1][r]
+				d += a[s2][k] * ndu[r][pk]
+			}
+			ders[k][r] = d
+			s1, s2 = s2, s1
+		}
+	}
+
+	f := float64(p)
+	for k := 1; k <= n && k <= p; k++ {
+		for j := range ders[k] {
+			ders[k][j] *= f
+		}
+		f *= float64(p - k)
+	}
+	return ders
+}
+
+func toFloat64[T Float](v []T) []float64 {
+	out := make([]float64, len(v))
+	for i, x := range v {
+		out[i] = float64(x)
+	}
+	return out
+}
+
+func binomial(n, k int) float64 {
+	b := 1.0
+	for i := 1; i <= k; i++ {
+		b = b * float64(n-k+i) / float64(i)
+	}
+	return b
+}
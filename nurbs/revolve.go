@@ -0,0 +1,146 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"fmt"
+	"math"
+)
+
+// Revolve returns the surface swept by turning the profile curve, of type
+// MAP1_VERTEX_3 or MAP1_VERTEX_4, by angle radians counterclockwise
+// around the line through origin along axis. The surface is an exact
+// rational surface of type MAP2_VERTEX_4, with s running around the axis
+// over [0, 1] and t along the profile. Normals point away from the axis
+// where the profile runs in the direction of axis. This is algorithm A8.1
+// of The NURBS Book.
+func Revolve[T Float](profile *Curve[T], origin, axis [3]T, angle T) (*Surface[T], error) {
+	if profile.Type != map1Vertex3 && profile.Type != map1Vertex4 {
+		return nil, fmt.Errorf("Invalid profile type 0x%X", profile.Type)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	a := normalize(toArray(axis[:]))
+	if a == [3]float64{} {
+		return nil, fmt.Errorf("Invalid axis %v", axis)
+	}
+	o := toArray(origin[:])
+
+	// The circle through (1, 0) in the x, y plane gives the weights and the
+	// coordinates of the control points around the axis.
+	circle := EllipticalArc[float64]([3]float64{}, [3]float64{1, 0, 0}, [3]float64{0, 1, 0}, 1, 1, 0, float64(angle))
+	nc := len(circle.Control) / 4
+	np := len(profile.Knots) - profile.Order
+
+	s := &Surface[T]{
+		SKnots:  convertKnots[T](circle.Knots),
+		TKnots:  append([]T(nil), profile.Knots...),
+		SStride: 4 * np,
+		TStride: 4,
+		Control: make([]T, 4*np*nc),
+		SOrder:  circle.Order,
+		TOrder:  profile.Order,
+		Type:    map2Vertex4,
+	}
+	for i := 0; i < np; i++ {
+		p := toArray(profile.Control[i*profile.Stride:])
+		wp := 1.0
+		if profile.Type == map1Vertex4 {
+			wp = float64(profile.Control[i*profile.Stride+3])
+			for k := range p {
+				p[k] /= wp
+			}
+		}
+		// Project p on the axis, and take the frame of its circle.
+		var d [3]float64
+		for k := range d {
+			d[k] = p[k] - o[k]
+		}
+		h := dot(d, a)
+		var c, x [3]float64
+		for k := range c {
+			c[k] = o[k] + h*a[k]
+			x[k] = p[k] - c[k]
+		}
+		y := cross(a, x)
+
+		for j := 0; j < nc; j++ {
+			q := circle.Control[4*j : 4*j+4]
+			wc := q[3]
+			w := wp * wc
+			dst := s.Control[j*s.SStride+i*s.TStride:]
+			for k := range c {
+				// The circle's control points are homogeneous.
+				dst[k] = T(w * (c[k] + (q[0]*x[k]+q[1]*y[k])/wc))
+			}
+			dst[3] = T(w)
+		}
+	}
+	return s, nil
+}
+
+// Sphere returns the sphere of the given center and radius. Its seam is
+// in the half plane y = center[1], x > center[0], and its poles lie on the
+// vertical through the center. Normals point outward.
+func Sphere[T Float](center [3]T, radius T) *Surface[T] {
+	profile := EllipticalArc(center, [3]T{1, 0, 0}, [3]T{0, 0, 1}, radius, radius, -math.Pi/2, math.Pi/2)
+	s, _ := Revolve(profile, center, [3]T{0, 0, 1}, 2*math.Pi)
+	return s
+}
+
+// Cylinder returns the side of the cylinder of the given radius and
+// height standing on the circle around center in the plane z = center[2].
+// Normals point outward.
+func Cylinder[T Float](center [3]T, radius, height T) *Surface[T] {
+	return Cone(center, radius, radius, height)
+}
+
+// Cone returns the side of the truncated cone of the given height standing
+// on the circle of radius baseRadius around center in the plane
+// z = center[2], with a top of radius topRadius. A zero topRadius gives a
+// full cone. Normals point outward.
+func Cone[T Float](center [3]T, baseRadius, topRadius, height T) *Surface[T] {
+	x, y, z := center[0], center[1], center[2]
+	profile := &Curve[T]{
+		Knots:   []T{0, 0, 1, 1},
+		Stride:  3,
+		Control: []T{x + baseRadius, y, z, x + topRadius, y, z + height},
+		Order:   2,
+		Type:    map1Vertex3,
+	}
+	s, _ := Revolve(profile, center, [3]T{0, 0, 1}, 2*math.Pi)
+	return s
+}
+
+// Torus returns the torus around the vertical through center, with the
+// given distance from center to the middle of the tube and radius of the
+// tube. Normals point outward.
+func Torus[T Float](center [3]T, majorRadius, minorRadius T) *Surface[T] {
+	middle := [3]T{center[0] + majorRadius, center[1], center[2]}
+	profile := EllipticalArc(middle, [3]T{1, 0, 0}, [3]T{0, 0, 1}, minorRadius, minorRadius, 0, 2*math.Pi)
+	s, _ := Revolve(profile, center, [3]T{0, 0, 1}, 2*math.Pi)
+	return s
+}
+
+func convertKnots[T Float](knots []float64) []T {
+	out := make([]T, len(knots))
+	for i, k := range knots {
+		out[i] = T(k)
+	}
+	return out
+}
+
+func toArray[T Float](v []T) [3]float64 {
+	return [3]float64{float64(v[0]), float64(v[1]), float64(v[2])}
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
@@ -0,0 +1,144 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"math"
+	"testing"
+)
+
+// checkSurface samples s over its domain and checks that distance, which
+// is zero on the expected shape, is within tolerance of zero, and that
+// the normals agree with outward, which returns the expected direction.
+func checkSurface(t *testing.T, name string, s *Surface[float64], distance func(p [3]float64) float64, outward func(p [3]float64) [3]float64) {
+	t.Helper()
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Type != map2Vertex4 {
+		t.Errorf("%v: expected a rational surface, got type 0x%X\n", name, s.Type)
+	}
+	const steps = 24
+	for i := 0; i <= steps; i++ {
+		for j := 0; j <= steps; j++ {
+			u, v := float64(i)/steps, float64(j)/steps
+			p := s.Point(u, v)
+			if d := distance(p); math.Abs(d) > 1e-12 {
+				t.Errorf("%v: point %v at %v, %v is %v off the surface\n", name, p, u, v, d)
+			}
+			expected := normalize(outward(p))
+			if expected == [3]float64{} {
+				continue
+			}
+			if n := s.Normal(u, v); dot(n, expected) < 1-1e-5 {
+				t.Errorf("%v: expected normal %v at %v, got %v\n", name, expected, p, n)
+			}
+		}
+	}
+}
+
+func TestSphere(t *testing.T) {
+	c := [3]float64{1, 2, 3}
+	s := Sphere(c, 2)
+	offset := func(p [3]float64) [3]float64 {
+		return [3]float64{p[0] - c[0], p[1] - c[1], p[2] - c[2]}
+	}
+	checkSurface(t, "sphere", s, func(p [3]float64) float64 {
+		d := offset(p)
+		return math.Sqrt(dot(d, d)) - 2
+	}, offset)
+
+	if p := s.Point(0.3, 0); !near(p, [3]float64{1, 2, 1}, 1e-12) {
+		t.Errorf("Expected the south pole, got %v\n", p)
+	}
+	if p := s.Point(0.3, 1); !near(p, [3]float64{1, 2, 5}, 1e-12) {
+		t.Errorf("Expected the north pole, got %v\n", p)
+	}
+}
+
+func TestCylinderAndCone(t *testing.T) {
+	s := Cylinder([3]float64{0, 0, -1}, 1.5, 2)
+	checkSurface(t, "cylinder", s, func(p [3]float64) float64 {
+		if p[2] < -1-1e-12 || p[2] > 1+1e-12 {
+			return p[2]
+		}
+		return math.Hypot(p[0], p[1]) - 1.5
+	}, func(p [3]float64) [3]float64 {
+		return [3]float64{p[0], p[1], 0}
+	})
+
+	// A cone of slope 1 with its apex at (0, 0, 2).
+	s = Cone([3]float64{}, 2, 0, 2)
+	checkSurface(t, "cone", s, func(p [3]float64) float64 {
+		return math.Hypot(p[0], p[1]) - (2 - p[2])
+	}, func(p [3]float64) [3]float64 {
+		r := math.Hypot(p[0], p[1])
+		if r == 0 {
+			// The normal at the apex is taken next to it.
+			return [3]float64{}
+		}
+		return [3]float64{p[0] / r, p[1] / r, 1}
+	})
+}
+
+func TestTorus(t *testing.T) {
+	s := Torus([3]float64{0, 0, 1}, 3, 1)
+	checkSurface(t, "torus", s, func(p [3]float64) float64 {
+		return math.Hypot(math.Hypot(p[0], p[1])-3, p[2]-1) - 1
+	}, func(p [3]float64) [3]float64 {
+		r := math.Hypot(p[0], p[1])
+		return [3]float64{p[0] - 3*p[0]/r, p[1] - 3*p[1]/r, p[2] - 1}
+	})
+}
+
+func TestRevolve(t *testing.T) {
+	// A quarter turn of a tilted line around a tilted axis gives part of
+	// a cone.
+	profile := &Curve[float64]{
+		Knots:   []float64{0, 0, 1, 1},
+		Stride:  3,
+		Control: []float64{1, 0, 0, 1, 1, 1},
+		Order:   2,
+		Type:    map1Vertex3,
+	}
+	axis := normalize([3]float64{0, 1, 1})
+	s, err := Revolve(profile, [3]float64{}, axis, math.Pi/2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []float64{0, 0.5, 1} {
+		q := profile.Point(v)
+		h := dot(q, axis)
+		r := math.Sqrt(dot(q, q) - h*h)
+		for i := 0; i <= 10; i++ {
+			p := s.Point(float64(i)/10, v)
+			ph := dot(p, axis)
+			if pr := math.Sqrt(dot(p, p) - ph*ph); math.Abs(ph-h) > 1e-12 || math.Abs(pr-r) > 1e-12 {
+				t.Errorf("Expected %v to be on the circle of %v\n", p, q)
+			}
+		}
+		// The end of the sweep is a quarter turn counterclockwise.
+		p := s.Point(1, v)
+		x := [3]float64{q[0] - h*axis[0], q[1] - h*axis[1], q[2] - h*axis[2]}
+		expected := cross(axis, x)
+		for k := range expected {
+			expected[k] += h * axis[k]
+		}
+		if !near(p, expected, 1e-12) {
+			t.Errorf("Expected %v after a quarter turn, got %v\n", expected, p)
+		}
+	}
+
+	if _, err := Revolve(profile, [3]float64{}, [3]float64{}, 1); err == nil {
+		t.Errorf("Expected an error for a zero axis\n")
+	}
+	profile.Type = map1Color4
+	if _, err := Revolve(profile, [3]float64{}, axis, 1); err == nil {
+		t.Errorf("Expected an error for a color profile\n")
+	}
+}
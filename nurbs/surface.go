@@ -0,0 +1,197 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"fmt"
+	"math"
+)
+
+// Surface is a NURBS surface. Control point i, j, the i-th along s and
+// the j-th along t, starts at Control[i*SStride+j*TStride]. There are
+// len(SKnots)-SOrder control points along s and len(TKnots)-TOrder along t.
+type Surface[T Float] struct {
+	SKnots  []T
+	TKnots  []T
+	SStride int
+	TStride int
+	Control []T
+	SOrder  int
+	TOrder  int
+	Type    uint32 // a MAP2_* type
+}
+
+// Validate checks that the knots, strides, control points and orders are
+// consistent with each other and with the map type.
+func (s *Surface[T]) Validate() error {
+	dim := 0
+	if s.Type >= mapSurfaceDistance {
+		dim = dimension(s.Type - mapSurfaceDistance)
+	}
+	if dim == 0 {
+		return fmt.Errorf("Invalid surface type 0x%X", s.Type)
+	}
+	ns, err := validateKnots(s.SKnots, s.SOrder, "s ")
+	if err != nil {
+		return err
+	}
+	nt, err := validateKnots(s.TKnots, s.TOrder, "t ")
+	if err != nil {
+		return err
+	}
+	if s.SStride < dim || s.TStride < dim {
+		return fmt.Errorf("Invalid strides %d, %d for %d coordinates", s.SStride, s.TStride, dim)
+	}
+	if need := (ns-1)*s.SStride + (nt-1)*s.TStride + dim; len(s.Control) < need {
+		return fmt.Errorf("Insufficient control data: %d values for %dx%d control points", len(s.Control), ns, nt)
+	}
+	return nil
+}
+
+// Domain returns the parameter ranges of the surface.
+func (s *Surface[T]) Domain() (sMin, sMax, tMin, tMax T) {
+	return s.SKnots[s.SOrder-1], s.SKnots[len(s.SKnots)-s.SOrder],
+		s.TKnots[s.TOrder-1], s.TKnots[len(s.TKnots)-s.TOrder]
+}
+
+// Evaluate returns the coordinates of the surface and of its partial
+// derivatives up to total order n at u, v, indexed by the order of the
+// derivative along s, along t, and coordinate, for orders adding up to at
+// most n. Coordinates of a rational surface are homogeneous. Parameters
+// outside the domain are clamped to it. The surface must be valid.
+func (s *Surface[T]) Evaluate(u, v T, n int) [][][]T {
+	ders := s.evaluate(u, v, n)
+	out := make([][][]T, len(ders))
+	for k := range ders {
+		out[k] = convert[T](ders[k])
+	}
+	return out
+}
+
+// evaluate is algorithm A3.6 of The NURBS Book.
+func (s *Surface[T]) evaluate(u, v T, n int) [][][]float64 {
+	dim := dimension(s.Type - mapSurfaceDistance)
+	sKnots, tKnots := toFloat64(s.SKnots), toFloat64(s.TKnots)
+	ns, p := len(sKnots)-s.SOrder, s.SOrder-1
+	nt, q := len(tKnots)-s.TOrder, s.TOrder-1
+	x := clamp(float64(u), sKnots[p], sKnots[ns])
+	y := clamp(float64(v), tKnots[q], tKnots[nt])
+	sSpan := findSpan(sKnots, ns, p, x)
+	tSpan := findSpan(tKnots, nt, q, y)
+	sBasis := basisDerivs(sKnots, sSpan, p, x, n)
+	tBasis := basisDerivs(tKnots, tSpan, q, y, n)
+
+	ders := make([][][]float64, n+1)
+	temp := make([][]float64, q+1)
+	for k := 0; k <= n; k++ {
+		ders[k] = make([][]float64, n+1-k)
+		for j := range temp {
+			temp[j] = make([]float64, dim)
+			for r, b := range sBasis[k] {
+				cp := s.Control[(sSpan-p+r)*s.SStride+(tSpan-q+j)*s.TStride:]
+				for i := range temp[j] {
+					temp[j][i] += b * float64(cp[i])
+				}
+			}
+		}
+		for l := range ders[k] {
+			ders[k][l] = make([]float64, dim)
+			for j, b := range tBasis[l] {
+				for i := range ders[k][l] {
+					ders[k][l][i] += b * temp[j][i]
+				}
+			}
+		}
+	}
+	return ders
+}
+
+// Point returns the point of a MAP2_VERTEX_3 or MAP2_VERTEX_4 surface at
+// u, v.
+func (s *Surface[T]) Point(u, v T) [3]T {
+	return vec[T](s.vertexDerivs(u, v, 0)[0][0])
+}
+
+// Derivatives returns the point and the first and second partial
+// derivatives of a MAP2_VERTEX_3 or MAP2_VERTEX_4 surface at u, v.
+func (s *Surface[T]) Derivatives(u, v T) (p, ds, dt, dss, dst, dtt [3]T) {
+	d := s.vertexDerivs(u, v, 2)
+	return vec[T](d[0][0]), vec[T](d[1][0]), vec[T](d[0][1]), vec[T](d[2][0]), vec[T](d[1][1]), vec[T](d[0][2])
+}
+
+// Normal returns the unit normal, the direction of the cross product of
+// the derivatives along s and t, of a MAP2_VERTEX_3 or MAP2_VERTEX_4
+// surface at u, v. Where the surface degenerates, as at the poles of a
+// sphere, the normal is taken slightly inside the domain.
+func (s *Surface[T]) Normal(u, v T) [3]T {
+	n := s.normal(float64(u), float64(v))
+	if n == [3]float64{} {
+		sMin, sMax, tMin, tMax := s.Domain()
+		const nudge = 1e-6
+		uc := float64(u) + nudge*(float64(sMin+sMax)/2-float64(u))
+		vc := float64(v) + nudge*(float64(tMin+tMax)/2-float64(v))
+		n = s.normal(uc, vc)
+	}
+	return vec[T](n)
+}
+
+func (s *Surface[T]) normal(u, v float64) [3]float64 {
+	d := s.vertexDerivs(T(u), T(v), 1)
+	a, b := d[1][0], d[0][1]
+	n := [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+	// Where a partial derivative vanishes, as at a pole, the cross product
+	// is rounding noise.
+	scale := math.Sqrt(a[0]*a[0]+a[1]*a[1]+a[2]*a[2]) + math.Sqrt(b[0]*b[0]+b[1]*b[1]+b[2]*b[2])
+	if math.Sqrt(n[0]*n[0]+n[1]*n[1]+n[2]*n[2]) <= 1e-12*scale*scale {
+		return [3]float64{}
+	}
+	return normalize(n)
+}
+
+// vertexDerivs returns the Euclidean point and partial derivatives up to
+// total order n, as in algorithm A4.4 of The NURBS Book.
+func (s *Surface[T]) vertexDerivs(u, v T, n int) [][][3]float64 {
+	ders := s.evaluate(u, v, n)
+	out := make([][][3]float64, len(ders))
+	for k := range ders {
+		out[k] = make([][3]float64, len(ders[k]))
+		for l := range ders[k] {
+			copy(out[k][l][:], ders[k][l])
+		}
+	}
+	if s.Type != map2Vertex4 {
+		return out
+	}
+
+	w := func(k, l int) float64 { return ders[k][l][3] }
+	for k := range out {
+		for l := range out[k] {
+			a := out[k][l]
+			for j := 1; j <= l; j++ {
+				b := binomial(l, j) * w(0, j)
+				for c := range a {
+					a[c] -= b * out[k][l-j][c]
+				}
+			}
+			for i := 1; i <= k; i++ {
+				b := binomial(k, i) * w(i, 0)
+				for c := range a {
+					a[c] -= b * out[k-i][l][c]
+				}
+				for j := 1; j <= l; j++ {
+					b := binomial(k, i) * binomial(l, j) * w(i, j)
+					for c := range a {
+						a[c] -= b * out[k-i][l-j][c]
+					}
+				}
+			}
+			for c := range a {
+				a[c] /= w(0, 0)
+			}
+			out[k][l] = a
+		}
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nurbs
+
+import (
+	"math"
+	"testing"
+)
+
+// cylinderPatch is a quarter of a unit cylinder of height 2, rational
+// along s and linear along t.
+func cylinderPatch() *Surface[float64] {
+	w := math.Sqrt2 / 2
+	return &Surface[float64]{
+		SKnots:  []float64{0, 0, 0, 1, 1, 1},
+		TKnots:  []float64{0, 0, 1, 1},
+		SStride: 8,
+		TStride: 4,
+		Control: []float64{
+			1, 0, 0, 1, 1, 0, 2, 1,
+			w, w, 0, w, w, w, 2 * w, w,
+			0, 1, 0, 1, 0, 1, 2, 1,
+		},
+		SOrder: 3,
+		TOrder: 2,
+		Type:   map2Vertex4,
+	}
+}
+
+func TestSurfaceRational(t *testing.T) {
+	s := cylinderPatch()
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	const h = 1e-5
+	for _, u := range []float64{0.1, 0.3, 0.7} {
+		for _, v := range []float64{0.2, 0.5, 0.9} {
+			p, ds, dt, dss, dst, dtt := s.Derivatives(u, v)
+			if r := math.Hypot(p[0], p[1]); math.Abs(r-1) > 1e-15 || math.Abs(p[2]-2*v) > 1e-15 {
+				t.Errorf("At %v, %v: expected a point on the cylinder, got %v\n", u, v, p)
+			}
+			if n := s.Normal(u, v); !near(n, [3]float64{p[0], p[1], 0}, 1e-12) {
+				t.Errorf("At %v, %v: expected an outward normal, got %v\n", u, v, n)
+			}
+
+			ps0, ps1 := s.Point(u-h, v), s.Point(u+h, v)
+			pt0, pt1 := s.Point(u, v-h), s.Point(u, v+h)
+			_, ds0, dt0, _, _, _ := s.Derivatives(u-h, v)
+			_, ds1, dt1, _, _, _ := s.Derivatives(u+h, v)
+			_, _, dt2, _, _, _ := s.Derivatives(u, v-h)
+			_, _, dt3, _, _, _ := s.Derivatives(u, v+h)
+			var fds, fdt, fdss, fdst, fdtt [3]float64
+			for i := range fds {
+				fds[i] = (ps1[i] - ps0[i]) / (2 * h)
+				fdt[i] = (pt1[i] - pt0[i]) / (2 * h)
+				fdss[i] = (ds1[i] - ds0[i]) / (2 * h)
+				fdst[i] = (dt1[i] - dt0[i]) / (2 * h)
+				fdtt[i] = (dt3[i] - dt2[i]) / (2 * h)
+			}
+			if !near(ds, fds, 1e-6) || !near(dt, fdt, 1e-6) || !near(dss, fdss, 1e-5) ||
+				!near(dst, fdst, 1e-5) || !near(dtt, fdtt, 1e-5) {
+				t.Errorf("At %v, %v: derivatives %v %v %v %v %v differ from %v %v %v %v %v\n",
+					u, v, ds, dt, dss, dst, dtt, fds, fdt, fdss, fdst, fdtt)
+			}
+		}
+	}
+}
+
+func TestSurfaceBilinear(t *testing.T) {
+	corners := [4][3]float32{{0, 0, 0}, {0, 1, 0}, {2, 0, 1}, {2, 1, 1}}
+	s := &Surface[float32]{
+		SKnots:  []float32{0, 0, 1, 1},
+		TKnots:  []float32{0, 0, 1, 1},
+		SStride: 6,
+		TStride: 3,
+		Control: []float32{0, 0, 0, 0, 1, 0, 2, 0, 1, 2, 1, 1},
+		SOrder:  2,
+		TOrder:  2,
+		Type:    map2Vertex3,
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	p := s.Point(0.5, 0.25)
+	for i := range p {
+		expected := 0.5*0.75*corners[0][i] + 0.5*0.25*corners[1][i] + 0.5*0.75*corners[2][i] + 0.5*0.25*corners[3][i]
+		if p[i] != expected {
+			t.Errorf("Expected %v, got %v\n", expected, p[i])
+		}
+	}
+	n := s.Normal(0.5, 0.5)
+	l := float32(math.Sqrt(5))
+	if expected := [3]float32{-1 / l, 0, 2 / l}; math.Abs(float64(n[0]-expected[0])) > 1e-6 ||
+		n[1] != 0 || math.Abs(float64(n[2]-expected[2])) > 1e-6 {
+		t.Errorf("Expected normal %v, got %v\n", expected, n)
+	}
+}
+
+func TestSurfaceDegenerate(t *testing.T) {
+	// A cone over the cylinder patch, with its apex at t = 1.
+	s := cylinderPatch()
+	for i := 0; i < 3; i++ {
+		w := s.Control[i*8+7]
+		copy(s.Control[i*8+4:], []float64{0, 0, 2 * w})
+	}
+	n := s.Normal(0.5, 1)
+	if l := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2]); math.Abs(l-1) > 1e-9 {
+		t.Fatalf("Expected a unit normal at the apex, got %v\n", n)
+	}
+	if expected := [3]float64{math.Sqrt(0.4), math.Sqrt(0.4), math.Sqrt(0.2)}; !near(n, expected, 1e-4) {
+		t.Errorf("Expected normal %v, got %v\n", expected, n)
+	}
+}
+
+func TestSurfaceValidate(t *testing.T) {
+	for _, change := range []func(*Surface[float64]){
+		func(s *Surface[float64]) { s.Type = map1Vertex4 },
+		func(s *Surface[float64]) { s.TOrder = 3 },
+		func(s *Surface[float64]) { s.SKnots = []float64{0, 0, 0, 1, 1} },
+		func(s *Surface[float64]) { s.TStride = 3 },
+		func(s *Surface[float64]) { s.Control = s.Control[:len(s.Control)-1] },
+	} {
+		s := cylinderPatch()
+		change(s)
+		if err := s.Validate(); err == nil {
+			t.Errorf("Expected an error for %v\n", s)
+		}
+	}
+}
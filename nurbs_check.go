@@ -0,0 +1,121 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"fmt"
+)
+
+// maxNurbsOrder is the highest order supported by GLU.
+const maxNurbsOrder = 24
+
+// nurbsMapDimension returns the number of coordinates of a control point
+// of the map type, or zero for an unknown type, and whether the type is a
+// MAP2_* surface type.
+func nurbsMapDimension(typ uint32) (int, bool) {
+	surface := typ >= MAP2_COLOR_4 && typ <= MAP2_VERTEX_4
+	if surface {
+		typ -= MAP2_COLOR_4 - MAP1_COLOR_4
+	}
+	switch typ {
+	case MAP1_INDEX, MAP1_TEXTURE_COORD_1:
+		return 1, surface
+	case MAP1_TEXTURE_COORD_2, MAP1_TRIM_2:
+		return 2, surface
+	case MAP1_NORMAL, MAP1_TEXTURE_COORD_3, MAP1_VERTEX_3, MAP1_TRIM_3:
+		return 3, surface
+	case MAP1_COLOR_4, MAP1_TEXTURE_COORD_4, MAP1_VERTEX_4:
+		return 4, surface
+	}
+	return 0, false
+}
+
+// checkNurbsKnots checks knotCount knots for the given order and returns
+// the number of control points they require.
+func checkNurbsKnots(knotCount int, knots []float32, order int, dir string) (int, error) {
+	if order < 1 || order > maxNurbsOrder {
+		return 0, fmt.Errorf("Unsupported %sorder %d", dir, order)
+	}
+	if knotCount < 0 || knotCount > len(knots) {
+		return 0, fmt.Errorf("Invalid %sknot count %d for %d knots", dir, knotCount, len(knots))
+	}
+	n := knotCount - order
+	if n < order {
+		return 0, fmt.Errorf("Insufficient number of %sknots: %d for order %d", dir, knotCount, order)
+	}
+	for i := 1; i < knotCount; i++ {
+		if knots[i] < knots[i-1] {
+			return 0, fmt.Errorf("Decreasing %sknot %v at index %d", dir, knots[i], i)
+		}
+	}
+	if !(knots[order-1] < knots[n]) {
+		return 0, fmt.Errorf("Empty %sparameter domain", dir)
+	}
+	return n, nil
+}
+
+// checkNurbsCurve checks the arguments of NurbsCurve.
+func checkNurbsCurve(knotCount int, knots []float32, stride int, control []float32, order int, type0 uint32) error {
+	dim, surface := nurbsMapDimension(type0)
+	if dim == 0 || surface {
+		return fmt.Errorf("Invalid NURBS curve map type %#x", type0)
+	}
+	n, err := checkNurbsKnots(knotCount, knots, order, "")
+	if err != nil {
+		return err
+	}
+	if stride < dim {
+		return fmt.Errorf("Invalid stride %d for %d coordinates", stride, dim)
+	}
+	if need := (n-1)*stride + dim; len(control) < need {
+		return fmt.Errorf("Insufficient control points: %d values for %d points with stride %d", len(control), n, stride)
+	}
+	return nil
+}
+
+// checkNurbsSurface checks the arguments of NurbsSurface.
+func checkNurbsSurface(sKnotCount int, sKnots []float32, tKnotCount int, tKnots []float32, sStride int, tStride int, ctlarray []float32, sOrder int, tOrder int, type0 uint32) error {
+	dim, surface := nurbsMapDimension(type0)
+	if dim == 0 || !surface {
+		return fmt.Errorf("Invalid NURBS surface map type %#x", type0)
+	}
+	sn, err := checkNurbsKnots(sKnotCount, sKnots, sOrder, "s ")
+	if err != nil {
+		return err
+	}
+	tn, err := checkNurbsKnots(tKnotCount, tKnots, tOrder, "t ")
+	if err != nil {
+		return err
+	}
+	if sStride < dim {
+		return fmt.Errorf("Invalid s stride %d for %d coordinates", sStride, dim)
+	}
+	if tStride < dim {
+		return fmt.Errorf("Invalid t stride %d for %d coordinates", tStride, dim)
+	}
+	if need := (sn-1)*sStride + (tn-1)*tStride + dim; len(ctlarray) < need {
+		return fmt.Errorf("Insufficient control points: %d values for %dx%d points with strides %d and %d",
+			len(ctlarray), sn, tn, sStride, tStride)
+	}
+	return nil
+}
+
+// checkPwlCurve checks the arguments of PwlCurve.
+func checkPwlCurve(count int, data []float32, stride int, type0 uint32) error {
+	if type0 != MAP1_TRIM_2 && type0 != MAP1_TRIM_3 {
+		return fmt.Errorf("Invalid piecewise-linear curve type %#x", type0)
+	}
+	dim, _ := nurbsMapDimension(type0)
+	if count < 2 {
+		return fmt.Errorf("Insufficient number of points: %d", count)
+	}
+	if stride < dim {
+		return fmt.Errorf("Invalid stride %d for %d coordinates", stride, dim)
+	}
+	if need := (count-1)*stride + dim; len(data) < need {
+		return fmt.Errorf("Insufficient points: %d values for %d points with stride %d", len(data), count, stride)
+	}
+	return nil
+}
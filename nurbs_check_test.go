@@ -0,0 +1,114 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckNurbsCurve(t *testing.T) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	control := make([]float32, 4*3)
+
+	tests := []struct {
+		knotCount int
+		knots     []float32
+		stride    int
+		control   []float32
+		order     int
+		type0     uint32
+		err       string
+	}{
+		{8, knots, 3, control, 4, MAP1_VERTEX_3, ""},
+		{8, knots, 2, control[:8], 4, MAP1_TRIM_2, ""},
+		{8, knots, 4, make([]float32, 16), 4, MAP1_VERTEX_4, ""},
+		{8, knots, 3, control, 4, MAP2_VERTEX_3, "map type"},
+		{8, knots, 3, control, 4, 0, "map type"},
+		{8, nil, 3, control, 4, MAP1_VERTEX_3, "knot count"},
+		{9, knots, 3, control, 4, MAP1_VERTEX_3, "knot count"},
+		{8, knots, 3, control, 0, MAP1_VERTEX_3, "order"},
+		{8, knots, 3, control, 5, MAP1_VERTEX_3, "number of knots"},
+		{8, []float32{0, 0, 0, 1, 0, 1, 1, 1}, 3, control, 4, MAP1_VERTEX_3, "Decreasing knot"},
+		{8, []float32{0, 0, 0, 0, 0, 0, 0, 0}, 3, control, 4, MAP1_VERTEX_3, "domain"},
+		{8, knots, 2, control, 4, MAP1_VERTEX_3, "stride"},
+		{8, knots, 3, control[:9], 4, MAP1_VERTEX_3, "control points"},
+		{8, knots, 3, nil, 4, MAP1_VERTEX_3, "control points"},
+	}
+	for i, test := range tests {
+		err := checkNurbsCurve(test.knotCount, test.knots, test.stride, test.control, test.order, test.type0)
+		checkError(t, i, err, test.err)
+	}
+}
+
+func TestCheckNurbsSurface(t *testing.T) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	linear := []float32{0, 0, 1, 1}
+	control := make([]float32, 4*4*3)
+
+	tests := []struct {
+		sKnotCount, tKnotCount int
+		sKnots, tKnots         []float32
+		sStride, tStride       int
+		control                []float32
+		sOrder, tOrder         int
+		type0                  uint32
+		err                    string
+	}{
+		{8, 8, knots, knots, 12, 3, control, 4, 4, MAP2_VERTEX_3, ""},
+		{8, 4, knots, linear, 6, 3, control[:24], 4, 2, MAP2_VERTEX_3, ""},
+		{8, 8, knots, knots, 8, 2, control[:32], 4, 4, MAP2_TEXTURE_COORD_2, ""},
+		{8, 8, knots, knots, 12, 3, control, 4, 4, MAP1_VERTEX_3, "map type"},
+		{8, 8, knots, knots, 12, 3, control, 4, 4, MAP1_TRIM_2, "map type"},
+		{8, 8, knots, nil, 12, 3, control, 4, 4, MAP2_VERTEX_3, "t knot count"},
+		{8, 8, []float32{1, 0, 0, 0, 1, 1, 1, 1}, knots, 12, 3, control, 4, 4, MAP2_VERTEX_3, "Decreasing s knot"},
+		{8, 8, knots, knots, 12, 2, control, 4, 4, MAP2_VERTEX_3, "t stride"},
+		{8, 8, knots, knots, 12, 3, control[:47], 4, 4, MAP2_VERTEX_3, "control points"},
+		{8, 8, knots, knots, 3, 12, control[:47], 4, 4, MAP2_VERTEX_3, "control points"},
+		{8, 8, knots, knots, 12, 3, control, 4, 25, MAP2_VERTEX_3, "t order"},
+	}
+	for i, test := range tests {
+		err := checkNurbsSurface(test.sKnotCount, test.sKnots, test.tKnotCount, test.tKnots,
+			test.sStride, test.tStride, test.control, test.sOrder, test.tOrder, test.type0)
+		checkError(t, i, err, test.err)
+	}
+}
+
+func TestCheckPwlCurve(t *testing.T) {
+	data := []float32{0, 0, 1, 0, 1, 1, 0, 0}
+
+	tests := []struct {
+		count  int
+		data   []float32
+		stride int
+		type0  uint32
+		err    string
+	}{
+		{4, data, 2, MAP1_TRIM_2, ""},
+		{2, data, 3, MAP1_TRIM_3, ""},
+		{4, data, 2, MAP1_VERTEX_3, "type"},
+		{1, data, 2, MAP1_TRIM_2, "number of points"},
+		{4, data, 1, MAP1_TRIM_2, "stride"},
+		{5, data, 2, MAP1_TRIM_2, "Insufficient points"},
+		{2, nil, 2, MAP1_TRIM_2, "Insufficient points"},
+	}
+	for i, test := range tests {
+		checkError(t, i, checkPwlCurve(test.count, test.data, test.stride, test.type0), test.err)
+	}
+}
+
+// checkError checks that err is nil if expected is empty, and otherwise
+// mentions expected.
+func checkError(t *testing.T, i int, err error, expected string) {
+	t.Helper()
+	switch {
+	case expected == "" && err != nil:
+		t.Errorf("%d: Unexpected error %v\n", i, err)
+	case expected != "" && err == nil:
+		t.Errorf("%d: Expected an error about %q\n", i, expected)
+	case expected != "" && !strings.Contains(err.Error(), expected):
+		t.Errorf("%d: Expected an error about %q, got %v\n", i, expected, err)
+	}
+}
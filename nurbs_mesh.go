@@ -0,0 +1,202 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+
+package glu
+
+// TessellateSurface runs GLU's NURBS tessellator over the surface defined
+// by the calls made in define, such as NurbsSurface and trims, and returns
+// the resulting triangles as an indexed mesh instead of rendering them.
+// BeginSurface and EndSurface are called around define.
+//
+// The mesh has normals, colors and UVs if GLU emitted them, as it does
+// for MAP2_NORMAL, MAP2_COLOR_4 and MAP2_TEXTURE_COORD_* maps. Vertices
+// with equal attributes are shared. The handlers and NURBS_MODE of n are
+// restored before returning. The first NURBS error is returned.
+func (n *Nurbs) TessellateSurface(define func()) (*Mesh, error) {
+	c := n.capture()
+	defer c.restore()
+
+	n.BeginSurface()
+	define()
+	n.EndSurface()
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.result(), nil
+}
+
+// TessellateCurve runs GLU's NURBS tessellator over the curve defined by
+// the calls made in define, such as NurbsCurve, and returns the resulting
+// line strips. BeginCurve and EndCurve are called around define. The
+// handlers and NURBS_MODE of n are restored before returning. The first
+// NURBS error is returned.
+func (n *Nurbs) TessellateCurve(define func()) ([]Contour, error) {
+	c := n.capture()
+	defer c.restore()
+
+	n.BeginCurve()
+	define()
+	n.EndCurve()
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.lines, nil
+}
+
+// nurbsVertex is a vertex of a captured mesh with all its attributes.
+type nurbsVertex struct {
+	position [3]float32
+	normal   [3]float32
+	color    [4]float32
+	uv       [2]float32
+}
+
+// nurbsCapture collects the primitives of a Nurbs object in tessellator
+// mode. It replaces the handlers of the object until restore is called.
+type nurbsCapture struct {
+	n     *Nurbs
+	saved struct {
+		begin        NurbsBeginDataHandler
+		vertex       NurbsVertexDataHandler
+		normal       NurbsNormalDataHandler
+		color        NurbsColorDataHandler
+		textureCoord NurbsTextureCoordDataHandler
+		end          NurbsEndDataHandler
+		error        NurbsErrorHandler
+	}
+	mode float32
+
+	current                    nurbsVertex
+	hasNormal, hasColor, hasUV bool
+	vertices                   []nurbsVertex
+	index                      map[nurbsVertex]uint32
+	indices                    []uint32
+	prims                      primitiveAssembler
+	lines                      []Contour
+	line                       Contour
+	primitive                  uint32
+	err                        error
+}
+
+func (n *Nurbs) capture() *nurbsCapture {
+	c := &nurbsCapture{
+		n:     n,
+		mode:  n.GetNurbsProperty(NURBS_MODE),
+		index: make(map[nurbsVertex]uint32),
+	}
+	s := &c.saved
+	s.begin, s.vertex, s.normal, s.color = n.beginData, n.vertexData, n.normalData, n.colorData
+	s.textureCoord, s.end, s.error = n.textureCoordData, n.endData, n.errorData
+
+	n.NurbsProperty(NURBS_MODE, NURBS_TESSELLATOR)
+	n.SetBeginCallback(c.begin)
+	n.SetVertexCallback(c.vertex)
+	n.SetNormalCallback(c.normal)
+	n.SetColorCallback(c.color)
+	n.SetTextureCoordCallback(c.textureCoord)
+	n.SetEndCallback(c.end)
+	n.SetErrorCallback(c.fail)
+	return c
+}
+
+func (c *nurbsCapture) restore() {
+	n, s := c.n, &c.saved
+	n.NurbsProperty(NURBS_MODE, c.mode)
+	n.SetBeginCallback(s.begin)
+	n.SetVertexCallback(s.vertex)
+	n.SetNormalCallback(s.normal)
+	n.SetColorCallback(s.color)
+	n.SetTextureCoordCallback(s.textureCoord)
+	n.SetEndCallback(s.end)
+	n.SetErrorCallback(s.error)
+}
+
+func (c *nurbsCapture) begin(primitive uint32, polygonData interface{}) {
+	c.primitive = primitive
+	c.prims.begin(primitive)
+	c.line = nil
+}
+
+func (c *nurbsCapture) vertex(vertexData []float32, polygonData interface{}) {
+	copy(c.current.position[:], vertexData)
+	switch c.primitive {
+	case LINE_STRIP, LINE_LOOP, LINES, POINTS:
+		p := c.current.position
+		c.line = append(c.line, [3]float64{float64(p[0]), float64(p[1]), float64(p[2])})
+		return
+	}
+
+	i, ok := c.index[c.current]
+	if !ok {
+		i = uint32(len(c.vertices))
+		c.index[c.current] = i
+		c.vertices = append(c.vertices, c.current)
+	}
+	c.indices = c.prims.vertex(c.indices, i)
+}
+
+func (c *nurbsCapture) normal(normalData []float32, polygonData interface{}) {
+	copy(c.current.normal[:], normalData)
+	c.hasNormal = true
+}
+
+func (c *nurbsCapture) color(colorData []float32, polygonData interface{}) {
+	copy(c.current.color[:], colorData)
+	c.hasColor = true
+}
+
+func (c *nurbsCapture) textureCoord(texCoordData []float32, polygonData interface{}) {
+	copy(c.current.uv[:], texCoordData)
+	c.hasUV = true
+}
+
+func (c *nurbsCapture) end(polygonData interface{}) {
+	c.prims.end()
+	if len(c.line) > 0 {
+		c.lines = append(c.lines, c.line)
+	}
+	c.line = nil
+}
+
+func (c *nurbsCapture) fail(errorNumber uint32, polygonData interface{}) {
+	if c.err == nil {
+		c.err = &NurbsError{errorNumber}
+	}
+}
+
+// result returns the captured triangles as a mesh.
+func (c *nurbsCapture) result() *Mesh {
+	m := &Mesh{
+		Positions: make([][3]float64, len(c.vertices)),
+		Indices:   c.indices,
+	}
+	if c.hasNormal {
+		m.Normals = make([][3]float64, len(c.vertices))
+	}
+	if c.hasColor {
+		m.Colors = make([][4]float32, len(c.vertices))
+	}
+	if c.hasUV {
+		m.UVs = make([][2]float64, len(c.vertices))
+	}
+	for i, v := range c.vertices {
+		for k := range v.position {
+			m.Positions[i][k] = float64(v.position[k])
+		}
+		if c.hasNormal {
+			for k := range v.normal {
+				m.Normals[i][k] = float64(v.normal[k])
+			}
+		}
+		if c.hasColor {
+			m.Colors[i] = v.color
+		}
+		if c.hasUV {
+			m.UVs[i] = [2]float64{float64(v.uv[0]), float64(v.uv[1])}
+		}
+	}
+	return m
+}
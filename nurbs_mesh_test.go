@@ -0,0 +1,165 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+
+package glu
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl-legacy/glu/nurbs"
+)
+
+// planePatch returns the control points of a bicubic patch which is the
+// square [0, 3] x [0, 3] in the z = 0 plane.
+func planePatch() (vertices []float32) {
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			vertices = append(vertices, float32(i), float32(j), 0)
+		}
+	}
+	return
+}
+
+func TestNurbsTessellateSurface(t *testing.T) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	vertices := planePatch()
+
+	nurbs := NewNurbsRenderer()
+	defer nurbs.Delete()
+
+	nurbs.NurbsProperty(SAMPLING_METHOD, DOMAIN_DISTANCE)
+	nurbs.NurbsProperty(U_STEP, 4)
+	nurbs.NurbsProperty(V_STEP, 4)
+
+	var userVertices int
+	nurbs.SetVertexCallback(func(vertexData []float32, polygonData interface{}) {
+		userVertices++
+	})
+
+	mesh, err := nurbs.TessellateSurface(func() {
+		nurbs.NurbsSurface(8, knots, 8, knots, 12, 3, vertices, 4, 4, MAP2_VERTEX_3)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mesh.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if mesh.TriangleCount() == 0 {
+		t.Fatalf("Expected triangles\n")
+	}
+	if area := math.Abs(meshArea(mesh)); math.Abs(area-9) > 1e-5 {
+		t.Errorf("Expected area 9, got %v\n", area)
+	}
+	for _, p := range mesh.Positions {
+		if math.Abs(p[2]) > 1e-6 || p[0] < -1e-6 || p[0] > 3+1e-6 || p[1] < -1e-6 || p[1] > 3+1e-6 {
+			t.Errorf("Unexpected position %v\n", p)
+		}
+	}
+	// Strips share their vertices.
+	if len(mesh.Positions) >= len(mesh.Indices) {
+		t.Errorf("Expected shared vertices, got %v for %v indices\n", len(mesh.Positions), len(mesh.Indices))
+	}
+
+	if userVertices != 0 {
+		t.Errorf("Expected the user callback not to be called\n")
+	}
+	if mode := nurbs.GetNurbsProperty(NURBS_MODE); mode != NURBS_RENDERER {
+		t.Errorf("Expected NURBS_MODE to be restored, got %v\n", mode)
+	}
+	if nurbs.vertexData == nil || nurbs.beginData != nil {
+		t.Errorf("Expected the handlers to be restored\n")
+	}
+}
+
+func TestNurbsTessellateCurve(t *testing.T) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	control := []float32{0, 0, 0, 1, 2, 0, 2, -2, 0, 3, 0, 0}
+
+	nurbs := NewBufferedNurbsRenderer()
+	defer nurbs.Delete()
+	nurbs.NurbsProperty(SAMPLING_METHOD, DOMAIN_DISTANCE)
+	nurbs.NurbsProperty(U_STEP, 10)
+
+	lines, err := nurbs.TessellateCurve(func() {
+		nurbs.NurbsCurve(8, knots, 3, control, 4, MAP1_VERTEX_3)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) == 0 || len(lines[0]) < 10 {
+		t.Fatalf("Expected a line strip, got %v\n", lines)
+	}
+	first, last := lines[0][0], lines[len(lines)-1]
+	if first != [3]float64{0, 0, 0} || last[len(last)-1] != [3]float64{3, 0, 0} {
+		t.Errorf("Expected the strip to run from the first to the last control point, got %v\n", lines)
+	}
+
+	// Decreasing knots.
+	_, err = nurbs.TessellateCurve(func() {
+		nurbs.NurbsCurve(8, []float32{0, 0, 0, 1, 0, 1, 1, 1}, 3, control, 4, MAP1_VERTEX_3)
+	})
+	if _, ok := err.(*NurbsError); !ok {
+		t.Errorf("Expected a NurbsError, got %v\n", err)
+	}
+}
+
+func TestNurbsTessellateConics(t *testing.T) {
+	renderer := NewNurbsRenderer()
+	defer renderer.Delete()
+	renderer.SetSamplingMethod(SamplingDomainDistance)
+	renderer.SetUVStep(20, 20)
+
+	center := [3]float32{1, 2, 3}
+	s := nurbs.Sphere(center, 2)
+	mesh, err := renderer.TessellateSurface(func() {
+		err := renderer.NurbsSurfaceChecked(len(s.SKnots), s.SKnots, len(s.TKnots), s.TKnots,
+			s.SStride, s.TStride, s.Control, s.SOrder, s.TOrder, MAP2_VERTEX_4)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mesh.TriangleCount() == 0 {
+		t.Fatalf("Expected a sphere\n")
+	}
+	for _, p := range mesh.Positions {
+		r := math.Sqrt((p[0]-1)*(p[0]-1) + (p[1]-2)*(p[1]-2) + (p[2]-3)*(p[2]-3))
+		if math.Abs(r-2) > 1e-5 {
+			t.Fatalf("Expected points on the sphere, got %v at distance %v\n", p, r)
+		}
+	}
+
+	// GLU passes the vertices of rational curves in homogeneous coordinates.
+	c := nurbs.Circle(center, 2)
+	buffered := NewBufferedNurbsRenderer()
+	defer buffered.Delete()
+	for _, renderer := range []*Nurbs{renderer, buffered} {
+		renderer.SetSamplingMethod(SamplingDomainDistance)
+		renderer.SetUVStep(20, 20)
+		lines, err := renderer.TessellateCurve(func() {
+			if err := renderer.NurbsCurveChecked(len(c.Knots), c.Knots, c.Stride, c.Control, c.Order, MAP1_VERTEX_4); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(lines) == 0 {
+			t.Fatalf("Expected a circle\n")
+		}
+		for _, line := range lines {
+			for _, p := range line {
+				if r := math.Hypot(p[0]-1, p[1]-2); math.Abs(r-2) > 1e-5 || math.Abs(p[2]-3) > 1e-5 {
+					t.Fatalf("Expected points on the circle, got %v\n", p)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+
+package glu
+
+// NurbsSamplingMethod is a value of the SAMPLING_METHOD property, which
+// selects how NURBS are divided into lines and polygons.
+type NurbsSamplingMethod uint32
+
+const (
+	SamplingPathLength            NurbsSamplingMethod = PATH_LENGTH             // edges of at most SAMPLING_TOLERANCE pixels
+	SamplingParametricError       NurbsSamplingMethod = PARAMETRIC_ERROR        // at most PARAMETRIC_TOLERANCE pixels from the surface
+	SamplingDomainDistance        NurbsSamplingMethod = DOMAIN_DISTANCE         // U_STEP and V_STEP points per parametric unit
+	SamplingObjectPathLength      NurbsSamplingMethod = OBJECT_PATH_LENGTH      // like SamplingPathLength, in object space
+	SamplingObjectParametricError NurbsSamplingMethod = OBJECT_PARAMETRIC_ERROR // like SamplingParametricError, in object space
+)
+
+// NurbsDisplayMode is a value of the DISPLAY_MODE property.
+type NurbsDisplayMode uint32
+
+const (
+	DisplayFill           NurbsDisplayMode = FILL            // filled polygons
+	DisplayOutlinePolygon NurbsDisplayMode = OUTLINE_POLYGON // outlines of the polygons
+	DisplayOutlinePatch   NurbsDisplayMode = OUTLINE_PATCH   // outlines of the patches and trim curves
+)
+
+// NurbsMode is a value of the NURBS_MODE property.
+type NurbsMode uint32
+
+const (
+	ModeRenderer    NurbsMode = NURBS_RENDERER    // draw with OpenGL
+	ModeTessellator NurbsMode = NURBS_TESSELLATOR // hand primitives to the callbacks
+)
+
+// SetSamplingMethod sets the SAMPLING_METHOD property.
+func (n *Nurbs) SetSamplingMethod(method NurbsSamplingMethod) {
+	n.NurbsProperty(SAMPLING_METHOD, float32(method))
+}
+
+// SamplingMethod returns the SAMPLING_METHOD property.
+func (n *Nurbs) SamplingMethod() NurbsSamplingMethod {
+	return NurbsSamplingMethod(n.GetNurbsProperty(SAMPLING_METHOD))
+}
+
+// SetSamplingTolerance sets the SAMPLING_TOLERANCE property, the longest
+// edge, in pixels or object units, of the path length sampling methods.
+func (n *Nurbs) SetSamplingTolerance(tolerance float32) {
+	n.NurbsProperty(SAMPLING_TOLERANCE, tolerance)
+}
+
+// SamplingTolerance returns the SAMPLING_TOLERANCE property.
+func (n *Nurbs) SamplingTolerance() float32 {
+	return n.GetNurbsProperty(SAMPLING_TOLERANCE)
+}
+
+// SetParametricTolerance sets the PARAMETRIC_TOLERANCE property, the
+// largest distance, in pixels or object units, between the polygons and
+// the surface for the parametric error sampling methods.
+func (n *Nurbs) SetParametricTolerance(tolerance float32) {
+	n.NurbsProperty(PARAMETRIC_TOLERANCE, tolerance)
+}
+
+// ParametricTolerance returns the PARAMETRIC_TOLERANCE property.
+func (n *Nurbs) ParametricTolerance() float32 {
+	return n.GetNurbsProperty(PARAMETRIC_TOLERANCE)
+}
+
+// SetUVStep sets the U_STEP and V_STEP properties, the number of sample
+// points per unit of parameter for SamplingDomainDistance.
+func (n *Nurbs) SetUVStep(u, v float32) {
+	n.NurbsProperty(U_STEP, u)
+	n.NurbsProperty(V_STEP, v)
+}
+
+// UVStep returns the U_STEP and V_STEP properties.
+func (n *Nurbs) UVStep() (u, v float32) {
+	return n.GetNurbsProperty(U_STEP), n.GetNurbsProperty(V_STEP)
+}
+
+// SetCulling sets the CULLING property. When enabled, NURBS entirely
+// outside the viewport are discarded before tessellation.
+func (n *Nurbs) SetCulling(enabled bool) {
+	n.NurbsProperty(CULLING, boolProperty(enabled))
+}
+
+// Culling returns the CULLING property.
+func (n *Nurbs) Culling() bool {
+	return n.GetNurbsProperty(CULLING) != 0
+}
+
+// SetAutoLoadMatrix sets the AUTO_LOAD_MATRIX property. When enabled, the
+// sampling matrices are read from the OpenGL state; otherwise they are
+// given with LoadSamplingMatrices.
+func (n *Nurbs) SetAutoLoadMatrix(enabled bool) {
+	n.NurbsProperty(AUTO_LOAD_MATRIX, boolProperty(enabled))
+}
+
+// AutoLoadMatrix returns the AUTO_LOAD_MATRIX property.
+func (n *Nurbs) AutoLoadMatrix() bool {
+	return n.GetNurbsProperty(AUTO_LOAD_MATRIX) != 0
+}
+
+// SetDisplayMode sets the DISPLAY_MODE property.
+func (n *Nurbs) SetDisplayMode(mode NurbsDisplayMode) {
+	n.NurbsProperty(DISPLAY_MODE, float32(mode))
+}
+
+// DisplayMode returns the DISPLAY_MODE property.
+func (n *Nurbs) DisplayMode() NurbsDisplayMode {
+	return NurbsDisplayMode(n.GetNurbsProperty(DISPLAY_MODE))
+}
+
+// SetMode sets the NURBS_MODE property.
+func (n *Nurbs) SetMode(mode NurbsMode) {
+	n.NurbsProperty(NURBS_MODE, float32(mode))
+}
+
+// Mode returns the NURBS_MODE property.
+func (n *Nurbs) Mode() NurbsMode {
+	return NurbsMode(n.GetNurbsProperty(NURBS_MODE))
+}
+
+func boolProperty(b bool) float32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+
+package glu
+
+import (
+	"testing"
+)
+
+func TestNurbsPropertyRoundTrip(t *testing.T) {
+	nurbs := NewNurbsRenderer()
+	defer nurbs.Delete()
+
+	tests := []struct {
+		property uint32
+		values   []float32
+	}{
+		{AUTO_LOAD_MATRIX, []float32{0, 1}},
+		{CULLING, []float32{1, 0}},
+		{PARAMETRIC_TOLERANCE, []float32{0.25, 2}},
+		{SAMPLING_TOLERANCE, []float32{10, 50}},
+		{DISPLAY_MODE, []float32{OUTLINE_POLYGON, OUTLINE_PATCH, FILL}},
+		{SAMPLING_METHOD, []float32{PARAMETRIC_ERROR, DOMAIN_DISTANCE,
+			OBJECT_PATH_LENGTH, OBJECT_PARAMETRIC_ERROR, PATH_LENGTH}},
+		{U_STEP, []float32{4, 100}},
+		{V_STEP, []float32{8, 100}},
+		{NURBS_MODE, []float32{NURBS_TESSELLATOR, NURBS_RENDERER}},
+	}
+	for _, test := range tests {
+		for _, value := range test.values {
+			nurbs.NurbsProperty(test.property, value)
+			if got := nurbs.GetNurbsProperty(test.property); got != value {
+				t.Errorf("Expected property %v to be %v, got %v\n", test.property, value, got)
+			}
+		}
+	}
+}
+
+func TestNurbsTypedProperties(t *testing.T) {
+	nurbs := NewNurbsRenderer()
+	defer nurbs.Delete()
+
+	for _, method := range []NurbsSamplingMethod{SamplingPathLength, SamplingParametricError,
+		SamplingDomainDistance, SamplingObjectPathLength, SamplingObjectParametricError} {
+		nurbs.SetSamplingMethod(method)
+		if got := nurbs.SamplingMethod(); got != method {
+			t.Errorf("Expected sampling method %v, got %v\n", method, got)
+		}
+	}
+	for _, mode := range []NurbsDisplayMode{DisplayOutlinePolygon, DisplayOutlinePatch, DisplayFill} {
+		nurbs.SetDisplayMode(mode)
+		if got := nurbs.DisplayMode(); got != mode {
+			t.Errorf("Expected display mode %v, got %v\n", mode, got)
+		}
+	}
+	for _, mode := range []NurbsMode{ModeTessellator, ModeRenderer} {
+		nurbs.SetMode(mode)
+		if got := nurbs.Mode(); got != mode {
+			t.Errorf("Expected mode %v, got %v\n", mode, got)
+		}
+	}
+	for _, b := range []bool{false, true} {
+		nurbs.SetCulling(b)
+		nurbs.SetAutoLoadMatrix(b)
+		if nurbs.Culling() != b || nurbs.AutoLoadMatrix() != b {
+			t.Errorf("Expected culling and auto load matrix to be %v\n", b)
+		}
+	}
+
+	nurbs.SetSamplingTolerance(30)
+	nurbs.SetParametricTolerance(0.75)
+	nurbs.SetUVStep(12, 34)
+	if got := nurbs.SamplingTolerance(); got != 30 {
+		t.Errorf("Expected sampling tolerance 30, got %v\n", got)
+	}
+	if got := nurbs.ParametricTolerance(); got != 0.75 {
+		t.Errorf("Expected parametric tolerance 0.75, got %v\n", got)
+	}
+	if u, v := nurbs.UVStep(); u != 12 || v != 34 {
+		t.Errorf("Expected steps 12 and 34, got %v and %v\n", u, v)
+	}
+}
@@ -1,6 +1,9 @@
+//go:build cgo
+
 package glu
 
 import (
+	"math"
 	"testing"
 )
 
@@ -51,3 +54,212 @@ func TestNurbs(t *testing.T) {
 	// End the surface
 	nurbs.EndSurface()
 }
+
+func TestNurbsCallbacks(t *testing.T) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	control := []float32{
+		0, 0, 0,
+		1, 2, 0,
+		2, -2, 0,
+		3, 0, 0,
+	}
+
+	var begins, vertices, ends int
+
+	nurbs := NewNurbsRenderer()
+	defer nurbs.Delete()
+
+	nurbs.NurbsProperty(NURBS_MODE, NURBS_TESSELLATOR)
+	nurbs.SetBeginCallback(func(tessType uint32, polygonData interface{}) {
+		begins += polygonData.(int)
+	})
+	nurbs.SetVertexCallback(func(vertexData []float32, polygonData interface{}) {
+		vertices++
+	})
+	nurbs.SetEndCallback(func(polygonData interface{}) {
+		ends++
+	})
+	nurbs.NurbsCallbackData(1)
+
+	nurbs.BeginCurve()
+	nurbs.NurbsCurve(8, knots, 3, control, 4, MAP1_VERTEX_3)
+	nurbs.EndCurve()
+
+	if begins == 0 || begins != ends {
+		t.Errorf("Expected matching begin and end callbacks, got %v and %v\n", begins, ends)
+	}
+	if vertices < 2 {
+		t.Errorf("Expected at least 2 vertices, got %v\n", vertices)
+	}
+}
+
+func TestNurbsErrorCallback(t *testing.T) {
+	var errors []uint32
+
+	nurbs := NewNurbsRenderer()
+	defer nurbs.Delete()
+
+	nurbs.SetErrorCallback(func(errorNumber uint32, polygonData interface{}) {
+		errors = append(errors, errorNumber)
+	})
+
+	nurbs.EndSurface()
+
+	if len(errors) != 1 {
+		t.Errorf("Expected 1 error, got %v\n", errors)
+	}
+}
+
+func TestNurbsErrorTargetNested(t *testing.T) {
+	a, b := NewNurbsRenderer(), NewNurbsRenderer()
+	defer a.Delete()
+	defer b.Delete()
+
+	// A second BeginCurve raises two errors in one call, and the first
+	// calls into another NURBS object.
+	var errs []uint32
+	a.SetErrorCallback(func(errorNumber uint32, polygonData interface{}) {
+		errs = append(errs, errorNumber)
+		b.NurbsProperty(SAMPLING_TOLERANCE, 25)
+	})
+	b.SetErrorCallback(func(errorNumber uint32, polygonData interface{}) {
+		t.Errorf("Unexpected error %v on the other NURBS object\n", errorNumber)
+	})
+
+	a.BeginCurve()
+	a.BeginCurve()
+	if len(errs) != 2 {
+		t.Errorf("Expected 2 errors, got %v\n", errs)
+	}
+}
+
+func TestBufferedNurbsCallbacks(t *testing.T) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	control := []float32{
+		0, 0, 0,
+		1, 2, 0,
+		2, -2, 0,
+		3, 0, 0,
+	}
+
+	collect := func(nurbs *Nurbs) (vertices [][3]float32) {
+		defer nurbs.Delete()
+
+		nurbs.NurbsProperty(NURBS_MODE, NURBS_TESSELLATOR)
+		nurbs.SetVertexCallback(func(vertexData []float32, polygonData interface{}) {
+			vertices = append(vertices, [3]float32{vertexData[0], vertexData[1], vertexData[2]})
+		})
+
+		nurbs.BeginCurve()
+		nurbs.NurbsCurve(8, knots, 3, control, 4, MAP1_VERTEX_3)
+		nurbs.EndCurve()
+		return
+	}
+
+	direct := collect(NewNurbsRenderer())
+	buffered := collect(NewBufferedNurbsRenderer())
+
+	if len(direct) == 0 || len(direct) != len(buffered) {
+		t.Fatalf("Expected %v buffered vertices, got %v\n", len(direct), len(buffered))
+	}
+	for i := range direct {
+		if direct[i] != buffered[i] {
+			t.Errorf("Vertex %v: expected %v, got %v\n", i, direct[i], buffered[i])
+		}
+	}
+}
+
+func BenchmarkNurbs(b *testing.B) {
+	benchmarkNurbs(b, NewNurbsRenderer)
+}
+
+func BenchmarkBufferedNurbs(b *testing.B) {
+	benchmarkNurbs(b, NewBufferedNurbsRenderer)
+}
+
+func benchmarkNurbs(b *testing.B, newNurbs func() *Nurbs) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	control := make([]float32, 4*4*3)
+	for u := 0; u < 4; u++ {
+		for v := 0; v < 4; v++ {
+			control[(u*4+v)*3+0] = float32(u)
+			control[(u*4+v)*3+1] = float32(v)
+			control[(u*4+v)*3+2] = float32((u + v) % 2)
+		}
+	}
+
+	nurbs := newNurbs()
+	defer nurbs.Delete()
+
+	nurbs.NurbsProperty(NURBS_MODE, NURBS_TESSELLATOR)
+	nurbs.NurbsProperty(SAMPLING_METHOD, DOMAIN_DISTANCE)
+	nurbs.NurbsProperty(U_STEP, 100)
+	nurbs.NurbsProperty(V_STEP, 100)
+
+	vertices := 0
+	nurbs.SetBeginCallback(func(tessType uint32, polygonData interface{}) {})
+	nurbs.SetVertexCallback(func(vertexData []float32, polygonData interface{}) {
+		vertices++
+	})
+	nurbs.SetNormalCallback(func(normalData []float32, polygonData interface{}) {})
+	nurbs.SetEndCallback(func(polygonData interface{}) {})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		nurbs.BeginSurface()
+		nurbs.NurbsSurface(8, knots, 8, knots, 4*3, 3, control, 4, 4, MAP2_VERTEX_3)
+		nurbs.EndSurface()
+	}
+}
+
+func TestNurbsChecked(t *testing.T) {
+	knots := []float32{0, 0, 0, 0, 1, 1, 1, 1}
+	control := make([]float32, 4*4*3)
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			copy(control[12*i+3*j:], []float32{float32(i), float32(j), 0})
+		}
+	}
+	hole := []float32{0.25, 0.25, 0.25, 0.75, 0.75, 0.75, 0.75, 0.25, 0.25, 0.25}
+	outer := []float32{0, 0, 1, 0, 1, 1, 0, 1, 0, 0}
+
+	nurbs := NewNurbsRenderer()
+	defer nurbs.Delete()
+	// Without an OpenGL context, sampling must not depend on its matrices.
+	nurbs.SetSamplingMethod(SamplingDomainDistance)
+
+	var errs []error
+	mesh, err := nurbs.TessellateSurface(func() {
+		errs = append(errs,
+			nurbs.NurbsSurfaceChecked(8, knots, 8, knots, 12, 3, control, 4, 4, MAP2_VERTEX_3),
+			// A map of the wrong type, and empty slices, are not passed on.
+			nurbs.NurbsSurfaceChecked(8, knots, 8, knots, 12, 3, control, 4, 4, MAP1_VERTEX_3),
+			nurbs.NurbsSurfaceChecked(8, nil, 8, nil, 12, 3, nil, 4, 4, MAP2_VERTEX_3))
+		nurbs.BeginTrim()
+		errs = append(errs, nurbs.PwlCurveChecked(5, outer, 2, MAP1_TRIM_2))
+		nurbs.EndTrim()
+		nurbs.BeginTrim()
+		errs = append(errs,
+			nurbs.PwlCurveChecked(5, hole, 2, MAP1_TRIM_2),
+			nurbs.PwlCurveChecked(5, nil, 2, MAP1_TRIM_2))
+		nurbs.EndTrim()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expectError := range []bool{false, true, true, false, false, true} {
+		if (errs[i] != nil) != expectError {
+			t.Errorf("%d: Unexpected error %v\n", i, errs[i])
+		}
+	}
+	// The surface is the square [0, 3] x [0, 3] with a hole of a quarter of
+	// its parameter domain.
+	if area := math.Abs(meshArea(mesh)); math.Abs(area-6.75) > 1e-4 {
+		t.Errorf("Expected a trimmed surface of area 6.75, got %v\n", area)
+	}
+
+	err = nurbs.NurbsCurveChecked(8, knots, 3, nil, 4, MAP1_VERTEX_3)
+	if err == nil {
+		t.Errorf("Expected an error for missing control points\n")
+	}
+}
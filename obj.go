@@ -0,0 +1,222 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteOBJ writes the mesh as a Wavefront OBJ file. Colors are written
+// after the position of each vertex, an extension understood by Blender
+// and MeshLab, without their alpha.
+func WriteOBJ(w io.Writer, m *Mesh) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	for i, p := range m.Positions {
+		fmt.Fprintf(bw, "v %s %s %s", formatFloat(p[0]), formatFloat(p[1]), formatFloat(p[2]))
+		if len(m.Colors) > 0 {
+			c := m.Colors[i]
+			fmt.Fprintf(bw, " %s %s %s", formatFloat32(c[0]), formatFloat32(c[1]), formatFloat32(c[2]))
+		}
+		bw.WriteString("\n")
+	}
+	for _, uv := range m.UVs {
+		fmt.Fprintf(bw, "vt %s %s\n", formatFloat(uv[0]), formatFloat(uv[1]))
+	}
+	for _, n := range m.Normals {
+		fmt.Fprintf(bw, "vn %s %s %s\n", formatFloat(n[0]), formatFloat(n[1]), formatFloat(n[2]))
+	}
+	for i := 0; i < len(m.Indices); i += 3 {
+		bw.WriteString("f")
+		for _, j := range m.Indices[i : i+3] {
+			j++
+			switch {
+			case len(m.UVs) > 0 && len(m.Normals) > 0:
+				fmt.Fprintf(bw, " %d/%d/%d", j, j, j)
+			case len(m.UVs) > 0:
+				fmt.Fprintf(bw, " %d/%d", j, j)
+			case len(m.Normals) > 0:
+				fmt.Fprintf(bw, " %d//%d", j, j)
+			default:
+				fmt.Fprintf(bw, " %d", j)
+			}
+		}
+		bw.WriteString("\n")
+	}
+	return bw.Flush()
+}
+
+// ReadOBJ reads the faces of a Wavefront OBJ file into a mesh. Polygons
+// are split into triangle fans. Statements other than v, vt, vn and f are
+// ignored. Vertices whose position, texture coordinate and normal indices
+// differ are duplicated as needed.
+func ReadOBJ(r io.Reader) (*Mesh, error) {
+	var (
+		positions [][3]float64
+		colors    [][4]float32
+		uvs       [][2]float64
+		normals   [][3]float64
+		corners   [][3]int // position, uv and normal index, -1 if absent
+		faces     []int    // corner count of each face
+	)
+
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		args := fields[1:]
+		switch fields[0] {
+		case "v":
+			v, err := parseFloats(args)
+			if err != nil || (len(v) != 3 && len(v) != 4 && len(v) != 6) {
+				return nil, fmt.Errorf("Invalid OBJ statement on line %d", line)
+			}
+			positions = append(positions, [3]float64{v[0], v[1], v[2]})
+			if len(v) == 6 {
+				colors = append(colors, [4]float32{float32(v[3]), float32(v[4]), float32(v[5]), 1})
+			}
+		case "vt":
+			v, err := parseFloats(args)
+			if err != nil || len(v) < 1 || len(v) > 3 {
+				return nil, fmt.Errorf("Invalid OBJ statement on line %d", line)
+			}
+			v = append(v, 0)
+			uvs = append(uvs, [2]float64{v[0], v[1]})
+		case "vn":
+			v, err := parseFloats(args)
+			if err != nil || len(v) != 3 {
+				return nil, fmt.Errorf("Invalid OBJ statement on line %d", line)
+			}
+			normals = append(normals, [3]float64{v[0], v[1], v[2]})
+		case "f":
+			if len(args) < 3 {
+				return nil, fmt.Errorf("Invalid OBJ statement on line %d", line)
+			}
+			for _, arg := range args {
+				c := [3]int{-1, -1, -1}
+				counts := [3]int{len(positions), len(uvs), len(normals)}
+				for k, ref := range strings.Split(arg, "/") {
+					if k > 2 || (ref == "" && k == 0) {
+						return nil, fmt.Errorf("Invalid OBJ statement on line %d", line)
+					}
+					if ref == "" {
+						continue
+					}
+					i, err := strconv.Atoi(ref)
+					if i < 0 {
+						// Relative to the end of the list so far.
+						i += counts[k] + 1
+					}
+					if err != nil || i < 1 || i > counts[k] {
+						return nil, fmt.Errorf("Invalid OBJ statement on line %d", line)
+					}
+					c[k] = i - 1
+				}
+				corners = append(corners, c)
+			}
+			faces = append(faces, len(args))
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(colors) != 0 && len(colors) != len(positions) {
+		return nil, fmt.Errorf("Invalid OBJ colors on some vertices only")
+	}
+
+	// Attributes are kept if every corner has them.
+	hasUV, hasNormal := len(corners) > 0, len(corners) > 0
+	direct := true
+	for _, c := range corners {
+		hasUV = hasUV && c[1] >= 0
+		hasNormal = hasNormal && c[2] >= 0
+		direct = direct && (c[1] < 0 || c[1] == c[0]) && (c[2] < 0 || c[2] == c[0])
+	}
+
+	m := &Mesh{}
+	index := make(map[[3]int]uint32)
+	vertex := func(c [3]int) uint32 {
+		if !hasUV {
+			c[1] = -1
+		}
+		if !hasNormal {
+			c[2] = -1
+		}
+		if direct {
+			return uint32(c[0])
+		}
+		i, ok := index[c]
+		if !ok {
+			i = uint32(len(m.Positions))
+			index[c] = i
+			m.Positions = append(m.Positions, positions[c[0]])
+			if len(colors) > 0 {
+				m.Colors = append(m.Colors, colors[c[0]])
+			}
+			if hasUV {
+				m.UVs = append(m.UVs, uvs[c[1]])
+			}
+			if hasNormal {
+				m.Normals = append(m.Normals, normals[c[2]])
+			}
+		}
+		return i
+	}
+	if direct && (hasUV && len(uvs) < len(positions) || hasNormal && len(normals) < len(positions)) {
+		direct = false
+	}
+	if direct {
+		// Position, uv and normal indices agree, so keep the vertices in
+		// the order of the file.
+		m.Positions = positions
+		m.Colors = colors
+		if hasUV {
+			m.UVs = uvs[:len(positions)]
+		}
+		if hasNormal {
+			m.Normals = normals[:len(positions)]
+		}
+	}
+
+	for _, n := range faces {
+		face := corners[:n]
+		corners = corners[n:]
+		first := vertex(face[0])
+		for k := 1; k+1 < n; k++ {
+			m.Indices = append(m.Indices, first, vertex(face[k]), vertex(face[k+1]))
+		}
+	}
+	return m, nil
+}
+
+func parseFloats(fields []string) ([]float64, error) {
+	v := make([]float64, len(fields))
+	for i, f := range fields {
+		var err error
+		if v[i], err = strconv.ParseFloat(f, 64); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// formatFloat formats f with the fewest digits that read back exactly.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func formatFloat32(f float32) string {
+	return strconv.FormatFloat(float64(f), 'g', -1, 32)
+}
@@ -0,0 +1,129 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+)
+
+// offsetMiterLimit is the largest ratio of the distance of a miter from
+// its vertex to the offset distance before Offset bevels the corner.
+const offsetMiterLimit = 4
+
+// Offset grows the region enclosed by the contours, which lie in the
+// z = 0 plane and are interpreted with the given TESS_WINDING_* rule, by
+// distance, or shrinks it for a negative distance. The z coordinates are
+// ignored. Convex corners of the grown region are shaped by join, with
+// round joins flattened to within tolerance; zero means a hundredth of the
+// distance. Miters reaching farther than four times the distance from
+// their vertex are beveled.
+//
+// Returned contours are counterclockwise for outer boundaries and
+// clockwise for holes. Parts of the region merge or vanish as needed.
+func Offset(contours []Contour, windingRule uint32, distance float64, join LineJoin, tolerance float64) ([]Contour, error) {
+	normal := [3]float64{0, 0, 1}
+
+	// Reduce the region to contours with the interior on their left.
+	flat := make([]Contour, len(contours))
+	for i, c := range contours {
+		flat[i] = make(Contour, len(c))
+		for j, v := range c {
+			flat[i][j] = [3]float64{v[0], v[1], 0}
+		}
+	}
+	rings, err := tessellateBoundary(flat, windingRule, normal)
+	if err != nil || distance == 0 {
+		return rings, err
+	}
+
+	if tolerance <= 0 {
+		tolerance = math.Abs(distance) / 100
+	}
+	raw := make([]Contour, 0, len(rings))
+	for _, r := range rings {
+		if c := offsetContour(r, distance, join, tolerance); len(c) > 2 {
+			raw = append(raw, c)
+		}
+	}
+
+	// The raw contours loop back on themselves around concave corners and
+	// where the region narrows. Loops which are part of the result wind
+	// counterclockwise, the others clockwise.
+	return tessellateBoundary(raw, TESS_WINDING_POSITIVE, normal)
+}
+
+// offsetContour moves each edge of the contour by distance to its right,
+// joining the moved edges around convex corners and connecting them
+// through the original vertex at concave ones.
+func offsetContour(c Contour, distance float64, join LineJoin, tolerance float64) Contour {
+	var points [][2]float64
+	for _, v := range c {
+		p := [2]float64{v[0], v[1]}
+		if len(points) == 0 || p != points[len(points)-1] {
+			points = append(points, p)
+		}
+	}
+	for len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	n := len(points)
+	if n < 3 {
+		return nil
+	}
+
+	var out [][2]float64
+	add := func(v, o [2]float64) {
+		out = append(out, [2]float64{v[0] + o[0], v[1] + o[1]})
+	}
+	for i, v := range points {
+		d0 := direction(points[(i+n-1)%n], v)
+		d1 := direction(v, points[(i+1)%n])
+		o0, o1 := leftNormal(d0, -distance), leftNormal(d1, -distance)
+		cross := d0[0]*d1[1] - d0[1]*d1[0]
+		dot := d0[0]*d1[0] + d0[1]*d1[1]
+
+		add(v, o0)
+		switch {
+		case math.Abs(cross) < 1e-12 && dot > 0:
+			continue
+		case math.Abs(cross) < 1e-12:
+			// The tip of a spike.
+			if distance > 0 && join == JoinRound {
+				out = appendArc(out, v, distance, distance, 0, math.Atan2(o0[1], o0[0]), math.Pi,
+					arcSegments(distance, math.Pi, tolerance))
+				continue
+			}
+			if distance < 0 {
+				out = append(out, v)
+			}
+		case cross*distance > 0:
+			// A corner the offset moves away from.
+			switch join {
+			case JoinRound:
+				r := math.Abs(distance)
+				sweep := math.Atan2(o0[0]*o1[1]-o0[1]*o1[0], o0[0]*o1[0]+o0[1]*o1[1])
+				out = appendArc(out, v, r, r, 0, math.Atan2(o0[1], o0[0]), sweep,
+					arcSegments(r, sweep, tolerance))
+				continue
+			case JoinMiter:
+				cos := math.Sqrt((1 + dot) / 2)
+				if cos > 0 && 1/cos <= offsetMiterLimit {
+					m := [2]float64{o0[0] + o1[0], o0[1] + o1[1]}
+					l := math.Abs(distance) / cos / math.Hypot(m[0], m[1])
+					add(v, [2]float64{m[0] * l, m[1] * l})
+				}
+			}
+		default:
+			out = append(out, v)
+		}
+		add(v, o1)
+	}
+
+	contour := make(Contour, len(out))
+	for i, p := range out {
+		contour[i] = [3]float64{p[0], p[1], 0}
+	}
+	return contour
+}
@@ -0,0 +1,64 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOffset(t *testing.T) {
+	clockwise := square(0, 0, 10)
+	reverseContour(clockwise)
+	hole := square(3, 3, 4)
+	reverseContour(hole)
+	withHole := []Contour{square(0, 0, 10), hole}
+	spike := Contour{{0, 0, 0}, {10, 0, 0}, {10, 4, 0}, {20, 5, 0}, {10, 6, 0}, {10, 10, 0}, {0, 10, 0}}
+	// Shrunk, the spike is a triangle on the inner square whose sides move
+	// in by sqrt(1.01)/2 in y. Grown, the sides of the spike turn by
+	// atan(10) at its base, where the moved edges overlap by a kite of
+	// 0.25*tan(atan(10)/2) each, and round joins turn by 2*pi+2*atan(10) in
+	// total.
+	spikeShrunk := 81 + 10*math.Pow(1.05-math.Sqrt(1.01)/2, 2)
+	spikeGrown := 110 + 0.5*(38+2*math.Sqrt(101)) + 0.125*(2*math.Pi+2*math.Atan(10)) - (math.Sqrt(101)-1)/20
+
+	tests := []struct {
+		name     string
+		contours []Contour
+		distance float64
+		join     LineJoin
+		area     float64
+	}{
+		{"zero", []Contour{square(0, 0, 10)}, 0, JoinMiter, 100},
+		{"miter", []Contour{square(0, 0, 10)}, 1, JoinMiter, 144},
+		{"bevel", []Contour{square(0, 0, 10)}, 1, JoinBevel, 142},
+		{"round", []Contour{square(0, 0, 10)}, 1, JoinRound, 140 + math.Pi},
+		{"shrink", []Contour{square(0, 0, 10)}, -1, JoinRound, 64},
+		{"clockwise", []Contour{clockwise}, 1, JoinMiter, 144},
+		{"vanish", []Contour{square(0, 0, 10)}, -6, JoinMiter, 0},
+		{"hole", withHole, 1, JoinMiter, 144 - 4},
+		{"hole shrink", withHole, -1, JoinMiter, 64 - 36},
+		{"hole closes", withHole, 2, JoinMiter, 196},
+		{"merge", []Contour{square(0, 0, 10), square(11, 0, 10)}, 1, JoinMiter, 23 * 12},
+		{"concave shrink", []Contour{spike}, -0.5, JoinMiter, spikeShrunk},
+		{"concave grow", []Contour{spike}, 0.5, JoinRound, spikeGrown},
+	}
+	for _, test := range tests {
+		contours, err := Offset(test.contours, TESS_WINDING_NONZERO, test.distance, test.join, 0.001)
+		if err != nil {
+			t.Errorf("%v: %v\n", test.name, err)
+			continue
+		}
+		area := contourArea(contours)
+		if math.Abs(area-test.area) > 0.01 {
+			t.Errorf("%v: expected area %v, got %v\n", test.name, test.area, area)
+		}
+		original := contourArea(test.contours)
+		if test.distance > 0 && area <= math.Abs(original) ||
+			test.distance < 0 && area >= math.Abs(original) {
+			t.Errorf("%v: area %v did not change as expected from %v\n", test.name, area, original)
+		}
+	}
+}
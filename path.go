@@ -0,0 +1,243 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+)
+
+// Path builds contours in the z = 0 plane from lines, Bezier curves and
+// elliptical arcs. Curves are flattened as they are added, so that no
+// point of a curve is farther than the tolerance from the resulting
+// polyline.
+type Path struct {
+	// Tolerance is the flattening tolerance in path units. It is used
+	// unless SetProjection was called.
+	Tolerance float64
+
+	subpaths []subpath
+	cur      subpath
+	start    [2]float64
+	view     *pathProjection
+}
+
+type subpath struct {
+	points [][2]float64
+	closed bool
+}
+
+// pathProjection holds the transformation to window coordinates used to
+// derive the flattening tolerance from a tolerance in pixels.
+type pathProjection struct {
+	pixels      float64
+	model, proj [16]float64
+	view        [4]int32
+}
+
+// Create a new path with the given flattening tolerance.
+func NewPath(tolerance float64) *Path {
+	return &Path{Tolerance: tolerance}
+}
+
+// SetProjection makes curves flatten to within pixels of the exact curve
+// once projected to window coordinates with the model, proj and view
+// arguments of Project. Curves farther from the viewer are then flattened
+// more coarsely. Passing nil matrices restores the use of Tolerance.
+func (p *Path) SetProjection(pixels float64, model, proj *[16]float64, view *[4]int32) {
+	if model == nil || proj == nil || view == nil {
+		p.view = nil
+		return
+	}
+	p.view = &pathProjection{pixels, *model, *proj, *view}
+}
+
+// MoveTo starts a new subpath at x, y.
+func (p *Path) MoveTo(x, y float64) {
+	p.endSubpath()
+	p.start = [2]float64{x, y}
+	p.cur.points = append(p.cur.points, p.start)
+}
+
+// LineTo adds a line from the current point to x, y.
+func (p *Path) LineTo(x, y float64) {
+	p.begin()
+	p.cur.points = append(p.cur.points, [2]float64{x, y})
+}
+
+// QuadTo adds a quadratic Bezier curve from the current point to x, y
+// with control point x1, y1.
+func (p *Path) QuadTo(x1, y1, x, y float64) {
+	p0 := p.begin()
+	p1, p2 := [2]float64{x1, y1}, [2]float64{x, y}
+	p.cur.points = flattenQuad(p.cur.points, p0, p1, p2, p.tolerance(p0, p1, p2))
+}
+
+// CubicTo adds a cubic Bezier curve from the current point to x, y with
+// control points x1, y1 and x2, y2.
+func (p *Path) CubicTo(x1, y1, x2, y2, x, y float64) {
+	p0 := p.begin()
+	p1, p2, p3 := [2]float64{x1, y1}, [2]float64{x2, y2}, [2]float64{x, y}
+	p.cur.points = flattenCubic(p.cur.points, p0, p1, p2, p3, p.tolerance(p0, p1, p2, p3))
+}
+
+// ArcTo adds an elliptical arc from the current point to x, y, like the
+// SVG arc command. The ellipse has radii rx and ry and its x axis is
+// rotated by rotation radians. Of the four arcs fitting these
+// constraints, largeArc selects one spanning more than 180 degrees and
+// sweep one turning counterclockwise, in a y up coordinate system.
+// Radii too small to reach x, y are scaled up.
+func (p *Path) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) {
+	p0 := p.begin()
+	p1 := [2]float64{x, y}
+	c, rx, ry, theta, delta, ok := arcCenter(p0, p1, rx, ry, rotation, largeArc, sweep)
+	if !ok {
+		if p0 != p1 {
+			p.cur.points = append(p.cur.points, p1)
+		}
+		return
+	}
+	mid := appendArc(nil, c, rx, ry, rotation, theta, delta/2, 1)[0]
+	n := arcSegments(math.Max(rx, ry), delta, p.tolerance(p0, mid, p1))
+	p.cur.points = appendArc(p.cur.points, c, rx, ry, rotation, theta, delta, n)
+	// Land exactly on the end point.
+	p.cur.points[len(p.cur.points)-1] = p1
+}
+
+// Close closes the current subpath with a line back to its start point.
+// Further segments start a new subpath at that point.
+func (p *Path) Close() {
+	if len(p.cur.points) == 0 {
+		return
+	}
+	p.cur.closed = true
+	p.endSubpath()
+}
+
+// Contours returns the flattened subpaths, including open ones, which a
+// Tesselator closes implicitly. Closing points repeating the start point
+// and subpaths with fewer than three points are left out.
+func (p *Path) Contours() []Contour {
+	var contours []Contour
+	for _, s := range p.allSubpaths() {
+		points := s.points
+		if len(points) > 1 && points[0] == points[len(points)-1] {
+			points = points[:len(points)-1]
+		}
+		if len(points) < 3 {
+			continue
+		}
+		c := make(Contour, len(points))
+		for i, v := range points {
+			c[i] = [3]float64{v[0], v[1], 0}
+		}
+		contours = append(contours, c)
+	}
+	return contours
+}
+
+// AddTo submits the contours of the path to a Tesselator between calls to
+// BeginPolygon and EndPolygon, made by the caller. The vertex data of each
+// vertex is an *AttribVertex without attributes, so that intersections
+// are handled without a combine callback.
+func (p *Path) AddTo(tess *Tesselator) error {
+	for _, c := range p.Contours() {
+		if err := tess.BeginContourChecked(); err != nil {
+			return err
+		}
+		for _, v := range c {
+			if err := tess.VertexChecked(v, &AttribVertex{Location: v}); err != nil {
+				return err
+			}
+		}
+		if err := tess.EndContourChecked(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tessellate triangulates the path with the given TESS_WINDING_* rule.
+func (p *Path) Tessellate(windingRule uint32) (*Mesh, error) {
+	return Tessellate(p.Contours(), windingRule, [3]float64{0, 0, 1})
+}
+
+// begin returns the current point, starting a subpath at the last start
+// point if there is none.
+func (p *Path) begin() [2]float64 {
+	if len(p.cur.points) == 0 {
+		p.cur.points = append(p.cur.points, p.start)
+	}
+	return p.cur.points[len(p.cur.points)-1]
+}
+
+func (p *Path) endSubpath() {
+	if len(p.cur.points) > 1 {
+		p.subpaths = append(p.subpaths, p.cur)
+	}
+	p.cur = subpath{}
+}
+
+// allSubpaths returns the finished subpaths and the current one.
+func (p *Path) allSubpaths() []subpath {
+	if len(p.cur.points) > 1 {
+		return append(p.subpaths[:len(p.subpaths):len(p.subpaths)], p.cur)
+	}
+	return p.subpaths
+}
+
+// tolerance returns the flattening tolerance for a curve with the given
+// control points.
+func (p *Path) tolerance(points ...[2]float64) float64 {
+	if p.view == nil {
+		return p.Tolerance
+	}
+	scale := 0.0
+	for _, v := range points {
+		scale = math.Max(scale, p.view.scale(v))
+	}
+	if scale == 0 || math.IsInf(scale, 0) || math.IsNaN(scale) {
+		return p.Tolerance
+	}
+	return p.view.pixels / scale
+}
+
+// scale estimates how many pixels a unit length at v covers in window
+// coordinates, in the direction it is stretched most.
+func (v *pathProjection) scale(p [2]float64) float64 {
+	h := 1e-6 * (1 + math.Abs(p[0]) + math.Abs(p[1]))
+	w0, ok0 := v.project(p[0], p[1])
+	wx, okx := v.project(p[0]+h, p[1])
+	wy, oky := v.project(p[0], p[1]+h)
+	if !ok0 || !okx || !oky {
+		return 0
+	}
+	dx := math.Hypot(wx[0]-w0[0], wx[1]-w0[1])
+	dy := math.Hypot(wy[0]-w0[0], wy[1]-w0[1])
+	return math.Max(dx, dy) / h
+}
+
+// project maps x, y, 0 to window coordinates like Project. It returns
+// false for points on or behind the eye plane.
+func (v *pathProjection) project(x, y float64) ([2]float64, bool) {
+	in := [4]float64{x, y, 0, 1}
+	var eye, clip [4]float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			eye[i] += v.model[j*4+i] * in[j]
+		}
+	}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			clip[i] += v.proj[j*4+i] * eye[j]
+		}
+	}
+	if clip[3] <= 0 {
+		return [2]float64{}, false
+	}
+	return [2]float64{
+		float64(v.view[0]) + float64(v.view[2])*(clip[0]/clip[3]+1)/2,
+		float64(v.view[1]) + float64(v.view[3])*(clip[1]/clip[3]+1)/2,
+	}, true
+}
@@ -0,0 +1,150 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPathLines(t *testing.T) {
+	p := NewPath(0.1)
+	p.MoveTo(0, 0)
+	p.LineTo(10, 0)
+	p.LineTo(10, 10)
+	p.LineTo(0, 10)
+	p.Close()
+	// A second subpath starting at the start point of the first.
+	p.LineTo(-10, 0)
+	p.LineTo(-10, -10)
+
+	contours := p.Contours()
+	if len(contours) != 2 || len(contours[0]) != 4 || len(contours[1]) != 3 {
+		t.Fatalf("Unexpected contours %v\n", contours)
+	}
+	mesh, err := p.Tessellate(TESS_WINDING_NONZERO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area := meshArea(mesh); area != 150 {
+		t.Errorf("Expected area 150, got %v\n", area)
+	}
+}
+
+func TestPathCurves(t *testing.T) {
+	const tolerance = 0.01
+	p := NewPath(tolerance)
+	p.MoveTo(0, 0)
+	p.CubicTo(0, 10, 10, 10, 10, 0)
+	p.QuadTo(5, -10, 0, 0)
+
+	contour := p.Contours()[0]
+	cubic := func(t float64) [2]float64 {
+		s := 1 - t
+		return [2]float64{3*s*t*t*10 + t*t*t*10, 3*s*s*t*10 + 3*s*t*t*10}
+	}
+	for i := 0; i <= 100; i++ {
+		c := cubic(float64(i) / 100)
+		d := math.Inf(1)
+		for j := 0; j+1 < len(contour); j++ {
+			a, b := contour[j], contour[j+1]
+			d = math.Min(d, segmentDistance(c, [2]float64{a[0], a[1]}, [2]float64{b[0], b[1]}))
+		}
+		if d > tolerance {
+			t.Errorf("Curve point %v is %v away from the polyline\n", c, d)
+		}
+	}
+
+	coarse := NewPath(10)
+	coarse.MoveTo(0, 0)
+	coarse.CubicTo(0, 10, 10, 10, 10, 0)
+	if n := len(coarse.Contours()); n != 0 {
+		t.Errorf("Expected a coarse curve to flatten to a line, got %v contours\n", n)
+	}
+}
+
+func TestPathArc(t *testing.T) {
+	p := NewPath(0.001)
+	p.MoveTo(-10, 0)
+	p.ArcTo(10, 10, 0, false, true, 10, 0)
+	p.ArcTo(5, 5, 0, false, true, -10, 0) // radii scaled up
+	p.Close()
+
+	contours := p.Contours()
+	if len(contours) != 1 {
+		t.Fatalf("Expected one contour, got %v\n", len(contours))
+	}
+	for _, v := range contours[0] {
+		if r := math.Hypot(v[0], v[1]); math.Abs(r-10) > 1e-9 {
+			t.Errorf("Expected all vertices on the circle, got radius %v\n", r)
+		}
+	}
+	// Counterclockwise in a y up coordinate system.
+	if area := contourArea(contours); math.Abs(area-100*math.Pi) > 0.05 {
+		t.Errorf("Expected area %v, got %v\n", 100*math.Pi, area)
+	}
+}
+
+func TestPathProjection(t *testing.T) {
+	build := func(p *Path) int {
+		p.MoveTo(0, 0)
+		p.CubicTo(0, 1, 1, 1, 1, 0)
+		p.ArcTo(0.5, 0.5, 0, false, false, 0, 0)
+		return len(p.Contours()[0])
+	}
+
+	// An identity projection into a 200x200 viewport maps a unit length to
+	// 100 pixels.
+	identity := [16]float64{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+	view := [4]int32{0, 0, 200, 200}
+	projected := NewPath(1)
+	projected.SetProjection(1, &identity, &identity, &view)
+
+	if n, expected := build(projected), build(NewPath(0.01)); n != expected {
+		t.Errorf("Expected %v vertices, got %v\n", expected, n)
+	}
+
+	reset := NewPath(1)
+	reset.SetProjection(1, &identity, &identity, &view)
+	reset.SetProjection(0, nil, nil, nil)
+	if n, expected := build(reset), build(NewPath(1)); n != expected {
+		t.Errorf("Expected %v vertices, got %v\n", expected, n)
+	}
+}
+
+func TestPathAddTo(t *testing.T) {
+	// A self-intersecting bow tie, which needs a new vertex.
+	p := NewPath(0.1)
+	p.MoveTo(0, 0)
+	p.LineTo(10, 10)
+	p.LineTo(10, 0)
+	p.LineTo(0, 10)
+	p.Close()
+
+	tess := NewTess()
+	defer tess.Delete()
+
+	var vertices []*AttribVertex
+	tess.SetVertexCallback(func(vertexData interface{}, polygonData interface{}) {
+		vertices = append(vertices, vertexData.(*AttribVertex))
+	})
+
+	tess.BeginPolygon(nil)
+	if err := p.AddTo(tess); err != nil {
+		t.Fatal(err)
+	}
+	if err := tess.EndPolygon(); err != nil {
+		t.Fatal(err)
+	}
+	if len(vertices) != 6 {
+		t.Fatalf("Expected 6 vertices, got %v\n", len(vertices))
+	}
+	for _, v := range vertices {
+		if v.Location[0] == 5 && v.Location[1] == 5 {
+			return
+		}
+	}
+	t.Errorf("Expected a vertex at the intersection\n")
+}
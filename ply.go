@@ -0,0 +1,371 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WritePLY writes the mesh as an ASCII PLY file. Positions are written in
+// double precision, normals and UVs in single precision and colors as
+// bytes.
+func WritePLY(w io.Writer, m *Mesh) error {
+	return writePLY(w, m, false)
+}
+
+// WritePLYBinary writes the mesh as a little endian binary PLY file, like
+// WritePLY.
+func WritePLYBinary(w io.Writer, m *Mesh) error {
+	return writePLY(w, m, true)
+}
+
+func writePLY(w io.Writer, m *Mesh, binaryFormat bool) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("ply\n")
+	if binaryFormat {
+		bw.WriteString("format binary_little_endian 1.0\n")
+	} else {
+		bw.WriteString("format ascii 1.0\n")
+	}
+	bw.WriteString("comment glu\n")
+	fmt.Fprintf(bw, "element vertex %d\n", len(m.Positions))
+	bw.WriteString("property double x\nproperty double y\nproperty double z\n")
+	if len(m.Normals) > 0 {
+		bw.WriteString("property float nx\nproperty float ny\nproperty float nz\n")
+	}
+	if len(m.UVs) > 0 {
+		bw.WriteString("property float s\nproperty float t\n")
+	}
+	if len(m.Colors) > 0 {
+		bw.WriteString("property uchar red\nproperty uchar green\nproperty uchar blue\nproperty uchar alpha\n")
+	}
+	fmt.Fprintf(bw, "element face %d\n", m.TriangleCount())
+	bw.WriteString("property list uchar uint vertex_indices\nend_header\n")
+
+	// Each value is written as a double, float, byte or index.
+	var buf [8]byte
+	sep := ""
+	put := func(kind byte, f float64, i uint32) {
+		if !binaryFormat {
+			bw.WriteString(sep)
+			sep = " "
+			switch kind {
+			case 'd':
+				bw.WriteString(formatFloat(f))
+			case 'f':
+				bw.WriteString(formatFloat32(float32(f)))
+			default:
+				bw.WriteString(strconv.FormatUint(uint64(i), 10))
+			}
+			return
+		}
+		switch kind {
+		case 'd':
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(f))
+			bw.Write(buf[:8])
+		case 'f':
+			binary.LittleEndian.PutUint32(buf[:], math.Float32bits(float32(f)))
+			bw.Write(buf[:4])
+		case 'b':
+			bw.WriteByte(byte(i))
+		default:
+			binary.LittleEndian.PutUint32(buf[:], i)
+			bw.Write(buf[:4])
+		}
+	}
+	end := func() {
+		if !binaryFormat {
+			bw.WriteString("\n")
+			sep = ""
+		}
+	}
+
+	for i, p := range m.Positions {
+		for _, f := range p {
+			put('d', f, 0)
+		}
+		if len(m.Normals) > 0 {
+			for _, f := range m.Normals[i] {
+				put('f', f, 0)
+			}
+		}
+		if len(m.UVs) > 0 {
+			for _, f := range m.UVs[i] {
+				put('f', f, 0)
+			}
+		}
+		if len(m.Colors) > 0 {
+			for _, c := range m.Colors[i] {
+				put('b', 0, colorByte(c))
+			}
+		}
+		end()
+	}
+	for i := 0; i < len(m.Indices); i += 3 {
+		put('b', 0, 3)
+		for _, j := range m.Indices[i : i+3] {
+			put('i', 0, j)
+		}
+		end()
+	}
+	return bw.Flush()
+}
+
+func colorByte(c float32) uint32 {
+	return uint32(math.Round(math.Max(0, math.Min(1, float64(c))) * 255))
+}
+
+// plyProperty is a property of an element declared in a PLY header.
+type plyProperty struct {
+	name      string
+	typ       string
+	countType string // the type of the count of a list property
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// ReadPLY reads an ASCII or binary PLY file into a mesh. Vertex positions
+// x, y, z, normals nx, ny, nz, texture coordinates s, t or u, v and
+// colors red, green, blue and alpha are read from the vertex element, and
+// polygons from the vertex_indices or vertex_index list of the face
+// element, split into triangle fans. Other elements are skipped.
+func ReadPLY(r io.Reader) (*Mesh, error) {
+	br := bufio.NewReader(r)
+	invalid := fmt.Errorf("Invalid PLY header")
+
+	var format string
+	var elements []*plyElement
+	for n := 0; ; n++ {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, invalid
+		}
+		fields := strings.Fields(line)
+		if n == 0 {
+			if len(fields) != 1 || fields[0] != "ply" {
+				return nil, invalid
+			}
+			continue
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) != 3 {
+				return nil, invalid
+			}
+			format = fields[1]
+		case "element":
+			if len(fields) != 3 {
+				return nil, invalid
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, invalid
+			}
+			elements = append(elements, &plyElement{name: fields[1], count: count})
+		case "property":
+			if len(elements) == 0 {
+				return nil, invalid
+			}
+			e := elements[len(elements)-1]
+			var p plyProperty
+			switch {
+			case len(fields) == 5 && fields[1] == "list":
+				p = plyProperty{name: fields[4], typ: fields[3], countType: fields[2]}
+			case len(fields) == 3:
+				p = plyProperty{name: fields[2], typ: fields[1]}
+			default:
+				return nil, invalid
+			}
+			if plyTypeSize(p.typ) == 0 || p.countType != "" && plyTypeSize(p.countType) == 0 {
+				return nil, fmt.Errorf("Invalid PLY property type in %q", strings.TrimSpace(line))
+			}
+			e.properties = append(e.properties, p)
+		case "comment", "obj_info":
+		case "end_header":
+			return readPLYBody(br, format, elements)
+		default:
+			return nil, invalid
+		}
+	}
+}
+
+func readPLYBody(br *bufio.Reader, format string, elements []*plyElement) (*Mesh, error) {
+	var read func(typ string) (float64, error)
+	switch format {
+	case "ascii":
+		s := bufio.NewScanner(br)
+		s.Split(bufio.ScanWords)
+		read = func(typ string) (float64, error) {
+			if !s.Scan() {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return strconv.ParseFloat(s.Text(), 64)
+		}
+	case "binary_little_endian":
+		read = plyBinaryReader(br, binary.LittleEndian)
+	case "binary_big_endian":
+		read = plyBinaryReader(br, binary.BigEndian)
+	default:
+		return nil, fmt.Errorf("Unsupported PLY format %q", format)
+	}
+
+	m := &Mesh{}
+	for _, e := range elements {
+		index := make(map[string]int)
+		for i, p := range e.properties {
+			index[p.name] = i
+		}
+		has := func(names ...string) bool {
+			for _, name := range names {
+				if _, ok := index[name]; !ok {
+					return false
+				}
+			}
+			return true
+		}
+		isVertex := e.name == "vertex"
+		faceList := -1
+		if e.name == "face" {
+			if i, ok := index["vertex_indices"]; ok {
+				faceList = i
+			} else if i, ok := index["vertex_index"]; ok {
+				faceList = i
+			}
+		}
+		if isVertex && !has("x", "y", "z") {
+			return nil, fmt.Errorf("Invalid PLY vertex element without positions")
+		}
+		uvNames := []string{"s", "t"}
+		if !has(uvNames...) {
+			uvNames = []string{"u", "v"}
+		}
+		colorScale := float32(1)
+		if i, ok := index["red"]; ok && plyTypeSize(e.properties[i].typ) == 1 {
+			colorScale = 255
+		}
+
+		values := make([]float64, len(e.properties))
+		var list []float64
+		for n := 0; n < e.count; n++ {
+			for i, p := range e.properties {
+				if p.countType == "" {
+					v, err := read(p.typ)
+					if err != nil {
+						return nil, fmt.Errorf("Invalid PLY %v data: %v", e.name, err)
+					}
+					values[i] = v
+					continue
+				}
+				count, err := read(p.countType)
+				if err != nil || count < 0 {
+					return nil, fmt.Errorf("Invalid PLY %v data", e.name)
+				}
+				list = list[:0]
+				for k := 0; k < int(count); k++ {
+					v, err := read(p.typ)
+					if err != nil {
+						return nil, fmt.Errorf("Invalid PLY %v data: %v", e.name, err)
+					}
+					if i == faceList {
+						list = append(list, v)
+					}
+				}
+			}
+
+			get := func(name string) float64 { return values[index[name]] }
+			switch {
+			case isVertex:
+				m.Positions = append(m.Positions, [3]float64{get("x"), get("y"), get("z")})
+				if has("nx", "ny", "nz") {
+					m.Normals = append(m.Normals, [3]float64{get("nx"), get("ny"), get("nz")})
+				}
+				if has(uvNames...) {
+					m.UVs = append(m.UVs, [2]float64{get(uvNames[0]), get(uvNames[1])})
+				}
+				if has("red", "green", "blue") {
+					c := [4]float32{float32(get("red")), float32(get("green")), float32(get("blue")), colorScale}
+					if has("alpha") {
+						c[3] = float32(get("alpha"))
+					}
+					for k := range c {
+						c[k] /= colorScale
+					}
+					m.Colors = append(m.Colors, c)
+				}
+			case faceList >= 0:
+				if len(list) < 3 {
+					return nil, fmt.Errorf("Invalid PLY face with %d vertices", len(list))
+				}
+				for k := 1; k+1 < len(list); k++ {
+					m.Indices = append(m.Indices, uint32(list[0]), uint32(list[k]), uint32(list[k+1]))
+				}
+			}
+		}
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func plyBinaryReader(r io.Reader, order binary.ByteOrder) func(typ string) (float64, error) {
+	var buf [8]byte
+	return func(typ string) (float64, error) {
+		b := buf[:plyTypeSize(typ)]
+		if _, err := io.ReadFull(r, b); err != nil {
+			return 0, err
+		}
+		switch typ {
+		case "char", "int8":
+			return float64(int8(b[0])), nil
+		case "uchar", "uint8":
+			return float64(b[0]), nil
+		case "short", "int16":
+			return float64(int16(order.Uint16(b))), nil
+		case "ushort", "uint16":
+			return float64(order.Uint16(b)), nil
+		case "int", "int32":
+			return float64(int32(order.Uint32(b))), nil
+		case "uint", "uint32":
+			return float64(order.Uint32(b)), nil
+		case "float", "float32":
+			return float64(math.Float32frombits(order.Uint32(b))), nil
+		default:
+			return math.Float64frombits(order.Uint64(b)), nil
+		}
+	}
+}
+
+// plyTypeSize returns the size in bytes of a PLY scalar type, or zero for
+// an unknown type.
+func plyTypeSize(typ string) int {
+	switch typ {
+	case "char", "int8", "uchar", "uint8":
+		return 1
+	case "short", "int16", "ushort", "uint16":
+		return 2
+	case "int", "int32", "uint", "uint32", "float", "float32":
+		return 4
+	case "double", "float64":
+		return 8
+	}
+	return 0
+}
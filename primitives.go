@@ -0,0 +1,500 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"fmt"
+	"math"
+)
+
+// The primitive generators build closed meshes with outward normals, UVs
+// and tangents, smooth except across the rims of flat caps and the edges
+// of boxes. Shapes of revolution turn around the z axis. Their u runs
+// counterclockwise from the positive x axis, seen from the positive z
+// axis, and their v up the profile. Points on the axis get one vertex per
+// slice, in the middle of the slice. Flat caps are mapped as seen from
+// above, and bottom caps mirrored so that they read correctly from below.
+
+// UVSphereMesh returns the sphere of the given radius around the origin,
+// divided into slices around the z axis and stacks of equal angle from
+// pole to pole.
+func UVSphereMesh(radius float64, slices, stacks int) (*Mesh, error) {
+	if radius <= 0 || slices < 3 || stacks < 2 {
+		return nil, fmt.Errorf("Invalid sphere of radius %v with %d slices and %d stacks", radius, slices, stacks)
+	}
+	profile := make([]profilePoint, stacks+1)
+	for j := range profile {
+		profile[j] = spherePoint(radius, 0, -math.Pi/2+math.Pi*float64(j)/float64(stacks), j == 0, j == stacks)
+		profile[j].v = float64(j) / float64(stacks)
+	}
+	b := &shapeBuilder{}
+	b.revolve(profile, slices)
+	return &b.mesh, nil
+}
+
+// IcoSphereMesh returns the sphere of the given radius around the origin
+// made by splitting each triangle of an icosahedron into four the given
+// number of times, which has 20*4^subdivisions triangles. The icosahedron
+// has a vertex at each pole. UVs are those of UVSphereMesh, with the
+// vertices on the seam and at the poles repeated as needed.
+func IcoSphereMesh(radius float64, subdivisions int) (*Mesh, error) {
+	if radius <= 0 || subdivisions < 0 || subdivisions > 12 {
+		return nil, fmt.Errorf("Invalid sphere of radius %v with %d subdivisions", radius, subdivisions)
+	}
+
+	// The poles and two rings of five vertices, half a step apart.
+	points := [][3]float64{{0, 0, 1}}
+	z, r := 1/math.Sqrt(5), 2/math.Sqrt(5)
+	for k := 0; k < 10; k++ {
+		ring := float64(k / 5)
+		sin, cos := math.Sincos(2 * math.Pi * (float64(k%5) + ring/2) / 5)
+		points = append(points, [3]float64{r * cos, r * sin, z * (1 - 2*ring)})
+	}
+	points = append(points, [3]float64{0, 0, -1})
+	var faces [][3]uint32
+	for k := uint32(0); k < 5; k++ {
+		u, u1 := 1+k, 1+(k+1)%5
+		l, l1 := 6+k, 6+(k+1)%5
+		faces = append(faces, [3]uint32{0, u, u1}, [3]uint32{u, l, u1}, [3]uint32{l, l1, u1}, [3]uint32{11, l1, l})
+	}
+
+	for s := 0; s < subdivisions; s++ {
+		midpoints := make(map[[2]uint32]uint32)
+		midpoint := func(a, b uint32) uint32 {
+			key := [2]uint32{a, b}
+			if a > b {
+				key = [2]uint32{b, a}
+			}
+			i, ok := midpoints[key]
+			if !ok {
+				p, q := points[a], points[b]
+				i = uint32(len(points))
+				points = append(points, normalize([3]float64{p[0] + q[0], p[1] + q[1], p[2] + q[2]}))
+				midpoints[key] = i
+			}
+			return i
+		}
+		split := make([][3]uint32, 0, 4*len(faces))
+		for _, f := range faces {
+			ab, bc, ca := midpoint(f[0], f[1]), midpoint(f[1], f[2]), midpoint(f[2], f[0])
+			split = append(split, [3]uint32{f[0], ab, ca}, [3]uint32{ab, f[1], bc}, [3]uint32{ca, bc, f[2]}, [3]uint32{ab, bc, ca})
+		}
+		faces = split
+	}
+
+	// Vertices are told apart by u as well as point, as it differs on the
+	// seam and at the poles.
+	b := &shapeBuilder{}
+	index := make(map[icoVertex]uint32)
+	for _, f := range faces {
+		var u [3]float64
+		var poles []int
+		min, max := math.Inf(1), math.Inf(-1)
+		for k, i := range f {
+			p := points[i]
+			if p[0] == 0 && p[1] == 0 {
+				poles = append(poles, k)
+				continue
+			}
+			u[k] = math.Atan2(p[1], p[0]) / (2 * math.Pi)
+			if u[k] < 0 {
+				u[k]++
+			}
+			min, max = math.Min(min, u[k]), math.Max(max, u[k])
+		}
+		for k := range u {
+			if max-min > 0.5 && u[k] < 0.5 {
+				u[k]++
+			}
+		}
+		for _, k := range poles {
+			u[k] = (u[0] + u[1] + u[2] - u[k]) / 2
+		}
+
+		var tri [3]uint32
+		for k, i := range f {
+			key := icoVertex{i, u[k]}
+			j, ok := index[key]
+			if !ok {
+				p := points[i]
+				sin, cos := math.Sincos(2 * math.Pi * u[k])
+				j = b.add([3]float64{radius * p[0], radius * p[1], radius * p[2]}, p,
+					[2]float64{u[k], 0.5 + math.Asin(p[2])/math.Pi}, [3]float64{-sin, cos, 0})
+				index[key] = j
+			}
+			tri[k] = j
+		}
+		b.triangle(tri[0], tri[1], tri[2])
+	}
+	return &b.mesh, nil
+}
+
+// icoVertex identifies a vertex of IcoSphereMesh.
+type icoVertex struct {
+	point uint32
+	u     float64
+}
+
+// HemisphereMesh returns the upper half of the sphere of the given radius
+// around the origin, divided into slices around the z axis and stacks of
+// equal angle, closed by a disk in the plane z = 0.
+func HemisphereMesh(radius float64, slices, stacks int) (*Mesh, error) {
+	if radius <= 0 || slices < 3 || stacks < 1 {
+		return nil, fmt.Errorf("Invalid hemisphere of radius %v with %d slices and %d stacks", radius, slices, stacks)
+	}
+	profile := make([]profilePoint, stacks+1)
+	for j := range profile {
+		profile[j] = spherePoint(radius, 0, math.Pi/2*float64(j)/float64(stacks), false, j == stacks)
+		profile[j].v = float64(j) / float64(stacks)
+	}
+	b := &shapeBuilder{}
+	b.revolve(profile, slices)
+	b.cap(radius, 0, slices, false)
+	return &b.mesh, nil
+}
+
+// CapsuleMesh returns the cylinder of the given radius and length along the
+// z axis, centered on the origin, capped by hemispheres. It is divided into
+// slices around the axis and stacks of equal angle in each hemisphere. The
+// v coordinate is proportional to the distance along the profile.
+func CapsuleMesh(radius, length float64, slices, stacks int) (*Mesh, error) {
+	if radius <= 0 || length < 0 || slices < 3 || stacks < 1 {
+		return nil, fmt.Errorf("Invalid capsule of radius %v and length %v with %d slices and %d stacks", radius, length, slices, stacks)
+	}
+	total := math.Pi*radius + length
+	var profile []profilePoint
+	for j := 0; j <= stacks; j++ {
+		phi := math.Pi / 2 * float64(j) / float64(stacks)
+		p := spherePoint(radius, -length/2, phi-math.Pi/2, j == 0, false)
+		p.v = radius * phi / total
+		profile = append(profile, p)
+	}
+	for j := 0; j <= stacks; j++ {
+		if j == 0 && length == 0 {
+			continue
+		}
+		phi := math.Pi / 2 * float64(j) / float64(stacks)
+		p := spherePoint(radius, length/2, phi, false, j == stacks)
+		p.v = (math.Pi*radius/2 + length + radius*phi) / total
+		profile = append(profile, p)
+	}
+	b := &shapeBuilder{}
+	b.revolve(profile, slices)
+	return &b.mesh, nil
+}
+
+// TorusMesh returns the torus around the z axis, centered on the origin,
+// with the given distance from the axis to the middle of the tube and
+// radius of the tube. It is divided into slices around the axis and sides
+// around the tube, whose v starts and ends on the inside.
+func TorusMesh(majorRadius, minorRadius float64, slices, sides int) (*Mesh, error) {
+	if minorRadius <= 0 || majorRadius < minorRadius || slices < 3 || sides < 3 {
+		return nil, fmt.Errorf("Invalid torus of radii %v and %v with %d slices and %d sides", majorRadius, minorRadius, slices, sides)
+	}
+	profile := make([]profilePoint, sides+1)
+	for j := range profile {
+		// Start on the inside, half a turn around the tube from the
+		// outermost point.
+		sin, cos := sliceAngle(j, sides)
+		profile[j] = profilePoint{
+			r:  majorRadius - minorRadius*cos,
+			z:  -minorRadius * sin,
+			nr: -cos,
+			nz: -sin,
+			v:  float64(j) / float64(sides),
+		}
+	}
+	b := &shapeBuilder{}
+	b.revolve(profile, slices)
+	return &b.mesh, nil
+}
+
+// ConeMesh returns the cone of the given height standing on a disk of the
+// given radius around the origin in the plane z = 0. It is divided into
+// slices around the z axis and stacks of equal height.
+func ConeMesh(radius, height float64, slices, stacks int) (*Mesh, error) {
+	return FrustumMesh(radius, 0, height, slices, stacks)
+}
+
+// FrustumMesh returns the truncated cone of the given height standing on a
+// disk of radius bottom around the origin in the plane z = 0, with a top of
+// radius top. Either radius may be zero. It is divided into slices around
+// the z axis and stacks of equal height.
+func FrustumMesh(bottom, top, height float64, slices, stacks int) (*Mesh, error) {
+	if bottom < 0 || top < 0 || bottom+top == 0 || height <= 0 || slices < 3 || stacks < 1 {
+		return nil, fmt.Errorf("Invalid frustum of radii %v and %v and height %v with %d slices and %d stacks", bottom, top, height, slices, stacks)
+	}
+	l := math.Hypot(bottom-top, height)
+	profile := make([]profilePoint, stacks+1)
+	for j := range profile {
+		t := float64(j) / float64(stacks)
+		profile[j] = profilePoint{
+			r:  bottom + (top-bottom)*t,
+			z:  height * t,
+			nr: height / l,
+			nz: (bottom - top) / l,
+			v:  t,
+		}
+	}
+	profile[stacks].r = top
+
+	b := &shapeBuilder{}
+	b.revolve(profile, slices)
+	if bottom > 0 {
+		b.cap(bottom, 0, slices, false)
+	}
+	if top > 0 {
+		b.cap(top, height, slices, true)
+	}
+	return &b.mesh, nil
+}
+
+// boxFaces holds the outward normal and the directions of u and v of each
+// face of a box. The direction of v is the cross product of the other two,
+// and up for the sides.
+var boxFaces = [6][3][3]float64{
+	{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	{{-1, 0, 0}, {0, -1, 0}, {0, 0, 1}},
+	{{0, 1, 0}, {-1, 0, 0}, {0, 0, 1}},
+	{{0, -1, 0}, {1, 0, 0}, {0, 0, 1}},
+	{{0, 0, 1}, {1, 0, 0}, {0, 1, 0}},
+	{{0, 0, -1}, {1, 0, 0}, {0, -1, 0}},
+}
+
+// BoxMesh returns the box of the given size along x, y and z centered on
+// the origin, with each face divided into a grid of the given number of
+// segments along each axis. Each face is mapped to the whole texture.
+func BoxMesh(size [3]float64, segments [3]int) (*Mesh, error) {
+	var half [3]float64
+	var coords [3][]float64
+	for k := range size {
+		if size[k] <= 0 || segments[k] < 1 {
+			return nil, fmt.Errorf("Invalid box of size %v with %v segments", size, segments)
+		}
+		half[k] = size[k] / 2
+		for i := 0; i <= segments[k]; i++ {
+			coords[k] = append(coords[k], -half[k]+size[k]*float64(i)/float64(segments[k]))
+		}
+		coords[k][segments[k]] = half[k]
+	}
+	b := &shapeBuilder{}
+	for _, face := range boxFaces {
+		b.boxFace(face, half, coords, 0)
+	}
+	return &b.mesh, nil
+}
+
+// RoundedBoxMesh returns the box of BoxMesh with its edges and corners
+// rounded to the given radius, which is at most half the smallest size.
+// Each rounded edge is divided into 2*segments steps of equal angle. A zero
+// radius gives the box with undivided faces.
+func RoundedBoxMesh(size [3]float64, radius float64, segments int) (*Mesh, error) {
+	if radius == 0 {
+		return BoxMesh(size, [3]int{1, 1, 1})
+	}
+	var half [3]float64
+	var coords [3][]float64
+	for k := range size {
+		half[k] = size[k] / 2
+		if radius < 0 || radius > half[k] || segments < 1 {
+			return nil, fmt.Errorf("Invalid box of size %v rounded to %v with %d segments", size, radius, segments)
+		}
+		// Along each face, the rounding covers half of the edge, which
+		// the tangent spaces evenly in angle.
+		inner := half[k] - radius
+		for i := segments; i >= 0; i-- {
+			coords[k] = append(coords[k], -inner-radius*math.Tan(math.Pi/4*float64(i)/float64(segments)))
+		}
+		for i := 0; i <= segments; i++ {
+			if i > 0 || inner > 0 {
+				coords[k] = append(coords[k], inner+radius*math.Tan(math.Pi/4*float64(i)/float64(segments)))
+			}
+		}
+		coords[k][0], coords[k][len(coords[k])-1] = -half[k], half[k]
+	}
+	b := &shapeBuilder{}
+	for _, face := range boxFaces {
+		b.boxFace(face, half, coords, radius)
+	}
+	return &b.mesh, nil
+}
+
+// profilePoint is a point of the profile of a shape of revolution, at
+// distance r from the z axis and height z, with the normal (nr, nz) and
+// texture coordinate v.
+type profilePoint struct {
+	r, z, nr, nz, v float64
+}
+
+// spherePoint returns the point of the profile of a sphere of the given
+// radius centered at height z at the given latitude. The south and north
+// poles are placed exactly on the axis.
+func spherePoint(radius, z, latitude float64, south, north bool) profilePoint {
+	sin, cos := math.Sincos(latitude)
+	switch {
+	case south:
+		sin, cos = -1, 0
+	case north:
+		sin, cos = 1, 0
+	}
+	return profilePoint{r: radius * cos, z: z + radius*sin, nr: cos, nz: sin}
+}
+
+// shapeBuilder collects the vertices and triangles of a primitive.
+type shapeBuilder struct {
+	mesh Mesh
+}
+
+// add appends a vertex whose bitangent is the cross product of its normal
+// and tangent, and returns its index.
+func (b *shapeBuilder) add(position, normal [3]float64, uv [2]float64, tangent [3]float64) uint32 {
+	b.mesh.Positions = append(b.mesh.Positions, position)
+	b.mesh.Normals = append(b.mesh.Normals, normal)
+	b.mesh.UVs = append(b.mesh.UVs, uv)
+	b.mesh.Tangents = append(b.mesh.Tangents, [4]float64{tangent[0], tangent[1], tangent[2], 1})
+	return uint32(len(b.mesh.Positions) - 1)
+}
+
+func (b *shapeBuilder) triangle(i, j, k uint32) {
+	b.mesh.Indices = append(b.mesh.Indices, i, j, k)
+}
+
+// revolve adds the surface swept by turning the profile around the z axis,
+// divided into slices. The outward normal lies to the right of the profile
+// as it is walked in the r, z plane.
+func (b *shapeBuilder) revolve(profile []profilePoint, slices int) {
+	rows := make([]uint32, len(profile))
+	for j, p := range profile {
+		rows[j] = uint32(len(b.mesh.Positions))
+		for i := 0; i <= slices; i++ {
+			u := float64(i) / float64(slices)
+			sin, cos := sliceAngle(i, slices)
+			if p.r == 0 {
+				if i == slices {
+					break
+				}
+				u = (float64(i) + 0.5) / float64(slices)
+				sin, cos = math.Sincos(2 * math.Pi * u)
+			}
+			b.add([3]float64{p.r * cos, p.r * sin, p.z},
+				[3]float64{p.nr * cos, p.nr * sin, p.nz},
+				[2]float64{u, p.v}, [3]float64{-sin, cos, 0})
+		}
+	}
+	for j := 0; j+1 < len(profile); j++ {
+		low, high := profile[j].r == 0, profile[j+1].r == 0
+		for i := uint32(0); i < uint32(slices); i++ {
+			a, d := rows[j]+i, rows[j+1]+i
+			switch {
+			case low && high:
+			case low:
+				b.triangle(a, d+1, d)
+			case high:
+				b.triangle(a, a+1, d)
+			default:
+				b.triangle(a, a+1, d+1)
+				b.triangle(a, d+1, d)
+			}
+		}
+	}
+}
+
+// cap adds the disk of the given radius around the z axis at height z,
+// facing up or down, divided into slices around its center.
+func (b *shapeBuilder) cap(radius, z float64, slices int, up bool) {
+	sign := 1.0
+	if !up {
+		sign = -1
+	}
+	normal := [3]float64{0, 0, sign}
+	center := b.add([3]float64{0, 0, z}, normal, [2]float64{0.5, 0.5}, [3]float64{1, 0, 0})
+	for i := 0; i < slices; i++ {
+		sin, cos := sliceAngle(i, slices)
+		b.add([3]float64{radius * cos, radius * sin, z}, normal,
+			[2]float64{0.5 + cos/2, 0.5 + sign*sin/2}, [3]float64{1, 0, 0})
+	}
+	for i := uint32(0); i < uint32(slices); i++ {
+		p, q := center+1+i, center+1+(i+1)%uint32(slices)
+		if up {
+			b.triangle(center, p, q)
+		} else {
+			b.triangle(center, q, p)
+		}
+	}
+}
+
+// boxFace adds the face of a box of half extents half with the normal and
+// directions of u and v in face, on the grid of the given coordinates
+// along each axis. A radius rounds the edges and corners of the box.
+func (b *shapeBuilder) boxFace(face [3][3]float64, half [3]float64, coords [3][]float64, radius float64) {
+	n, du, dv := face[0], face[1], face[2]
+	ku, kv := boxAxis(du), boxAxis(dv)
+	us, vs := coords[ku], coords[kv]
+
+	first := uint32(len(b.mesh.Positions))
+	for _, cv := range vs {
+		for _, cu := range us {
+			var p [3]float64
+			for k := range p {
+				p[k] = n[k]*half[k] + du[k]*cu + dv[k]*cv
+			}
+			position, normal, tangent := p, n, du
+			if radius > 0 {
+				// Push the point out from the nearest point of the box
+				// shrunk by radius.
+				var c, d [3]float64
+				for k := range p {
+					inner := half[k] - radius
+					c[k] = math.Max(-inner, math.Min(inner, p[k]))
+					d[k] = p[k] - c[k]
+				}
+				normal = normalize(d)
+				s := dot(du, normal)
+				for k := range p {
+					position[k] = c[k] + radius*normal[k]
+					tangent[k] = du[k] - s*normal[k]
+				}
+				tangent = normalize(tangent)
+			}
+			b.add(position, normal,
+				[2]float64{(cu + half[ku]) / (2 * half[ku]), (cv + half[kv]) / (2 * half[kv])}, tangent)
+		}
+	}
+	w := uint32(len(us))
+	for j := uint32(0); j+1 < uint32(len(vs)); j++ {
+		for i := uint32(0); i+1 < w; i++ {
+			a := first + j*w + i
+			b.triangle(a, a+1, a+w+1)
+			b.triangle(a, a+w+1, a+w)
+		}
+	}
+}
+
+// boxAxis returns the index of the axis along the unit vector v.
+func boxAxis(v [3]float64) int {
+	for k := range v {
+		if v[k] != 0 {
+			return k
+		}
+	}
+	return 0
+}
+
+func dot(a, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+// normalize returns v scaled to unit length, or zero for a zero vector.
+func normalize(v [3]float64) [3]float64 {
+	l := math.Sqrt(dot(v, v))
+	if l == 0 {
+		return [3]float64{}
+	}
+	return [3]float64{v[0] / l, v[1] / l, v[2] / l}
+}
@@ -0,0 +1,209 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestPrimitives(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		mesh   func() (*Mesh, error)
+		volume float64
+	}{
+		{"uv sphere", func() (*Mesh, error) { return UVSphereMesh(1, 32, 16) }, 4 * math.Pi / 3},
+		{"coarse uv sphere", func() (*Mesh, error) { return UVSphereMesh(1, 3, 2) }, 0},
+		{"ico sphere", func() (*Mesh, error) { return IcoSphereMesh(1, 3) }, 4 * math.Pi / 3},
+		{"icosahedron", func() (*Mesh, error) { return IcoSphereMesh(1, 0) }, 0},
+		{"hemisphere", func() (*Mesh, error) { return HemisphereMesh(1, 32, 8) }, 2 * math.Pi / 3},
+		{"capsule", func() (*Mesh, error) { return CapsuleMesh(1, 2, 32, 8) }, 2*math.Pi + 4*math.Pi/3},
+		{"capsule without length", func() (*Mesh, error) { return CapsuleMesh(1, 0, 32, 8) }, 4 * math.Pi / 3},
+		{"torus", func() (*Mesh, error) { return TorusMesh(2, 0.5, 48, 24) }, math.Pi * math.Pi},
+		{"horn torus", func() (*Mesh, error) { return TorusMesh(1, 1, 48, 24) }, 2 * math.Pi * math.Pi},
+		{"cone", func() (*Mesh, error) { return ConeMesh(1, 2, 32, 3) }, 2 * math.Pi / 3},
+		{"frustum", func() (*Mesh, error) { return FrustumMesh(1, 0.5, 1, 32, 2) }, 1.75 * math.Pi / 3},
+		{"upside down cone", func() (*Mesh, error) { return FrustumMesh(0, 1, 2, 32, 1) }, 2 * math.Pi / 3},
+		{"box", func() (*Mesh, error) { return BoxMesh([3]float64{1, 2, 3}, [3]int{2, 3, 4}) }, 6},
+		{"rounded box", func() (*Mesh, error) { return RoundedBoxMesh([3]float64{2, 2, 4}, 0.5, 4) }, 10 + 17*math.Pi/12},
+		{"round box", func() (*Mesh, error) { return RoundedBoxMesh([3]float64{2, 2, 2}, 1, 6) }, 4 * math.Pi / 3},
+		{"sharp box", func() (*Mesh, error) { return RoundedBoxMesh([3]float64{2, 2, 2}, 0, 6) }, 8},
+	} {
+		m, err := test.mesh()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		checkSolid(t, test.name, m)
+		if volume := meshVolume(m); test.volume != 0 && math.Abs(volume-test.volume) > 0.02*test.volume {
+			t.Errorf("%s: expected a volume near %v, got %v\n", test.name, test.volume, volume)
+		}
+	}
+}
+
+func TestPrimitiveCounts(t *testing.T) {
+	for _, test := range []struct {
+		name                string
+		mesh                func() (*Mesh, error)
+		vertices, triangles int
+	}{
+		// One vertex per slice at each pole, and none in the middle of the
+		// polar triangle fans.
+		{"uv sphere", func() (*Mesh, error) { return UVSphereMesh(1, 8, 4) }, 2*8 + 3*9, 2*8 + 2*2*8},
+		{"ico sphere", func() (*Mesh, error) { return IcoSphereMesh(1, 2) }, 0, 20 * 16},
+		{"cone", func() (*Mesh, error) { return ConeMesh(1, 1, 8, 1) }, 9 + 8 + 1 + 8, 8 + 8},
+		{"box", func() (*Mesh, error) { return BoxMesh([3]float64{1, 1, 1}, [3]int{1, 2, 3}) }, 2 * (6 + 8 + 12), 2 * 2 * (2 + 3 + 6)},
+	} {
+		m, err := test.mesh()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.vertices != 0 && len(m.Positions) != test.vertices || m.TriangleCount() != test.triangles {
+			t.Errorf("%s: expected %d vertices and %d triangles, got %d and %d\n",
+				test.name, test.vertices, test.triangles, len(m.Positions), m.TriangleCount())
+		}
+	}
+}
+
+func TestPrimitiveErrors(t *testing.T) {
+	for i, f := range []func() (*Mesh, error){
+		func() (*Mesh, error) { return UVSphereMesh(1, 2, 4) },
+		func() (*Mesh, error) { return IcoSphereMesh(0, 1) },
+		func() (*Mesh, error) { return IcoSphereMesh(1, -1) },
+		func() (*Mesh, error) { return HemisphereMesh(1, 8, 0) },
+		func() (*Mesh, error) { return CapsuleMesh(1, -1, 8, 2) },
+		func() (*Mesh, error) { return TorusMesh(1, 2, 8, 8) },
+		func() (*Mesh, error) { return FrustumMesh(0, 0, 1, 8, 1) },
+		func() (*Mesh, error) { return ConeMesh(1, 0, 8, 1) },
+		func() (*Mesh, error) { return BoxMesh([3]float64{1, 0, 1}, [3]int{1, 1, 1}) },
+		func() (*Mesh, error) { return BoxMesh([3]float64{1, 1, 1}, [3]int{1, 0, 1}) },
+		func() (*Mesh, error) { return RoundedBoxMesh([3]float64{1, 1, 1}, 0.6, 2) },
+		func() (*Mesh, error) { return RoundedBoxMesh([3]float64{1, 1, 1}, 0.2, 0) },
+	} {
+		if _, err := f(); err == nil {
+			t.Errorf("%d: expected an error\n", i)
+		}
+	}
+}
+
+func TestPrimitiveTangentsGLTF(t *testing.T) {
+	m, err := BoxMesh([3]float64{1, 1, 1}, [3]int{1, 1, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc, bin bytes.Buffer
+	if err := WriteGLTF(&doc, &bin, "box.bin", m); err != nil {
+		t.Fatal(err)
+	}
+	var a gltfAsset
+	if err := json.Unmarshal(doc.Bytes(), &a); err != nil {
+		t.Fatal(err)
+	}
+	accessor, ok := a.Meshes[0].Primitives[0].Attributes["TANGENT"]
+	if !ok {
+		t.Fatalf("Expected a TANGENT attribute, got %v\n", a.Meshes[0].Primitives[0].Attributes)
+	}
+	// The texture is flipped vertically, and the bitangent with it.
+	view := a.BufferViews[a.Accessors[accessor].BufferView]
+	tangents := make([][4]float32, len(m.Positions))
+	binary.Read(bytes.NewReader(bin.Bytes()[view.ByteOffset:]), binary.LittleEndian, tangents)
+	if tangents[0] != [4]float32{0, 1, 0, -1} {
+		t.Errorf("Expected the tangent (0, 1, 0, -1), got %v\n", tangents[0])
+	}
+}
+
+// checkSolid checks that m is closed and consistently wound around its
+// normals, and that its tangents follow its UVs.
+func checkSolid(t *testing.T, name string, m *Mesh) {
+	if err := m.validate(); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if len(m.Normals) == 0 || len(m.UVs) == 0 || len(m.Tangents) == 0 {
+		t.Fatalf("%s: expected normals, UVs and tangents\n", name)
+	}
+
+	// Every edge is matched by one in the opposite direction, between
+	// vertices at the same places.
+	type point [3]float64
+	at := func(i uint32) point {
+		var p point
+		for k, x := range m.Positions[i] {
+			p[k] = math.Round(x*1e6) / 1e6
+		}
+		return p
+	}
+	edges := make(map[[2]point]int)
+	for i := 0; i < len(m.Indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			a, b := at(m.Indices[i+k]), at(m.Indices[i+(k+1)%3])
+			if a == b {
+				t.Errorf("%s: triangle %d is degenerate\n", name, i/3)
+				continue
+			}
+			edges[[2]point{a, b}]++
+		}
+	}
+	for e, n := range edges {
+		if edges[[2]point{e[1], e[0]}] != n {
+			t.Errorf("%s: edge %v is open\n", name, e)
+			break
+		}
+	}
+
+	for i, n := range m.Normals {
+		tangent := m.Tangents[i]
+		tv := [3]float64{tangent[0], tangent[1], tangent[2]}
+		if math.Abs(dot(n, n)-1) > 1e-9 || math.Abs(dot(tv, tv)-1) > 1e-9 || math.Abs(dot(n, tv)) > 1e-9 || math.Abs(tangent[3]) != 1 {
+			t.Errorf("%s: vertex %d has normal %v and tangent %v\n", name, i, n, tangent)
+			return
+		}
+	}
+
+	for i := 0; i < m.TriangleCount(); i++ {
+		tri := m.Indices[3*i : 3*i+3]
+		p0, p1, p2 := m.Positions[tri[0]], m.Positions[tri[1]], m.Positions[tri[2]]
+		uv0, uv1, uv2 := m.UVs[tri[0]], m.UVs[tri[1]], m.UVs[tri[2]]
+		var e1, e2 [3]float64
+		for k := range e1 {
+			e1[k], e2[k] = p1[k]-p0[k], p2[k]-p0[k]
+		}
+		du1, dv1, du2, dv2 := uv1[0]-uv0[0], uv1[1]-uv0[1], uv2[0]-uv0[0], uv2[1]-uv0[1]
+		det := du1*dv2 - du2*dv1
+		if math.Abs(det) < 1e-12 {
+			t.Errorf("%s: triangle %d has degenerate UVs %v %v %v\n", name, i, uv0, uv1, uv2)
+			continue
+		}
+		var dpdu, dpdv [3]float64
+		for k := range dpdu {
+			dpdu[k] = (e1[k]*dv2 - e2[k]*dv1) / det
+			dpdv[k] = (e2[k]*du1 - e1[k]*du2) / det
+		}
+		face := m.faceNormal(i)
+		for _, j := range tri {
+			n, tangent := m.Normals[j], m.Tangents[j]
+			tv := [3]float64{tangent[0], tangent[1], tangent[2]}
+			bitangent := cross(n, tv)
+			if dot(face, n) <= 0 || dot(tv, dpdu) <= 0 || tangent[3]*dot(bitangent, dpdv) <= 0 {
+				t.Errorf("%s: vertex %d of triangle %d has normal %v and tangent %v, the face has normal %v and UV gradients %v and %v\n",
+					name, j, i, n, tangent, face, dpdu, dpdv)
+				return
+			}
+		}
+	}
+}
+
+// meshVolume returns the volume enclosed by a closed mesh wound
+// counterclockwise when seen from outside.
+func meshVolume(m *Mesh) float64 {
+	var volume float64
+	for i := 0; i < m.TriangleCount(); i++ {
+		tri := m.Triangle(i)
+		volume += dot(tri[0], cross(tri[1], tri[2])) / 6
+	}
+	return volume
+}
@@ -0,0 +1,177 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+// #ifdef __APPLE__
+// #define GL_SILENCE_DEPRECATION
+//   #include <OpenGL/glu.h>
+// #else
+//   #include <GL/glu.h>
+// #endif
+// #include "callback.h"
+import "C"
+import (
+	"runtime"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
+// QuadricDrawStyle is a value for Quadric.SetDrawStyle.
+type QuadricDrawStyle uint32
+
+const (
+	DrawFill       QuadricDrawStyle = FILL       // filled polygons
+	DrawLine       QuadricDrawStyle = LINE       // wireframe lines
+	DrawSilhouette QuadricDrawStyle = SILHOUETTE // lines, without those between coplanar faces
+	DrawPoint      QuadricDrawStyle = POINT      // vertices as points
+)
+
+// QuadricNormals is a value for Quadric.SetNormals.
+type QuadricNormals uint32
+
+const (
+	NormalsSmooth QuadricNormals = SMOOTH // one normal per vertex
+	NormalsFlat   QuadricNormals = FLAT   // one normal per face
+	NormalsNone   QuadricNormals = NONE   // no normals
+)
+
+// Quadric holds the GLUquadric object.
+type Quadric struct {
+	quadric *C.GLUquadric
+
+	// handle identifies the object to error callbacks.
+	handle cgo.Handle
+
+	errorData QuadricErrorHandler
+}
+
+// NewQuadricObject creates a new quadric object. It draws filled, smooth
+// shaded, outward facing and untextured quadrics until told otherwise.
+func NewQuadricObject() *Quadric {
+	q := &Quadric{
+		quadric: C.gluNewQuadric(),
+	}
+
+	if q.quadric == nil {
+		panic("Out of memory or GLU not initialized.")
+	}
+	q.handle = cgo.NewHandle(q)
+	quadrics.Store(q.quadric, q)
+
+	return q
+}
+
+// Delete deletes the quadric object.
+func (q *Quadric) Delete() {
+	quadrics.Delete(q.quadric)
+	C.gluDeleteQuadric(q.quadric)
+	q.handle.Delete()
+	q.quadric = nil
+}
+
+// Pointer returns the GLUquadric object, for the deprecated functions
+// which take one. Their errors go to the callback of q.
+func (q *Quadric) Pointer() unsafe.Pointer {
+	return unsafe.Pointer(q.quadric)
+}
+
+// SetDrawStyle sets how quadrics are drawn.
+func (q *Quadric) SetDrawStyle(style QuadricDrawStyle) {
+	defer q.bind()()
+	C.gluQuadricDrawStyle(q.quadric, C.GLenum(style))
+}
+
+// SetNormals sets which normals are generated.
+func (q *Quadric) SetNormals(normals QuadricNormals) {
+	defer q.bind()()
+	C.gluQuadricNormals(q.quadric, C.GLenum(normals))
+}
+
+// SetOrientation sets which way normals point.
+func (q *Quadric) SetOrientation(orientation QuadricOrientation) {
+	defer q.bind()()
+	C.gluQuadricOrientation(q.quadric, C.GLenum(orientation))
+}
+
+// SetTexture sets whether texture coordinates are generated.
+func (q *Quadric) SetTexture(enabled bool) {
+	defer q.bind()()
+	var texture C.GLboolean
+	if enabled {
+		texture = 1
+	}
+	C.gluQuadricTexture(q.quadric, texture)
+}
+
+// SetErrorCallback sets the callback for ERROR, which GLU calls with an
+// error code such as INVALID_ENUM or INVALID_VALUE.
+func (q *Quadric) SetErrorCallback(f QuadricErrorHandler) {
+	if q.quadric == nil {
+		panic("Uninitialised Quadric. @see glu.NewQuadricObject.")
+	}
+	q.errorData = f
+	C.setGluQuadricErrorCallback(q.quadric)
+}
+
+// Sphere draws a sphere of the given radius around the origin, divided
+// into slices around the z axis and stacks along it.
+func (q *Quadric) Sphere(radius float64, slices, stacks int) {
+	defer q.bind()()
+	C.gluSphere(q.quadric, C.GLdouble(radius), C.GLint(slices), C.GLint(stacks))
+}
+
+// Cylinder draws a cylinder along the z axis from z = 0, of radius base,
+// to z = height, of radius top, divided into slices around the axis and
+// stacks along it.
+func (q *Quadric) Cylinder(base, top, height float64, slices, stacks int) {
+	defer q.bind()()
+	C.gluCylinder(q.quadric, C.GLdouble(base), C.GLdouble(top), C.GLdouble(height), C.GLint(slices), C.GLint(stacks))
+}
+
+// Disk draws a disk in the z = 0 plane between the radii inner and outer,
+// divided into slices around the origin and concentric loops.
+func (q *Quadric) Disk(inner, outer float64, slices, loops int) {
+	defer q.bind()()
+	C.gluDisk(q.quadric, C.GLdouble(inner), C.GLdouble(outer), C.GLint(slices), C.GLint(loops))
+}
+
+// PartialDisk draws part of a disk, from startAngle over sweepAngle
+// degrees clockwise from the positive y axis.
+func (q *Quadric) PartialDisk(inner, outer float64, slices, loops int, startAngle, sweepAngle float64) {
+	defer q.bind()()
+	C.gluPartialDisk(q.quadric, C.GLdouble(inner), C.GLdouble(outer), C.GLint(slices), C.GLint(loops), C.GLdouble(startAngle), C.GLdouble(sweepAngle))
+}
+
+// quadrics maps the GLUquadric objects of NewQuadricObject to their
+// Quadric, for the deprecated functions which take the objects.
+var quadrics sync.Map
+
+// bind makes q the target of ERROR callbacks, which GLU raises without
+// user data, until the returned function is called. The calling goroutine
+// stays on its thread in between.
+func (q *Quadric) bind() func() {
+	return bindQuadricHandle(q.handle)
+}
+
+// bindQuadric binds the Quadric of the GLUquadric object q for the
+// deprecated functions. Errors on objects from NewQuadric are dropped.
+func bindQuadric(q unsafe.Pointer) func() {
+	if v, ok := quadrics.Load((*C.GLUquadric)(q)); ok {
+		return v.(*Quadric).bind()
+	}
+	return bindQuadricHandle(0)
+}
+
+// bindQuadricHandle makes h the target of ERROR callbacks until the
+// returned function restores the previous target.
+func bindQuadricHandle(h cgo.Handle) func() {
+	runtime.LockOSThread()
+	previous := C.setGluQuadricErrorTarget(C.uintptr_t(h))
+	return func() {
+		C.setGluQuadricErrorTarget(previous)
+		runtime.UnlockOSThread()
+	}
+}
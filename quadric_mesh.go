@@ -0,0 +1,243 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"fmt"
+	"math"
+)
+
+// QuadricOrientation is a value for Quadric.SetOrientation and the quadric
+// mesh generators.
+type QuadricOrientation uint32
+
+const (
+	OrientationOutside QuadricOrientation = OUTSIDE // normals point away from the center
+	OrientationInside  QuadricOrientation = INSIDE  // normals point toward the center
+)
+
+// The quadric mesh generators build the triangles that Quadric draws with
+// smooth normals and texture coordinates enabled, with the same winding,
+// normals and texture coordinates. Vertices shared by neighbouring strips
+// are shared in the mesh; the zero-area triangles GLU draws at the poles
+// of a sphere are kept. Unlike GLU, slices, stacks and loops are not
+// limited to 239.
+
+// SphereMesh returns the sphere Quadric.Sphere draws: slice i lies at
+// 360*i/slices degrees clockwise from the positive y axis, seen from the
+// positive z axis, and stack j runs from 180*j/stacks to 180*(j+1)/stacks
+// degrees from the positive z axis. Texture coordinates go from
+// (1 - i/slices, 1 - j/stacks) at the top of a slice.
+func SphereMesh(radius float64, slices, stacks int, orientation QuadricOrientation) (*Mesh, error) {
+	sign, err := orientationSign(orientation)
+	if err != nil {
+		return nil, err
+	}
+	if slices < 2 || stacks < 1 || radius < 0 {
+		return nil, fmt.Errorf("Invalid sphere of radius %v with %d slices and %d stacks", radius, slices, stacks)
+	}
+
+	b := newQuadricBuilder((slices + 1) * (stacks + 1))
+	for j := 0; j <= stacks; j++ {
+		sinPhi, cosPhi := math.Sincos(math.Pi * float64(j) / float64(stacks))
+		if j == 0 || j == stacks {
+			// Make sure it comes to a point.
+			sinPhi = 0
+		}
+		for i := 0; i <= slices; i++ {
+			sinTheta, cosTheta := sliceAngle(i, slices)
+			n := [3]float64{sinPhi * sinTheta, sinPhi * cosTheta, cosPhi}
+			b.add([3]float64{radius * n[0], radius * n[1], radius * n[2]},
+				[3]float64{sign * n[0], sign * n[1], sign * n[2]},
+				[2]float64{1 - float64(i)/float64(slices), 1 - float64(j)/float64(stacks)})
+		}
+	}
+	for j := 0; j < stacks; j++ {
+		b.strip(j+1, j, slices+1, orientation)
+	}
+	return &b.mesh, nil
+}
+
+// CylinderMesh returns the cylinder Quadric.Cylinder draws: slice i lies at
+// 360*i/slices degrees clockwise from the positive y axis, seen from the
+// positive z axis, and stack j runs from z = height*j/stacks to
+// z = height*(j+1)/stacks. Texture coordinates go from
+// (1 - i/slices, j/stacks) at the bottom of a slice.
+func CylinderMesh(base, top, height float64, slices, stacks int, orientation QuadricOrientation) (*Mesh, error) {
+	sign, err := orientationSign(orientation)
+	if err != nil {
+		return nil, err
+	}
+	delta := base - top
+	length := math.Hypot(delta, height)
+	if slices < 2 || stacks < 1 || base < 0 || top < 0 || height < 0 || length == 0 {
+		return nil, fmt.Errorf("Invalid cylinder of radii %v and %v and height %v with %d slices and %d stacks", base, top, height, slices, stacks)
+	}
+
+	// Like GLU, OrientationInside reverses the radial part of the normals
+	// but keeps their slope along the axis.
+	xy, z := sign*height/length, delta/length
+	b := newQuadricBuilder((slices + 1) * (stacks + 1))
+	for j := 0; j <= stacks; j++ {
+		t := float64(j) / float64(stacks)
+		r := base - delta*t
+		for i := 0; i <= slices; i++ {
+			sinTheta, cosTheta := sliceAngle(i, slices)
+			b.add([3]float64{r * sinTheta, r * cosTheta, height * t},
+				[3]float64{xy * sinTheta, xy * cosTheta, z},
+				[2]float64{1 - float64(i)/float64(slices), t})
+		}
+	}
+	for j := 0; j < stacks; j++ {
+		b.strip(j, j+1, slices+1, orientation)
+	}
+	return &b.mesh, nil
+}
+
+// DiskMesh returns the disk Quadric.Disk draws, which is PartialDiskMesh
+// with a start angle of 0 and a sweep angle of 360 degrees.
+func DiskMesh(inner, outer float64, slices, loops int, orientation QuadricOrientation) (*Mesh, error) {
+	return PartialDiskMesh(inner, outer, slices, loops, 0, 360, orientation)
+}
+
+// PartialDiskMesh returns the partial disk Quadric.PartialDisk draws:
+// slice i lies at startAngle + sweepAngle*i/slices degrees clockwise from
+// the positive y axis, seen from the positive z axis, and loop j runs from
+// the outer radius inward. A point (x, y) has the texture coordinates
+// (0.5 + x/(2*outer), 0.5 + y/(2*outer)). A disk without a hole is closed
+// with a fan around a single center vertex, and a full disk shares the
+// vertices of its first and last slices.
+func PartialDiskMesh(inner, outer float64, slices, loops int, startAngle, sweepAngle float64, orientation QuadricOrientation) (*Mesh, error) {
+	sign, err := orientationSign(orientation)
+	if err != nil {
+		return nil, err
+	}
+	if slices < 2 || loops < 1 || outer <= 0 || inner < 0 || inner > outer {
+		return nil, fmt.Errorf("Invalid disk of radii %v and %v with %d slices and %d loops", inner, outer, slices, loops)
+	}
+
+	// GLU turns sweeps below -360 degrees into full counterclockwise ones.
+	if sweepAngle < -360 || sweepAngle > 360 {
+		sweepAngle = 360
+	}
+	if sweepAngle < 0 {
+		startAngle += sweepAngle
+		sweepAngle = -sweepAngle
+	}
+	full := sweepAngle == 360
+	ring := slices + 1
+	if full {
+		ring = slices
+	}
+	rings := loops + 1
+	if inner == 0 {
+		rings = loops
+	}
+
+	normal := [3]float64{0, 0, sign}
+	b := newQuadricBuilder(ring*rings + 1)
+	for j := 0; j < rings; j++ {
+		r := outer - (outer-inner)*float64(j)/float64(loops)
+		for i := 0; i < ring; i++ {
+			// The angles add up in the order GLU adds them.
+			sin, cos := math.Sincos(startAngle*math.Pi/180 + math.Pi*sweepAngle/180*float64(i)/float64(slices))
+			b.add([3]float64{r * sin, r * cos, 0}, normal,
+				[2]float64{0.5 + r*sin/(2*outer), 0.5 + r*cos/(2*outer)})
+		}
+	}
+	column := func(i int) int {
+		if full {
+			return i % slices
+		}
+		return i
+	}
+
+	if inner == 0 {
+		center := b.add([3]float64{}, normal, [2]float64{0.5, 0.5})
+		b.prims.begin(TRIANGLE_FAN)
+		b.vertex(center)
+		for k := 0; k <= slices; k++ {
+			i := slices - k
+			if orientation == OrientationInside {
+				i = k
+			}
+			b.vertex(uint32((loops-1)*ring + column(i)))
+		}
+		b.prims.end()
+	}
+	for j := 0; j+1 < rings; j++ {
+		b.prims.begin(QUAD_STRIP)
+		for i := 0; i <= slices; i++ {
+			low, high := uint32(j*ring+column(i)), uint32((j+1)*ring+column(i))
+			if orientation == OrientationInside {
+				low, high = high, low
+			}
+			b.vertex(low)
+			b.vertex(high)
+		}
+		b.prims.end()
+	}
+	return &b.mesh, nil
+}
+
+// quadricBuilder collects the vertices of a quadric and the triangles of
+// the primitives drawn with them.
+type quadricBuilder struct {
+	mesh  Mesh
+	prims primitiveAssembler
+}
+
+func newQuadricBuilder(vertices int) *quadricBuilder {
+	return &quadricBuilder{mesh: Mesh{
+		Positions: make([][3]float64, 0, vertices),
+		Normals:   make([][3]float64, 0, vertices),
+		UVs:       make([][2]float64, 0, vertices),
+	}}
+}
+
+// add appends a vertex and returns its index.
+func (b *quadricBuilder) add(position, normal [3]float64, uv [2]float64) uint32 {
+	b.mesh.Positions = append(b.mesh.Positions, position)
+	b.mesh.Normals = append(b.mesh.Normals, normal)
+	b.mesh.UVs = append(b.mesh.UVs, uv)
+	return uint32(len(b.mesh.Positions) - 1)
+}
+
+func (b *quadricBuilder) vertex(i uint32) {
+	b.mesh.Indices = b.prims.vertex(b.mesh.Indices, i)
+}
+
+// strip adds the quad strip between the rows of n vertices first and
+// second, which GLU draws in that order for OrientationOutside.
+func (b *quadricBuilder) strip(first, second, n int, orientation QuadricOrientation) {
+	if orientation == OrientationInside {
+		first, second = second, first
+	}
+	b.prims.begin(QUAD_STRIP)
+	for i := 0; i < n; i++ {
+		b.vertex(uint32(first*n + i))
+		b.vertex(uint32(second*n + i))
+	}
+	b.prims.end()
+}
+
+// sliceAngle returns the sine and cosine of slice i of a full turn, with
+// the last slice closing exactly on the first.
+func sliceAngle(i, slices int) (sin, cos float64) {
+	if i == slices {
+		i = 0
+	}
+	return math.Sincos(2 * math.Pi * float64(i) / float64(slices))
+}
+
+func orientationSign(orientation QuadricOrientation) (float64, error) {
+	switch orientation {
+	case OrientationOutside:
+		return 1, nil
+	case OrientationInside:
+		return -1, nil
+	}
+	return 0, fmt.Errorf("Invalid quadric orientation %d", orientation)
+}
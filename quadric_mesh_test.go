@@ -0,0 +1,133 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuadricMeshes(t *testing.T) {
+	for _, orientation := range []QuadricOrientation{OrientationOutside, OrientationInside} {
+		for _, test := range []struct {
+			name                string
+			mesh                func() (*Mesh, error)
+			vertices, triangles int
+			area                float64
+		}{
+			// Every quad of a strip makes two triangles.
+			{"sphere", func() (*Mesh, error) { return SphereMesh(2, 16, 8, orientation) }, 17 * 9, 2 * 16 * 8, 16 * math.Pi},
+			{"cylinder", func() (*Mesh, error) { return CylinderMesh(1, 1, 3, 16, 2, orientation) }, 17 * 3, 2 * 16 * 2, 6 * math.Pi},
+			{"cone", func() (*Mesh, error) { return CylinderMesh(3, 0, 4, 16, 2, orientation) }, 17 * 3, 2 * 16 * 2, 15 * math.Pi},
+			// A fan of slices triangles closes a disk without a hole.
+			{"disk", func() (*Mesh, error) { return DiskMesh(0, 2, 16, 3, orientation) }, 16*3 + 1, 16 + 2*16*2, 4 * math.Pi},
+			{"annulus", func() (*Mesh, error) { return DiskMesh(1, 2, 16, 3, orientation) }, 16 * 4, 2 * 16 * 3, 3 * math.Pi},
+			{"partial disk", func() (*Mesh, error) { return PartialDiskMesh(1, 2, 16, 1, 90, -180, orientation) }, 17 * 2, 2 * 16, 1.5 * math.Pi},
+			{"rotated disk", func() (*Mesh, error) { return PartialDiskMesh(1, 2, 16, 1, 45, -360, orientation) }, 16 * 2, 2 * 16, 3 * math.Pi},
+		} {
+			m, err := test.mesh()
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if err := m.validate(); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if len(m.Positions) != test.vertices || m.TriangleCount() != test.triangles {
+				t.Errorf("%s: expected %d vertices and %d triangles, got %d and %d\n",
+					test.name, test.vertices, test.triangles, len(m.Positions), m.TriangleCount())
+			}
+
+			// The faces wind counterclockwise around the normals, and
+			// approach the area of the quadric.
+			var area float64
+			for i := 0; i < m.TriangleCount(); i++ {
+				face := m.faceNormal(i)
+				for _, k := range m.Indices[3*i : 3*i+3] {
+					if n := m.Normals[k]; face != [3]float64{} && dot(face, n) <= 0 {
+						t.Errorf("%s: triangle %d faces %v against normal %v\n", test.name, i, face, n)
+					}
+				}
+				tri := m.Triangle(i)
+				u := [3]float64{tri[1][0] - tri[0][0], tri[1][1] - tri[0][1], tri[1][2] - tri[0][2]}
+				v := [3]float64{tri[2][0] - tri[0][0], tri[2][1] - tri[0][1], tri[2][2] - tri[0][2]}
+				area += length(cross(u, v)) / 2
+			}
+			if math.Abs(area-test.area) > 0.05*test.area {
+				t.Errorf("%s: expected an area near %v, got %v\n", test.name, test.area, area)
+			}
+		}
+	}
+}
+
+func TestQuadricMeshTexture(t *testing.T) {
+	m, err := SphereMesh(1, 4, 2, OrientationOutside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The seam at slice 0 and 4 lies along the positive y axis, with s
+	// falling from 1 to 0 around the sphere, and t rising to 1 at the top.
+	for _, v := range []struct {
+		index    int
+		position [3]float64
+		uv       [2]float64
+	}{
+		{0, [3]float64{0, 0, 1}, [2]float64{1, 1}},
+		{5, [3]float64{0, 1, 0}, [2]float64{1, 0.5}},
+		{6, [3]float64{1, 0, 0}, [2]float64{0.75, 0.5}},
+		{9, [3]float64{0, 1, 0}, [2]float64{0, 0.5}},
+		{14, [3]float64{0, 0, -1}, [2]float64{0, 0}},
+	} {
+		p, uv := m.Positions[v.index], m.UVs[v.index]
+		for k := range p {
+			if math.Abs(p[k]-v.position[k]) > 1e-12 {
+				t.Errorf("Expected vertex %d at %v, got %v\n", v.index, v.position, p)
+			}
+		}
+		if uv != v.uv {
+			t.Errorf("Expected vertex %d at %v in the texture, got %v\n", v.index, v.uv, uv)
+		}
+	}
+
+	m, err = PartialDiskMesh(0, 2, 2, 1, 0, 90, OrientationInside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if center := m.UVs[len(m.UVs)-1]; center != [2]float64{0.5, 0.5} {
+		t.Errorf("Expected the center at (0.5, 0.5), got %v\n", center)
+	}
+	if uv := m.UVs[0]; uv != [2]float64{0.5, 1} {
+		t.Errorf("Expected the start of the edge at (0.5, 1), got %v\n", uv)
+	}
+
+	// A full sweep still starts at startAngle.
+	m, err = PartialDiskMesh(0.5, 1, 4, 1, 90, -360, OrientationOutside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := m.Positions[0]; math.Abs(p[0]-1) > 1e-12 || math.Abs(p[1]) > 1e-12 {
+		t.Errorf("Expected the full disk to start at (1, 0, 0), got %v\n", p)
+	}
+}
+
+func TestQuadricMeshErrors(t *testing.T) {
+	for i, f := range []func() (*Mesh, error){
+		func() (*Mesh, error) { return SphereMesh(1, 1, 4, OrientationOutside) },
+		func() (*Mesh, error) { return SphereMesh(-1, 8, 4, OrientationOutside) },
+		func() (*Mesh, error) { return SphereMesh(1, 8, 4, QuadricOrientation(FILL)) },
+		func() (*Mesh, error) { return CylinderMesh(1, 1, 1, 8, 0, OrientationOutside) },
+		func() (*Mesh, error) { return CylinderMesh(0, 0, 0, 8, 1, OrientationOutside) },
+		func() (*Mesh, error) { return DiskMesh(0, 0, 8, 1, OrientationOutside) },
+		func() (*Mesh, error) { return DiskMesh(2, 1, 8, 1, OrientationOutside) },
+		func() (*Mesh, error) { return PartialDiskMesh(0, 1, 8, 0, 0, 90, OrientationInside) },
+	} {
+		if _, err := f(); err == nil {
+			t.Errorf("%d: expected an error\n", i)
+		}
+	}
+}
+
+func length(v [3]float64) float64 {
+	return math.Sqrt(dot(v, v))
+}
@@ -0,0 +1,88 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build cgo
+
+package glu
+
+import (
+	"testing"
+)
+
+func TestQuadric(t *testing.T) {
+	// Without an OpenGL context the drawing calls do nothing, but they
+	// must not crash.
+	q := NewQuadricObject()
+	defer q.Delete()
+
+	var errs []uint32
+	q.SetErrorCallback(func(errorNumber uint32) {
+		errs = append(errs, errorNumber)
+	})
+
+	for _, style := range []QuadricDrawStyle{DrawPoint, DrawLine, DrawSilhouette, DrawFill} {
+		q.SetDrawStyle(style)
+		q.Sphere(1, 8, 4)
+	}
+	for _, normals := range []QuadricNormals{NormalsNone, NormalsFlat, NormalsSmooth} {
+		q.SetNormals(normals)
+		q.Cylinder(1, 0.5, 2, 8, 2)
+	}
+	q.SetOrientation(OrientationInside)
+	q.SetTexture(true)
+	q.Disk(0.5, 1, 8, 2)
+	q.SetOrientation(OrientationOutside)
+	q.SetTexture(false)
+	q.PartialDisk(0.5, 1, 8, 2, 30, 90)
+	if len(errs) != 0 {
+		t.Errorf("Unexpected errors %v\n", errs)
+	}
+
+	q.SetDrawStyle(QuadricDrawStyle(TRIANGLES))
+	q.Sphere(-1, 8, 4)
+	if len(errs) != 2 || errs[0] != INVALID_ENUM || errs[1] != INVALID_VALUE {
+		t.Errorf("Expected INVALID_ENUM and INVALID_VALUE, got %v\n", errs)
+	}
+
+	// The deprecated functions take the underlying object.
+	Sphere(q.Pointer(), 1, 8, 4)
+}
+
+func TestQuadricErrorTarget(t *testing.T) {
+	a, b := NewQuadricObject(), NewQuadricObject()
+	defer a.Delete()
+	defer b.Delete()
+
+	var errsA, errsB int
+	a.SetErrorCallback(func(errorNumber uint32) { errsA++ })
+	b.SetErrorCallback(func(errorNumber uint32) { errsB++ })
+
+	b.Disk(2, 1, 8, 2)
+	a.SetNormals(QuadricNormals(0))
+	b.SetOrientation(QuadricOrientation(0))
+	if errsA != 1 || errsB != 2 {
+		t.Errorf("Expected 1 and 2 errors, got %v and %v\n", errsA, errsB)
+	}
+}
+
+func TestQuadricErrorTargetReset(t *testing.T) {
+	a := NewQuadricObject()
+	defer a.Delete()
+	var errs []uint32
+	a.SetErrorCallback(func(errorNumber uint32) { errs = append(errs, errorNumber) })
+
+	// The last quadric bound is deleted before an error is raised through
+	// the deprecated functions.
+	b := NewQuadricObject()
+	b.SetErrorCallback(func(errorNumber uint32) { t.Errorf("Unexpected error on a deleted quadric\n") })
+	b.Sphere(1, 8, 4)
+	b.Delete()
+	Sphere(a.Pointer(), -1, 8, 4)
+	Cylinder(a.Pointer(), 1, 1, 1, 0, 1)
+	if len(errs) != 2 || errs[0] != INVALID_VALUE {
+		t.Errorf("Expected 2 errors starting with INVALID_VALUE, got %v\n", errs)
+	}
+
+	Disk(NewQuadric(), 2, 1, 8, 1)
+}
@@ -0,0 +1,327 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && cgo && softgl
+
+package glu
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl-legacy/glu/internal/softgl"
+)
+
+// newSoftGL returns a software OpenGL context with identity matrices and
+// a viewport of 2 by 2 pixels, so that window coordinates are object
+// coordinates plus one, or skips the test if there is none.
+func newSoftGL(t *testing.T) *softgl.Context {
+	ctx, err := softgl.New(2, 2)
+	if err != nil {
+		t.Skip(err)
+	}
+	ctx.LoadIdentity(softgl.PROJECTION)
+	ctx.LoadIdentity(softgl.MODELVIEW)
+	return ctx
+}
+
+// quadricVertex is a vertex as drawn by GLU.
+type quadricVertex struct {
+	position, normal [3]float64
+	uv               [2]float64
+}
+
+// feedbackTriangles returns the triangles draw produces. Their normals
+// are recovered from the colors of two passes with opposite lights.
+func feedbackTriangles(t *testing.T, ctx *softgl.Context, draw func()) [][3]quadricVertex {
+	var passes [2][][3]softgl.Vertex
+	for k, sign := range []float32{1, -1} {
+		ctx.LightNormals(true, sign)
+		tris, err := ctx.Feedback(1<<20, draw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		passes[k] = tris
+	}
+	ctx.LightNormals(false, 1)
+
+	out := make([][3]quadricVertex, len(passes[0]))
+	for i := range out {
+		for j := range out[i] {
+			a, b := passes[0][i][j], passes[1][i][j]
+			v := &out[i][j]
+			v.position = [3]float64{a.Position[0] - 1, a.Position[1] - 1, 2*a.Position[2] - 1}
+			for k := range v.normal {
+				v.normal[k] = a.Color[k] - b.Color[k]
+			}
+			v.uv = [2]float64{a.TexCoord[0], a.TexCoord[1]}
+		}
+	}
+	return out
+}
+
+// compareQuadric checks that m has the triangles GLU drew. OpenGL may
+// split quads in a different order, and start a triangle at any of its
+// corners.
+func compareQuadric(t *testing.T, name string, m *Mesh, tris [][3]quadricVertex) {
+	if m.TriangleCount() != len(tris) {
+		t.Errorf("%s: expected %d triangles, got %d\n", name, len(tris), m.TriangleCount())
+		return
+	}
+	near := func(a, b []float64, tolerance float64) bool {
+		for k := range a {
+			if math.Abs(a[k]-b[k]) > tolerance {
+				return false
+			}
+		}
+		return true
+	}
+	same := func(v quadricVertex, i uint32) bool {
+		return near(v.position[:], m.Positions[i][:], 1e-5) &&
+			near(v.normal[:], m.Normals[i][:], 1e-3) &&
+			near(v.uv[:], m.UVs[i][:], 1e-5)
+	}
+	used := make([]bool, len(tris))
+	for _, tri := range tris {
+		found := false
+		for i := 0; i < m.TriangleCount() && !found; i++ {
+			indices := m.Indices[3*i : 3*i+3]
+			for r := 0; r < 3 && !used[i] && !found; r++ {
+				found = same(tri[0], indices[r]) && same(tri[1], indices[(r+1)%3]) && same(tri[2], indices[(r+2)%3])
+				used[i] = found
+			}
+		}
+		if !found {
+			t.Errorf("%s: GLU drew %v, which is not in the mesh\n", name, tri)
+			return
+		}
+	}
+}
+
+func TestQuadricMeshesMatchGLU(t *testing.T) {
+	ctx := newSoftGL(t)
+	defer ctx.Delete()
+
+	q := NewQuadricObject()
+	defer q.Delete()
+	q.SetTexture(true)
+
+	for _, orientation := range []QuadricOrientation{OrientationOutside, OrientationInside} {
+		q.SetOrientation(orientation)
+		for _, test := range []struct {
+			name string
+			mesh func() (*Mesh, error)
+			draw func()
+		}{
+			{"sphere",
+				func() (*Mesh, error) { return SphereMesh(0.75, 7, 5, orientation) },
+				func() { q.Sphere(0.75, 7, 5) }},
+			{"cylinder",
+				func() (*Mesh, error) { return CylinderMesh(0.5, 0.25, 0.75, 6, 3, orientation) },
+				func() { q.Cylinder(0.5, 0.25, 0.75, 6, 3) }},
+			{"disk",
+				func() (*Mesh, error) { return DiskMesh(0, 0.75, 5, 3, orientation) },
+				func() { q.Disk(0, 0.75, 5, 3) }},
+			{"annulus",
+				func() (*Mesh, error) { return DiskMesh(0.25, 0.75, 5, 2, orientation) },
+				func() { q.Disk(0.25, 0.75, 5, 2) }},
+			{"partial disk",
+				func() (*Mesh, error) { return PartialDiskMesh(0.25, 0.75, 4, 2, 30, -100, orientation) },
+				func() { q.PartialDisk(0.25, 0.75, 4, 2, 30, -100) }},
+			{"partial disk without hole",
+				func() (*Mesh, error) { return PartialDiskMesh(0, 0.75, 3, 2, 45, 270, orientation) },
+				func() { q.PartialDisk(0, 0.75, 3, 2, 45, 270) }},
+			{"rotated disk",
+				func() (*Mesh, error) { return PartialDiskMesh(0.25, 0.75, 4, 1, 45, 360, orientation) },
+				func() { q.PartialDisk(0.25, 0.75, 4, 1, 45, 360) }},
+			{"rotated disk without hole",
+				func() (*Mesh, error) { return PartialDiskMesh(0, 0.75, 5, 2, 30, -360, orientation) },
+				func() { q.PartialDisk(0, 0.75, 5, 2, 30, -360) }},
+		} {
+			m, err := test.mesh()
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			compareQuadric(t, test.name, m, feedbackTriangles(t, ctx, test.draw))
+		}
+	}
+}
+
+func TestMatricesMatchGLU(t *testing.T) {
+	ctx := newSoftGL(t)
+	defer ctx.Delete()
+
+	view := [4]int32{10, 20, 640, 480}
+	for _, test := range []struct {
+		name   string
+		mode   uint32
+		draw   func()
+		single [16]float32
+		double [16]float64
+	}{
+		{"perspective", softgl.PROJECTION,
+			func() { Perspective(60, 4.0/3, 0.1, 100) },
+			PerspectiveMatrix[float32](60, 4.0/3, 0.1, 100), PerspectiveMatrix(60, 4.0/3, 0.1, 100)},
+		{"wide perspective", softgl.PROJECTION,
+			func() { Perspective(170, 0.5, 3, 7) },
+			PerspectiveMatrix[float32](170, 0.5, 3, 7), PerspectiveMatrix(170, 0.5, 3, 7)},
+		{"flat perspective", softgl.PROJECTION,
+			func() { Perspective(60, 1, 5, 5) },
+			PerspectiveMatrix[float32](60, 1, 5, 5), PerspectiveMatrix(60, 1, 5, 5.0)},
+		{"look at", softgl.MODELVIEW,
+			func() { LookAt(3.0, 4, 5, 0.3, -0.2, 0.1, 0.1, 1, 0) },
+			LookAtMatrix[float32](3, 4, 5, 0.3, -0.2, 0.1, 0.1, 1, 0), LookAtMatrix(3.0, 4, 5, 0.3, -0.2, 0.1, 0.1, 1, 0)},
+		{"look down", softgl.MODELVIEW,
+			func() { LookAt(-1.5, 7, 2.25, -1.5, 0, 2.25, 0, 0, -1) },
+			LookAtMatrix[float32](-1.5, 7, 2.25, -1.5, 0, 2.25, 0, 0, -1), LookAtMatrix(-1.5, 7, 2.25, -1.5, 0, 2.25, 0, 0, -1)},
+		{"pick", softgl.PROJECTION,
+			func() { PickMatrix(123.5, 321, 5, 7, &view) },
+			PickingMatrix[float32](123.5, 321, 5, 7, &view), PickingMatrix(123.5, 321, 5, 7.0, &view)},
+		{"empty pick", softgl.PROJECTION,
+			func() { PickMatrix(123.5, 321, 0, 7, &view) },
+			PickingMatrix[float32](123.5, 321, 0, 7, &view), PickingMatrix(123.5, 321, 0, 7.0, &view)},
+		{"ortho 2d", softgl.PROJECTION,
+			func() { ctx.Ortho(-0.3, 640, 480, 0.7, -1, 1) },
+			Ortho2DMatrix[float32](-0.3, 640, 480, 0.7), Ortho2DMatrix(-0.3, 640, 480, 0.7)},
+		{"frustum", softgl.PROJECTION,
+			func() { ctx.Frustum(-0.3, 0.5, -0.2, 0.35, 0.7, 123) },
+			FrustumMatrix[float32](-0.3, 0.5, -0.2, 0.35, 0.7, 123), FrustumMatrix(-0.3, 0.5, -0.2, 0.35, 0.7, 123)},
+	} {
+		ctx.LoadIdentity(test.mode)
+		test.draw()
+		pname := uint32(softgl.PROJECTION_MATRIX)
+		if test.mode == softgl.MODELVIEW {
+			pname = softgl.MODELVIEW_MATRIX
+		}
+		gl := ctx.Matrix(pname)
+		ctx.LoadIdentity(test.mode)
+
+		// OpenGL keeps single precision matrices, which the float32
+		// builders give exactly.
+		for i := range gl {
+			if float64(test.single[i]) != gl[i] || math.Abs(test.double[i]-gl[i]) > 1e-6*math.Max(1, math.Abs(gl[i])) {
+				t.Errorf("%s: expected %v, got %v and %v\n", test.name, gl, test.single, test.double)
+				break
+			}
+		}
+	}
+}
+
+func TestMatrixStackMatchesOpenGL(t *testing.T) {
+	ctx := newSoftGL(t)
+	defer ctx.Delete()
+	ctx.LoadIdentity(softgl.TEXTURE)
+	ctx.MatrixMode(softgl.MODELVIEW)
+
+	var single MatrixStack[float32]
+	var double MatrixStack[float64]
+	view := [4]int32{0, 0, 640, 480}
+	matrices := map[MatrixMode]uint32{
+		MatrixModelView:  softgl.MODELVIEW_MATRIX,
+		MatrixProjection: softgl.PROJECTION_MATRIX,
+		MatrixTexture:    softgl.TEXTURE_MATRIX,
+	}
+
+	// The arguments are exact in single precision, as OpenGL takes them.
+	for i, step := range []struct {
+		op   string
+		args []float64
+	}{
+		{"mode", []float64{float64(MatrixProjection)}},
+		{"push", nil},
+		{"pick", []float64{320, 240.5, 8, 4}},
+		{"perspective", []float64{45, 1.25, 0.5, 64}},
+		{"pop", nil},
+		{"ortho2d", []float64{0, 640, 480, 0}},
+		{"mode", []float64{float64(MatrixModelView)}},
+		{"look at", []float64{3, 4, 5, 0.25, -0.5, 0, 0, 1, 0}},
+		{"push", nil},
+		{"translate", []float64{1.5, -2, 0.25}},
+		{"rotate", []float64{30, 1, 2, 3}},
+		{"rotate", []float64{-75, 0, 0, -2}},
+		{"rotate", []float64{10, 0, 3, 0}},
+		{"rotate", []float64{1, 0.5, 0, 0}},
+		{"rotate", []float64{45, 0, 0, 0}},
+		{"scale", []float64{2, -0.5, 0.75}},
+		{"mult", []float64{1, 0.5, 0, 0, -0.25, 2, 0, 0, 0, 0, 1, 0.125, 3, 4, 5, 1}},
+		{"pop", nil},
+		{"look at", []float64{-1, 0.5, 2, 6, -3, 1, 0.5, 0, 1}},
+		{"mode", []float64{float64(MatrixTexture)}},
+		{"translate", []float64{0.5, 0.5, 0}},
+		{"scale", []float64{0.5, 0.5, 1}},
+		{"rotate", []float64{90, 0, 0, 1}},
+		{"identity", nil},
+	} {
+		applyMatrixStep(&single, step.op, step.args, &view)
+		applyMatrixStep(&double, step.op, step.args, &view)
+		a := step.args
+		switch step.op {
+		case "mode":
+			ctx.MatrixMode(uint32(a[0]))
+		case "push":
+			ctx.PushMatrix()
+		case "pop":
+			ctx.PopMatrix()
+		case "identity":
+			ctx.LoadIdentity(uint32(single.Mode()))
+		case "translate":
+			ctx.Translate(a[0], a[1], a[2])
+		case "rotate":
+			ctx.Rotate(a[0], a[1], a[2], a[3])
+		case "scale":
+			ctx.Scale(a[0], a[1], a[2])
+		case "mult":
+			ctx.MultMatrix((*[16]float64)(a))
+		case "pick":
+			PickMatrix(a[0], a[1], a[2], a[3], &view)
+		case "perspective":
+			Perspective(a[0], a[1], a[2], a[3])
+		case "ortho2d":
+			ctx.Ortho(a[0], a[1], a[2], a[3], -1, 1)
+		case "look at":
+			LookAt(a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8])
+		}
+
+		gl := ctx.Matrix(matrices[single.Mode()])
+		s, d := single.Top(), double.Top()
+		for k := range gl {
+			if float64(s[k]) != gl[k] || math.Abs(d[k]-gl[k]) > 1e-5*math.Max(1, math.Abs(gl[k])) {
+				t.Fatalf("%d %s: expected %v, got %v and %v\n", i, step.op, gl, s, d)
+			}
+		}
+	}
+}
+
+func applyMatrixStep[T Float](s *MatrixStack[T], op string, args []float64, view *[4]int32) {
+	a := make([]T, len(args))
+	for k, x := range args {
+		a[k] = T(x)
+	}
+	switch op {
+	case "mode":
+		s.SetMode(MatrixMode(args[0]))
+	case "push":
+		s.Push()
+	case "pop":
+		s.Pop()
+	case "identity":
+		s.LoadIdentity()
+	case "translate":
+		s.Translate(a[0], a[1], a[2])
+	case "rotate":
+		s.Rotate(a[0], a[1], a[2], a[3])
+	case "scale":
+		s.Scale(a[0], a[1], a[2])
+	case "mult":
+		s.Mult([16]T(a))
+	case "pick":
+		s.PickMatrix(a[0], a[1], a[2], a[3], view)
+	case "perspective":
+		s.Perspective(a[0], a[1], a[2], a[3])
+	case "ortho2d":
+		s.Ortho2D(a[0], a[1], a[2], a[3])
+	case "look at":
+		s.LookAt(a[0], a[1], a[2], a[3], a[4], a[5], a[6], a[7], a[8])
+	}
+}
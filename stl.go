@@ -0,0 +1,136 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// WriteSTL writes the triangles of the mesh as an ASCII STL file, with
+// facet normals computed from the counterclockwise winding. STL keeps
+// positions only.
+func WriteSTL(w io.Writer, m *Mesh) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString("solid glu\n")
+	for i := 0; i < m.TriangleCount(); i++ {
+		n := m.faceNormal(i)
+		fmt.Fprintf(bw, "facet normal %s %s %s\n outer loop\n",
+			formatFloat(n[0]), formatFloat(n[1]), formatFloat(n[2]))
+		for _, p := range m.Triangle(i) {
+			fmt.Fprintf(bw, "  vertex %s %s %s\n", formatFloat(p[0]), formatFloat(p[1]), formatFloat(p[2]))
+		}
+		bw.WriteString(" endloop\nendfacet\n")
+	}
+	bw.WriteString("endsolid glu\n")
+	return bw.Flush()
+}
+
+// WriteSTLBinary writes the triangles of the mesh as a binary STL file,
+// like WriteSTL. Coordinates are rounded to single precision.
+func WriteSTLBinary(w io.Writer, m *Mesh) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	var header [80]byte
+	copy(header[:], "glu")
+	bw.Write(header[:])
+	binary.Write(bw, binary.LittleEndian, uint32(m.TriangleCount()))
+
+	var facet [50]byte
+	for i := 0; i < m.TriangleCount(); i++ {
+		n := m.faceNormal(i)
+		t := m.Triangle(i)
+		v := [12]float64{n[0], n[1], n[2]}
+		for j, p := range t {
+			copy(v[3+3*j:], p[:])
+		}
+		for j, f := range v {
+			binary.LittleEndian.PutUint32(facet[4*j:], math.Float32bits(float32(f)))
+		}
+		bw.Write(facet[:])
+	}
+	return bw.Flush()
+}
+
+// ReadSTL reads an ASCII or binary STL file into a mesh. Vertices at the
+// same position are shared between triangles.
+func ReadSTL(r io.Reader) (*Mesh, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Mesh{}
+	index := make(map[[3]float64]uint32)
+	vertex := func(p [3]float64) {
+		i, ok := index[p]
+		if !ok {
+			i = uint32(len(m.Positions))
+			index[p] = i
+			m.Positions = append(m.Positions, p)
+		}
+		m.Indices = append(m.Indices, i)
+	}
+
+	// Binary files may start with "solid" too, so go by their size.
+	if len(data) >= 84 {
+		count := binary.LittleEndian.Uint32(data[80:])
+		if uint64(len(data)) == 84+50*uint64(count) {
+			for i := 0; i < int(count); i++ {
+				facet := data[84+50*i:]
+				for j := 0; j < 3; j++ {
+					var p [3]float64
+					for k := range p {
+						bits := binary.LittleEndian.Uint32(facet[12+12*j+4*k:])
+						p[k] = float64(math.Float32frombits(bits))
+					}
+					vertex(p)
+				}
+			}
+			return m, nil
+		}
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	solid := false
+	for s.Scan() {
+		line++
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "solid":
+			solid = true
+		case "vertex":
+			v, err := parseFloats(fields[1:])
+			if err != nil || len(v) != 3 || !solid {
+				return nil, fmt.Errorf("Invalid STL vertex on line %d", line)
+			}
+			vertex([3]float64{v[0], v[1], v[2]})
+		case "facet", "outer", "endloop", "endfacet", "endsolid":
+		default:
+			return nil, fmt.Errorf("Invalid STL statement on line %d", line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if !solid || len(m.Indices)%3 != 0 {
+		return nil, fmt.Errorf("Invalid STL file")
+	}
+	return m, nil
+}
@@ -0,0 +1,406 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"fmt"
+	"math"
+)
+
+// LineJoin selects the shape drawn where two segments of a stroke meet.
+type LineJoin int
+
+const (
+	JoinMiter LineJoin = iota // extend the outer edges until they meet
+	JoinRound                 // a circular arc
+	JoinBevel                 // a straight line across the corner
+)
+
+// LineCap selects the shape drawn at the ends of an open stroke.
+type LineCap int
+
+const (
+	CapButt   LineCap = iota // end flush with the end point
+	CapRound                 // a half circle around the end point
+	CapSquare                // a half square around the end point
+)
+
+// StrokeStyle describes how polylines are stroked.
+type StrokeStyle struct {
+	Width float64
+	Join  LineJoin
+	Cap   LineCap
+
+	// MiterLimit is the largest ratio of the length of a miter to the
+	// width before the join is beveled instead. Zero means 4.
+	MiterLimit float64
+
+	// Dashes holds alternating dash and gap lengths, repeated along each
+	// polyline. A list of odd length is repeated twice. An empty list
+	// draws a solid line. DashOffset is the distance into the pattern at
+	// which each polyline starts.
+	Dashes     []float64
+	DashOffset float64
+
+	// Tolerance bounds the flattening error of round joins and caps.
+	// Zero means a hundredth of the width.
+	Tolerance float64
+}
+
+// Stroke triangulates the area covered by stroking the polylines, which
+// lie in the z = 0 plane, with the given style. The z coordinates are
+// ignored. If closed is true each polyline joins its end to its start.
+// Overlapping parts of the stroke are covered once.
+func Stroke(polylines []Contour, closed bool, style *StrokeStyle) (*Mesh, error) {
+	pieces, err := strokePieces(polylines, closed, style)
+	if err != nil {
+		return nil, err
+	}
+	return Tessellate(pieces, TESS_WINDING_NONZERO, [3]float64{0, 0, 1})
+}
+
+// StrokeOutline returns the boundary of the area covered by stroking the
+// polylines, like Stroke.
+func StrokeOutline(polylines []Contour, closed bool, style *StrokeStyle) ([]Contour, error) {
+	pieces, err := strokePieces(polylines, closed, style)
+	if err != nil {
+		return nil, err
+	}
+	return tessellateBoundary(pieces, TESS_WINDING_NONZERO, [3]float64{0, 0, 1})
+}
+
+// Stroke triangulates the area covered by stroking the path. Subpaths
+// ended by Close are stroked as closed polylines.
+func (p *Path) Stroke(style *StrokeStyle) (*Mesh, error) {
+	pieces, err := p.strokePieces(style)
+	if err != nil {
+		return nil, err
+	}
+	return Tessellate(pieces, TESS_WINDING_NONZERO, [3]float64{0, 0, 1})
+}
+
+// StrokeOutline returns the boundary of the area covered by stroking the
+// path, like Stroke.
+func (p *Path) StrokeOutline(style *StrokeStyle) ([]Contour, error) {
+	pieces, err := p.strokePieces(style)
+	if err != nil {
+		return nil, err
+	}
+	return tessellateBoundary(pieces, TESS_WINDING_NONZERO, [3]float64{0, 0, 1})
+}
+
+func strokePieces(polylines []Contour, closed bool, style *StrokeStyle) ([]Contour, error) {
+	s, err := newStroker(style)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range polylines {
+		points := make([][2]float64, len(c))
+		for i, v := range c {
+			points[i] = [2]float64{v[0], v[1]}
+		}
+		s.polyline(points, closed)
+	}
+	return s.pieces, nil
+}
+
+func (p *Path) strokePieces(style *StrokeStyle) ([]Contour, error) {
+	s, err := newStroker(style)
+	if err != nil {
+		return nil, err
+	}
+	for _, sp := range p.allSubpaths() {
+		s.polyline(sp.points, sp.closed)
+	}
+	return s.pieces, nil
+}
+
+// =============================================================================
+
+// stroker covers a stroke with counterclockwise pieces: a rectangle per
+// segment and a polygon per join and cap. The union of the pieces under
+// TESS_WINDING_NONZERO is the stroke.
+type stroker struct {
+	hw         float64 // half the width
+	join       LineJoin
+	cap        LineCap
+	miterLimit float64
+	dashes     []float64
+	dashOffset float64
+	tolerance  float64
+	pieces     []Contour
+}
+
+func newStroker(style *StrokeStyle) (*stroker, error) {
+	if !(style.Width > 0) || math.IsInf(style.Width, 0) {
+		return nil, fmt.Errorf("Invalid stroke width %v", style.Width)
+	}
+	s := &stroker{
+		hw:         style.Width / 2,
+		join:       style.Join,
+		cap:        style.Cap,
+		miterLimit: style.MiterLimit,
+		dashes:     style.Dashes,
+		dashOffset: style.DashOffset,
+		tolerance:  style.Tolerance,
+	}
+	if s.miterLimit == 0 {
+		s.miterLimit = 4
+	}
+	if s.tolerance <= 0 {
+		s.tolerance = style.Width / 100
+	}
+
+	sum := 0.0
+	for _, d := range s.dashes {
+		if d < 0 {
+			return nil, fmt.Errorf("Invalid dash length %v", d)
+		}
+		sum += d
+	}
+	if sum == 0 {
+		s.dashes = nil
+	} else if len(s.dashes)%2 == 1 {
+		s.dashes = append(s.dashes[:len(s.dashes):len(s.dashes)], s.dashes...)
+	}
+	return s, nil
+}
+
+func (s *stroker) polyline(points [][2]float64, closed bool) {
+	// Drop repeated points, which have no direction.
+	var clean [][2]float64
+	for _, p := range points {
+		if len(clean) == 0 || p != clean[len(clean)-1] {
+			clean = append(clean, p)
+		}
+	}
+	if closed && len(clean) > 1 && clean[0] == clean[len(clean)-1] {
+		clean = clean[:len(clean)-1]
+	}
+	if len(clean) == 0 {
+		return
+	}
+
+	if s.dashes != nil {
+		for _, dash := range s.dash(clean, closed) {
+			s.open(dash)
+		}
+	} else if closed && len(clean) > 2 {
+		s.closed(clean)
+	} else {
+		s.open(clean)
+	}
+}
+
+func (s *stroker) open(points [][2]float64) {
+	// Dashes may repeat points where they start on a vertex.
+	n := 0
+	for _, p := range points {
+		if n == 0 || p != points[n-1] {
+			points[n] = p
+			n++
+		}
+	}
+	points = points[:n]
+
+	if len(points) == 1 {
+		// A zero length line shows its caps only.
+		s.endCap(points[0], [2]float64{1, 0}, false)
+		s.endCap(points[0], [2]float64{1, 0}, true)
+		return
+	}
+	for i := 0; i+1 < len(points); i++ {
+		s.segment(points[i], points[i+1])
+		if i > 0 {
+			s.joint(points[i-1], points[i], points[i+1])
+		}
+	}
+	last := len(points) - 1
+	s.endCap(points[0], direction(points[0], points[1]), false)
+	s.endCap(points[last], direction(points[last-1], points[last]), true)
+}
+
+func (s *stroker) closed(points [][2]float64) {
+	n := len(points)
+	for i := range points {
+		s.segment(points[i], points[(i+1)%n])
+		s.joint(points[(i+n-1)%n], points[i], points[(i+1)%n])
+	}
+}
+
+func (s *stroker) segment(a, b [2]float64) {
+	n := leftNormal(direction(a, b), s.hw)
+	s.piece([][2]float64{
+		{a[0] - n[0], a[1] - n[1]},
+		{b[0] - n[0], b[1] - n[1]},
+		{b[0] + n[0], b[1] + n[1]},
+		{a[0] + n[0], a[1] + n[1]},
+	})
+}
+
+// joint fills the outer side of the corner at b between the segments ab
+// and bc.
+func (s *stroker) joint(a, b, c [2]float64) {
+	d0, d1 := direction(a, b), direction(b, c)
+	cross := d0[0]*d1[1] - d0[1]*d1[0]
+	dot := d0[0]*d1[0] + d0[1]*d1[1]
+	if math.Abs(cross) < 1e-12 && dot > 0 {
+		return
+	}
+
+	// The outer side is to the right of a left turn.
+	o0, o1 := leftNormal(d0, s.hw), leftNormal(d1, s.hw)
+	if cross > 0 {
+		o0 = [2]float64{-o0[0], -o0[1]}
+		o1 = [2]float64{-o1[0], -o1[1]}
+	}
+	p0 := [2]float64{b[0] + o0[0], b[1] + o0[1]}
+	p1 := [2]float64{b[0] + o1[0], b[1] + o1[1]}
+
+	switch s.join {
+	case JoinRound:
+		sweep := math.Atan2(o0[0]*o1[1]-o0[1]*o1[0], o0[0]*o1[0]+o0[1]*o1[1])
+		if math.Abs(cross) < 1e-12 {
+			// Turning back, around the front of the incoming segment.
+			sweep = -math.Pi
+		}
+		s.pie(b, o0, sweep)
+	case JoinMiter:
+		// The miter extends hw/cos(a/2) from b, for a turning angle a.
+		cos := math.Sqrt((1 + dot) / 2)
+		if cos > 0 && 1/cos <= s.miterLimit {
+			m := [2]float64{o0[0] + o1[0], o0[1] + o1[1]}
+			l := s.hw / cos / math.Hypot(m[0], m[1])
+			s.piece([][2]float64{b, p0, {b[0] + m[0]*l, b[1] + m[1]*l}, p1})
+			return
+		}
+		fallthrough
+	default:
+		s.piece([][2]float64{b, p0, p1})
+	}
+}
+
+// endCap adds the cap at the start or end p of a line with direction d.
+func (s *stroker) endCap(p, d [2]float64, end bool) {
+	n := leftNormal(d, s.hw)
+	switch s.cap {
+	case CapRound:
+		if end {
+			s.pie(p, n, -math.Pi)
+		} else {
+			s.pie(p, n, math.Pi)
+		}
+	case CapSquare:
+		e := [2]float64{d[0] * s.hw, d[1] * s.hw}
+		if !end {
+			e = [2]float64{-e[0], -e[1]}
+		}
+		s.piece([][2]float64{
+			{p[0] + n[0], p[1] + n[1]},
+			{p[0] + n[0] + e[0], p[1] + n[1] + e[1]},
+			{p[0] - n[0] + e[0], p[1] - n[1] + e[1]},
+			{p[0] - n[0], p[1] - n[1]},
+		})
+	}
+}
+
+// pie adds the circular sector around c starting at offset o and spanning
+// sweep radians.
+func (s *stroker) pie(c, o [2]float64, sweep float64) {
+	n := arcSegments(s.hw, sweep, s.tolerance)
+	points := [][2]float64{c, {c[0] + o[0], c[1] + o[1]}}
+	points = appendArc(points, c, s.hw, s.hw, 0, math.Atan2(o[1], o[0]), sweep, n)
+	s.piece(points)
+}
+
+// piece adds a polygon, oriented counterclockwise so that the winding
+// numbers of overlapping pieces add up.
+func (s *stroker) piece(points [][2]float64) {
+	area := 0.0
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		area += p[0]*q[1] - q[0]*p[1]
+	}
+	if area == 0 {
+		return
+	}
+	c := make(Contour, len(points))
+	for i, p := range points {
+		c[i] = [3]float64{p[0], p[1], 0}
+	}
+	if area < 0 {
+		reverseContour(c)
+	}
+	s.pieces = append(s.pieces, c)
+}
+
+// dash splits a polyline into the open polylines of its dashes.
+func (s *stroker) dash(points [][2]float64, closed bool) [][][2]float64 {
+	sum := 0.0
+	for _, d := range s.dashes {
+		sum += d
+	}
+	pos := math.Mod(s.dashOffset, sum)
+	if pos < 0 {
+		pos += sum
+	}
+	// Skip the entries before pos, including zero-length ones, but start
+	// on a dot at pos 0.
+	i := 0
+	for pos > 0 && pos >= s.dashes[i] {
+		pos -= s.dashes[i]
+		i = (i + 1) % len(s.dashes)
+	}
+	remain := s.dashes[i] - pos
+	on := i%2 == 0
+
+	var dashes [][][2]float64
+	var cur [][2]float64
+	if on {
+		cur = [][2]float64{points[0]}
+	}
+	n := len(points) - 1
+	if closed {
+		n++
+	}
+	for j := 0; j < n; j++ {
+		a, b := points[j], points[(j+1)%len(points)]
+		l := math.Hypot(b[0]-a[0], b[1]-a[1])
+		t := 0.0
+		for l-t > remain {
+			t += remain
+			p := [2]float64{a[0] + (b[0]-a[0])*t/l, a[1] + (b[1]-a[1])*t/l}
+			if on {
+				dashes = append(dashes, append(cur, p))
+				cur = nil
+			} else {
+				cur = [][2]float64{p}
+			}
+			on = !on
+			i = (i + 1) % len(s.dashes)
+			remain = s.dashes[i]
+		}
+		remain -= l - t
+		if on {
+			cur = append(cur, b)
+		}
+	}
+	if on {
+		dashes = append(dashes, cur)
+	}
+	return dashes
+}
+
+// direction returns the unit vector from a to b.
+func direction(a, b [2]float64) [2]float64 {
+	d := [2]float64{b[0] - a[0], b[1] - a[1]}
+	l := math.Hypot(d[0], d[1])
+	return [2]float64{d[0] / l, d[1] / l}
+}
+
+// leftNormal returns the vector of length l to the left of d.
+func leftNormal(d [2]float64, l float64) [2]float64 {
+	return [2]float64{-d[1] * l, d[0] * l}
+}
@@ -0,0 +1,136 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStroke(t *testing.T) {
+	line := []Contour{{{0, 0, 0}, {10, 0, 0}}}
+	corner := []Contour{{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}}}
+	sharp := []Contour{{{0, 0, 0}, {10, 0, 0}, {0, 1, 0}}}
+	back := []Contour{{{0, 0, 0}, {10, 0, 0}, {5, 0, 0}}}
+	square := []Contour{{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}, {0, 10, 0}}}
+	dot := []Contour{{{0, 0, 0}, {0, 0, 0}}}
+
+	tests := []struct {
+		name      string
+		polylines []Contour
+		closed    bool
+		style     StrokeStyle
+		area      float64
+	}{
+		{"butt", line, false, StrokeStyle{Width: 2}, 20},
+		{"square cap", line, false, StrokeStyle{Width: 2, Cap: CapSquare}, 24},
+		{"round cap", line, false, StrokeStyle{Width: 2, Cap: CapRound}, 20 + math.Pi},
+		{"miter", corner, false, StrokeStyle{Width: 2}, 40},
+		{"bevel", corner, false, StrokeStyle{Width: 2, Join: JoinBevel}, 39.5},
+		{"round join", corner, false, StrokeStyle{Width: 2, Join: JoinRound}, 39 + math.Pi/4},
+		{"miter limit", sharp, false, StrokeStyle{Width: 0.5, MiterLimit: 1.5}, -1},
+		{"turn back", back, false, StrokeStyle{Width: 2, Join: JoinBevel}, 20},
+		{"turn back round", back, false, StrokeStyle{Width: 2, Join: JoinRound}, 20 + math.Pi/2},
+		{"closed", square, true, StrokeStyle{Width: 2}, 80},
+		{"closed round", square, true, StrokeStyle{Width: 2, Join: JoinRound}, 76 + math.Pi},
+		{"dot", dot, false, StrokeStyle{Width: 2, Cap: CapRound}, math.Pi},
+		{"dot butt", dot, false, StrokeStyle{Width: 2}, 0},
+		{"dashes", line, false, StrokeStyle{Width: 1, Dashes: []float64{2, 3}}, 4},
+		{"dash offset", line, false, StrokeStyle{Width: 1, Dashes: []float64{2, 3}, DashOffset: 6}, 4},
+		{"odd dashes", line, false, StrokeStyle{Width: 1, Dashes: []float64{1}}, 5},
+		{"closed dashes", square, true, StrokeStyle{Width: 1, Dashes: []float64{5, 5}}, 20},
+		{"dots", line, false, StrokeStyle{Width: 1, Dashes: []float64{0, 4}, Cap: CapSquare}, 3},
+		{"dots offset", line, false, StrokeStyle{Width: 1, Dashes: []float64{0, 4}, DashOffset: 1, Cap: CapSquare}, 2},
+	}
+	for _, test := range tests {
+		mesh, err := Stroke(test.polylines, test.closed, &test.style)
+		if err != nil {
+			t.Errorf("%v: %v\n", test.name, err)
+			continue
+		}
+		area := meshArea(mesh)
+		if test.area >= 0 && math.Abs(area-test.area) > 0.1 {
+			t.Errorf("%v: expected area %v, got %v\n", test.name, test.area, area)
+		}
+
+		outline, err := StrokeOutline(test.polylines, test.closed, &test.style)
+		if err != nil {
+			t.Errorf("%v: %v\n", test.name, err)
+			continue
+		}
+		if a := contourArea(outline); math.Abs(a-area) > 1e-6 {
+			t.Errorf("%v: expected outline area %v, got %v\n", test.name, area, a)
+		}
+	}
+}
+
+func TestStrokeDashZeroLengths(t *testing.T) {
+	// Dashes starting in the pattern after zero lengths stay on the line.
+	line := []Contour{{{0, 0, 0}, {10, 0, 0}}}
+	for _, style := range []StrokeStyle{
+		{Width: 1, Dashes: []float64{2, 0, 0, 3}, DashOffset: 4, Cap: CapSquare},
+		{Width: 1, Dashes: []float64{0, 4}, DashOffset: 1, Cap: CapSquare},
+	} {
+		mesh, err := Stroke(line, false, &style)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range mesh.Positions {
+			if p[0] < 0 || p[0] > 10 {
+				t.Errorf("%v: expected the dashes between x = 0 and 10, got %v\n", style.Dashes, p)
+				break
+			}
+		}
+	}
+}
+
+func TestStrokeMiterLimit(t *testing.T) {
+	sharp := []Contour{{{0, 0, 0}, {10, 0, 0}, {0, 1, 0}}}
+	style := StrokeStyle{Width: 0.5}
+	mitered, err := Stroke(sharp, false, &style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	style.Join = JoinBevel
+	beveled, err := Stroke(sharp, false, &style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	style.Join = JoinMiter
+	style.MiterLimit = 100
+	long, err := Stroke(sharp, false, &style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meshArea(mitered) != meshArea(beveled) {
+		t.Errorf("Expected a miter exceeding the limit to be beveled\n")
+	}
+	if meshArea(long) <= meshArea(beveled) {
+		t.Errorf("Expected a miter within the limit to add area\n")
+	}
+}
+
+func TestPathStroke(t *testing.T) {
+	p := NewPath(0.001)
+	p.MoveTo(-10, 0)
+	p.ArcTo(10, 10, 0, false, true, 10, 0)
+	p.ArcTo(10, 10, 0, false, true, -10, 0)
+	p.Close()
+
+	mesh, err := p.Stroke(&StrokeStyle{Width: 2, Tolerance: 0.001})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if area, expected := meshArea(mesh), math.Pi*(11*11-9*9); math.Abs(area-expected) > 0.1 {
+		t.Errorf("Expected area %v, got %v\n", expected, area)
+	}
+
+	if _, err := p.Stroke(&StrokeStyle{}); err == nil {
+		t.Errorf("Expected an error for a zero width\n")
+	}
+	if _, err := p.Stroke(&StrokeStyle{Width: 1, Dashes: []float64{1, -1}}); err == nil {
+		t.Errorf("Expected an error for a negative dash\n")
+	}
+}
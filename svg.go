@@ -0,0 +1,626 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVGShape is a filled shape read from an SVG document. Its contours lie
+// in the z = 0 plane, in the user units of the document's root element.
+type SVGShape struct {
+	ID          string
+	Contours    []Contour
+	WindingRule uint32 // TESS_WINDING_NONZERO or TESS_WINDING_ODD
+}
+
+// Tessellate triangulates the shape with its winding rule.
+func (s *SVGShape) Tessellate() (*Mesh, error) {
+	return Tessellate(s.Contours, s.WindingRule, [3]float64{0, 0, 1})
+}
+
+// ReadSVG reads the filled shapes of an SVG document, in document order.
+// It understands path, rect, circle, ellipse, polygon and polyline
+// elements, the transform attribute on them and on enclosing g elements,
+// and the fill-rule and fill properties, given as attributes or in a style
+// attribute. Shapes with fill none are left out, as are the contents of
+// defs and similar elements which are not rendered directly.
+//
+// Curves are flattened so that no point of a curve is farther than
+// tolerance from the resulting contour, after transformation.
+func ReadSVG(r io.Reader, tolerance float64) ([]SVGShape, error) {
+	if tolerance <= 0 {
+		return nil, fmt.Errorf("Invalid tolerance %v", tolerance)
+	}
+
+	type state struct {
+		m       svgMatrix
+		evenOdd bool
+		noFill  bool
+	}
+	stack := []state{{m: svgIdentity}}
+	skip := 0
+
+	var shapes []SVGShape
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return shapes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if skip > 0 || svgHidden[tok.Name.Local] {
+				skip++
+				continue
+			}
+
+			s := stack[len(stack)-1]
+			attrs := svgAttrs(tok.Attr)
+			if t, ok := attrs["transform"]; ok {
+				m, err := parseSVGTransform(t)
+				if err != nil {
+					return nil, err
+				}
+				s.m = s.m.mul(m)
+			}
+			if rule, ok := attrs["fill-rule"]; ok {
+				s.evenOdd = rule == "evenodd"
+			}
+			if fill, ok := attrs["fill"]; ok {
+				s.noFill = fill == "none"
+			}
+			stack = append(stack, s)
+
+			b := &svgBuilder{m: s.m, tolerance: tolerance}
+			if err := b.element(tok.Name.Local, attrs); err != nil {
+				return nil, err
+			}
+			if len(b.contours) > 0 && !s.noFill {
+				shape := SVGShape{
+					ID:          attrs["id"],
+					Contours:    b.contours,
+					WindingRule: TESS_WINDING_NONZERO,
+				}
+				if s.evenOdd {
+					shape.WindingRule = TESS_WINDING_ODD
+				}
+				shapes = append(shapes, shape)
+			}
+
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
+
+// svgHidden holds the elements whose contents are not rendered directly.
+var svgHidden = map[string]bool{
+	"defs":     true,
+	"clipPath": true,
+	"mask":     true,
+	"symbol":   true,
+	"pattern":  true,
+	"marker":   true,
+	"style":    true,
+}
+
+// svgAttrs returns the attributes of an element, with the declarations of
+// its style attribute taking precedence.
+func svgAttrs(attr []xml.Attr) map[string]string {
+	attrs := make(map[string]string, len(attr))
+	for _, a := range attr {
+		attrs[a.Name.Local] = strings.TrimSpace(a.Value)
+	}
+	for _, decl := range strings.Split(attrs["style"], ";") {
+		if i := strings.IndexByte(decl, ':'); i >= 0 {
+			attrs[strings.TrimSpace(decl[:i])] = strings.TrimSpace(decl[i+1:])
+		}
+	}
+	return attrs
+}
+
+// =============================================================================
+
+// svgMatrix is the affine transform [a c e; b d f] of SVG.
+type svgMatrix [6]float64
+
+var svgIdentity = svgMatrix{1, 0, 0, 1, 0, 0}
+
+// mul returns the transform applying n, then m.
+func (m svgMatrix) mul(n svgMatrix) svgMatrix {
+	return svgMatrix{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m svgMatrix) apply(p [2]float64) [2]float64 {
+	return [2]float64{
+		m[0]*p[0] + m[2]*p[1] + m[4],
+		m[1]*p[0] + m[3]*p[1] + m[5],
+	}
+}
+
+// scale returns an upper bound of the factor by which m stretches lengths.
+func (m svgMatrix) scale() float64 {
+	return math.Sqrt(m[0]*m[0] + m[1]*m[1] + m[2]*m[2] + m[3]*m[3])
+}
+
+// parseSVGTransform parses the value of a transform attribute.
+func parseSVGTransform(s string) (svgMatrix, error) {
+	m := svgIdentity
+	rest := s
+	for {
+		rest = strings.TrimLeft(rest, " \t\r\n,")
+		if rest == "" {
+			return m, nil
+		}
+		open := strings.IndexByte(rest, '(')
+		end := strings.IndexByte(rest, ')')
+		if open < 0 || end < open {
+			return m, fmt.Errorf("Invalid SVG transform %q", s)
+		}
+		name := strings.TrimSpace(rest[:open])
+		args, err := parseSVGNumbers(rest[open+1 : end])
+		if err != nil {
+			return m, err
+		}
+		rest = rest[end+1:]
+
+		var t svgMatrix
+		switch {
+		case name == "matrix" && len(args) == 6:
+			copy(t[:], args)
+		case name == "translate" && len(args) == 1:
+			t = svgMatrix{1, 0, 0, 1, args[0], 0}
+		case name == "translate" && len(args) == 2:
+			t = svgMatrix{1, 0, 0, 1, args[0], args[1]}
+		case name == "scale" && len(args) == 1:
+			t = svgMatrix{args[0], 0, 0, args[0], 0, 0}
+		case name == "scale" && len(args) == 2:
+			t = svgMatrix{args[0], 0, 0, args[1], 0, 0}
+		case name == "rotate" && (len(args) == 1 || len(args) == 3):
+			sin, cos := math.Sincos(args[0] * math.Pi / 180)
+			t = svgMatrix{cos, sin, -sin, cos, 0, 0}
+			if len(args) == 3 {
+				cx, cy := args[1], args[2]
+				t = svgMatrix{1, 0, 0, 1, cx, cy}.mul(t).mul(svgMatrix{1, 0, 0, 1, -cx, -cy})
+			}
+		case name == "skewX" && len(args) == 1:
+			t = svgMatrix{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}
+		case name == "skewY" && len(args) == 1:
+			t = svgMatrix{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}
+		default:
+			return m, fmt.Errorf("Invalid SVG transform %q", s)
+		}
+		m = m.mul(t)
+	}
+}
+
+// =============================================================================
+
+// svgBuilder turns the geometry of one element into transformed, flattened
+// contours.
+type svgBuilder struct {
+	m         svgMatrix
+	tolerance float64
+	contours  []Contour
+	cur       [][2]float64
+}
+
+func (b *svgBuilder) element(name string, attrs map[string]string) error {
+	num := func(key string) (float64, error) {
+		v, ok := attrs[key]
+		if !ok {
+			return 0, nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSuffix(v, "px"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid SVG length %q", v)
+		}
+		return f, nil
+	}
+	nums := func(keys ...string) ([]float64, error) {
+		vals := make([]float64, len(keys))
+		for i, key := range keys {
+			var err error
+			if vals[i], err = num(key); err != nil {
+				return nil, err
+			}
+		}
+		return vals, nil
+	}
+
+	switch name {
+	case "path":
+		if err := b.path(attrs["d"]); err != nil {
+			return err
+		}
+	case "rect":
+		v, err := nums("x", "y", "width", "height", "rx", "ry")
+		if err != nil {
+			return err
+		}
+		_, hasRx := attrs["rx"]
+		_, hasRy := attrs["ry"]
+		if !hasRx {
+			v[4] = v[5]
+		}
+		if !hasRy {
+			v[5] = v[4]
+		}
+		b.rect(v[0], v[1], v[2], v[3], v[4], v[5])
+	case "circle":
+		v, err := nums("cx", "cy", "r")
+		if err != nil {
+			return err
+		}
+		b.ellipse(v[0], v[1], v[2], v[2])
+	case "ellipse":
+		v, err := nums("cx", "cy", "rx", "ry")
+		if err != nil {
+			return err
+		}
+		b.ellipse(v[0], v[1], v[2], v[3])
+	case "polygon", "polyline":
+		// A filled polyline is closed like a polygon.
+		v, err := parseSVGNumbers(attrs["points"])
+		if err != nil {
+			return err
+		}
+		for i := 0; i+1 < len(v); i += 2 {
+			if i == 0 {
+				b.moveTo([2]float64{v[i], v[i+1]})
+			} else {
+				b.lineTo([2]float64{v[i], v[i+1]})
+			}
+		}
+	}
+	b.close()
+	return nil
+}
+
+func (b *svgBuilder) moveTo(p [2]float64) {
+	b.close()
+	b.cur = append(b.cur, b.m.apply(p))
+}
+
+func (b *svgBuilder) lineTo(p [2]float64) {
+	b.cur = append(b.cur, b.m.apply(p))
+}
+
+func (b *svgBuilder) quadTo(p1, p2 [2]float64) {
+	p0 := b.cur[len(b.cur)-1]
+	b.cur = flattenQuad(b.cur, p0, b.m.apply(p1), b.m.apply(p2), b.tolerance)
+}
+
+func (b *svgBuilder) cubicTo(p1, p2, p3 [2]float64) {
+	p0 := b.cur[len(b.cur)-1]
+	b.cur = flattenCubic(b.cur, p0, b.m.apply(p1), b.m.apply(p2), b.m.apply(p3), b.tolerance)
+}
+
+// arc appends the arc of the ellipse with center c, radii rx and ry and x
+// axis rotated by phi, from angle theta spanning sweep radians.
+func (b *svgBuilder) arc(c [2]float64, rx, ry, phi, theta, sweep float64) {
+	n := arcSegments(math.Max(rx, ry)*b.m.scale(), sweep, b.tolerance)
+	for _, p := range appendArc(nil, c, rx, ry, phi, theta, sweep, n) {
+		b.lineTo(p)
+	}
+}
+
+func (b *svgBuilder) rect(x, y, w, h, rx, ry float64) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	rx = math.Min(math.Abs(rx), w/2)
+	ry = math.Min(math.Abs(ry), h/2)
+	if rx == 0 || ry == 0 {
+		b.moveTo([2]float64{x, y})
+		b.lineTo([2]float64{x + w, y})
+		b.lineTo([2]float64{x + w, y + h})
+		b.lineTo([2]float64{x, y + h})
+		return
+	}
+	b.moveTo([2]float64{x + rx, y})
+	b.lineTo([2]float64{x + w - rx, y})
+	b.arc([2]float64{x + w - rx, y + ry}, rx, ry, 0, -math.Pi/2, math.Pi/2)
+	b.lineTo([2]float64{x + w, y + h - ry})
+	b.arc([2]float64{x + w - rx, y + h - ry}, rx, ry, 0, 0, math.Pi/2)
+	b.lineTo([2]float64{x + rx, y + h})
+	b.arc([2]float64{x + rx, y + h - ry}, rx, ry, 0, math.Pi/2, math.Pi/2)
+	b.lineTo([2]float64{x, y + ry})
+	b.arc([2]float64{x + rx, y + ry}, rx, ry, 0, math.Pi, math.Pi/2)
+}
+
+func (b *svgBuilder) ellipse(cx, cy, rx, ry float64) {
+	if rx <= 0 || ry <= 0 {
+		return
+	}
+	b.moveTo([2]float64{cx + rx, cy})
+	b.arc([2]float64{cx, cy}, rx, ry, 0, 0, 2*math.Pi)
+}
+
+// close ends the current contour. Contours enclosing no area are dropped.
+func (b *svgBuilder) close() {
+	cur := b.cur
+	b.cur = nil
+	if len(cur) > 1 && cur[0] == cur[len(cur)-1] {
+		cur = cur[:len(cur)-1]
+	}
+	if len(cur) < 3 {
+		return
+	}
+	c := make(Contour, len(cur))
+	for i, p := range cur {
+		c[i] = [3]float64{p[0], p[1], 0}
+	}
+	b.contours = append(b.contours, c)
+}
+
+// path adds the subpaths of SVG path data.
+func (b *svgBuilder) path(d string) error {
+	s := svgScanner{s: d}
+	var cmd byte
+	var cur, start, ctrl [2]float64
+	var prev byte
+
+	for {
+		s.skipSpace()
+		if s.done() {
+			return nil
+		}
+		if c := s.s[s.i]; isSVGCommand(c) {
+			cmd = c
+			s.i++
+		} else if cmd == 0 {
+			return fmt.Errorf("Invalid SVG path data %q", d)
+		}
+		if prev == 0 && cmd != 'M' && cmd != 'm' {
+			// Path data must start with a move.
+			return fmt.Errorf("Invalid SVG path data %q", d)
+		}
+
+		rel := cmd >= 'a'
+		point := func() ([2]float64, error) {
+			x, err := s.number()
+			if err != nil {
+				return cur, err
+			}
+			y, err := s.number()
+			if err != nil {
+				return cur, err
+			}
+			if rel {
+				x, y = x+cur[0], y+cur[1]
+			}
+			return [2]float64{x, y}, nil
+		}
+		reflect := func(c byte) [2]float64 {
+			if prev == c || prev == c+'a'-'A' {
+				return [2]float64{2*cur[0] - ctrl[0], 2*cur[1] - ctrl[1]}
+			}
+			return cur
+		}
+
+		var err error
+		switch cmd {
+		case 'M', 'm':
+			if cur, err = point(); err != nil {
+				break
+			}
+			b.moveTo(cur)
+			start = cur
+			// Further coordinate pairs are implicit line commands.
+			cmd -= 'M' - 'L'
+		case 'L', 'l':
+			if cur, err = point(); err == nil {
+				b.lineTo(cur)
+			}
+		case 'H', 'h':
+			var x float64
+			if x, err = s.number(); err == nil {
+				if rel {
+					x += cur[0]
+				}
+				cur[0] = x
+				b.lineTo(cur)
+			}
+		case 'V', 'v':
+			var y float64
+			if y, err = s.number(); err == nil {
+				if rel {
+					y += cur[1]
+				}
+				cur[1] = y
+				b.lineTo(cur)
+			}
+		case 'C', 'c', 'S', 's':
+			p1 := reflect('C')
+			if cmd == 'C' || cmd == 'c' {
+				if p1, err = point(); err != nil {
+					break
+				}
+			}
+			var p2, p3 [2]float64
+			if p2, err = point(); err != nil {
+				break
+			}
+			if p3, err = point(); err != nil {
+				break
+			}
+			b.cubicTo(p1, p2, p3)
+			ctrl, cur = p2, p3
+		case 'Q', 'q', 'T', 't':
+			p1 := reflect('Q')
+			if cmd == 'Q' || cmd == 'q' {
+				if p1, err = point(); err != nil {
+					break
+				}
+			}
+			var p2 [2]float64
+			if p2, err = point(); err != nil {
+				break
+			}
+			b.quadTo(p1, p2)
+			ctrl, cur = p1, p2
+		case 'A', 'a':
+			var v [3]float64
+			for i := range v {
+				if v[i], err = s.number(); err != nil {
+					break
+				}
+			}
+			var large, sweep bool
+			if err == nil {
+				large, err = s.flag()
+			}
+			if err == nil {
+				sweep, err = s.flag()
+			}
+			var p [2]float64
+			if err == nil {
+				p, err = point()
+			}
+			if err == nil {
+				b.endpointArc(cur, p, v[0], v[1], v[2]*math.Pi/180, large, sweep)
+				cur = p
+			}
+		case 'Z', 'z':
+			b.close()
+			cur = start
+			// A new subpath starts at the same point unless moved.
+			b.cur = append(b.cur, b.m.apply(cur))
+		}
+		if err != nil {
+			return fmt.Errorf("Invalid SVG path data %q", d)
+		}
+		prev = cmd
+		if prev == 'S' || prev == 's' {
+			prev = 'C'
+		} else if prev == 'T' || prev == 't' {
+			prev = 'Q'
+		}
+	}
+}
+
+// endpointArc appends an arc given in SVG endpoint parameterization.
+func (b *svgBuilder) endpointArc(p1, p2 [2]float64, rx, ry, phi float64, large, sweep bool) {
+	c, rx, ry, theta, delta, ok := arcCenter(p1, p2, rx, ry, phi, large, sweep)
+	if !ok {
+		if p1 != p2 {
+			b.lineTo(p2)
+		}
+		return
+	}
+	b.arc(c, rx, ry, phi, theta, delta)
+	// Land exactly on the end point.
+	b.cur[len(b.cur)-1] = b.m.apply(p2)
+}
+
+// =============================================================================
+
+func isSVGCommand(c byte) bool {
+	return strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0
+}
+
+// svgScanner reads numbers and flags from SVG attribute values.
+type svgScanner struct {
+	s string
+	i int
+}
+
+func (s *svgScanner) done() bool {
+	return s.i >= len(s.s)
+}
+
+func (s *svgScanner) skipSpace() {
+	for !s.done() && strings.IndexByte(" \t\r\n,", s.s[s.i]) >= 0 {
+		s.i++
+	}
+}
+
+// number reads a number, which in SVG need not be separated from the
+// next one by anything but its sign or a second decimal point.
+func (s *svgScanner) number() (float64, error) {
+	s.skipSpace()
+	start := s.i
+	if !s.done() && (s.s[s.i] == '+' || s.s[s.i] == '-') {
+		s.i++
+	}
+	digits := s.digits()
+	if !s.done() && s.s[s.i] == '.' {
+		s.i++
+		digits += s.digits()
+	}
+	if digits == 0 {
+		s.i = start
+		return 0, fmt.Errorf("Expected a number at %q", s.s[start:])
+	}
+	if !s.done() && (s.s[s.i] == 'e' || s.s[s.i] == 'E') {
+		mark := s.i
+		s.i++
+		if !s.done() && (s.s[s.i] == '+' || s.s[s.i] == '-') {
+			s.i++
+		}
+		if s.digits() == 0 {
+			s.i = mark
+		}
+	}
+	return strconv.ParseFloat(s.s[start:s.i], 64)
+}
+
+func (s *svgScanner) digits() int {
+	n := 0
+	for !s.done() && s.s[s.i] >= '0' && s.s[s.i] <= '9' {
+		s.i++
+		n++
+	}
+	return n
+}
+
+// flag reads an arc flag, a single 0 or 1.
+func (s *svgScanner) flag() (bool, error) {
+	s.skipSpace()
+	if s.done() || (s.s[s.i] != '0' && s.s[s.i] != '1') {
+		return false, fmt.Errorf("Expected a flag at %q", s.s[s.i:])
+	}
+	s.i++
+	return s.s[s.i-1] == '1', nil
+}
+
+// parseSVGNumbers parses a list of numbers.
+func parseSVGNumbers(v string) ([]float64, error) {
+	s := svgScanner{s: v}
+	var nums []float64
+	for {
+		s.skipSpace()
+		if s.done() {
+			return nums, nil
+		}
+		f, err := s.number()
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, f)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const testSVG = `<?xml version="1.0"?>
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100">
+  <defs><rect id="hidden" width="10" height="10"/></defs>
+  <rect id="rect" x="1" y="1" width="10" height="20"/>
+  <g transform="translate(50 0) scale(2)" fill-rule="evenodd">
+    <path id="ring" d="M0 0h10v10H0z m2 2v6h6V2z"/>
+    <polygon id="star" points="0,1 0.6,-0.8 -0.95,0.3 0.95,0.3 -0.6,-0.8" style="fill-rule:nonzero"/>
+  </g>
+  <circle id="circle" cx="0" cy="0" r="5"/>
+  <path id="curves" d="M0,0 C0,10 10,10 10,0 S20,-10 20,0 Q25,5 30,0 T40,0 A5 5 0 0 1 50 0 L50 -20 Z"/>
+  <rect id="rounded" width="10" height="10" rx="2"/>
+  <ellipse id="none" cx="0" cy="0" rx="5" ry="2" fill="none"/>
+</svg>`
+
+func TestReadSVG(t *testing.T) {
+	shapes, err := ReadSVG(strings.NewReader(testSVG), 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		id   string
+		rule uint32
+		area float64
+	}{
+		{"rect", TESS_WINDING_NONZERO, 200},
+		{"ring", TESS_WINDING_ODD, 4 * (100 - 36)},
+		{"star", TESS_WINDING_NONZERO, -1},
+		{"circle", TESS_WINDING_NONZERO, 25 * math.Pi},
+		{"curves", TESS_WINDING_NONZERO, -1},
+		{"rounded", TESS_WINDING_NONZERO, 100 - (4-math.Pi)*4},
+	}
+	if len(shapes) != len(expected) {
+		t.Fatalf("Expected %v shapes, got %v\n", len(expected), len(shapes))
+	}
+	for i, e := range expected {
+		s := shapes[i]
+		if s.ID != e.id || s.WindingRule != e.rule {
+			t.Errorf("Shape %v: expected %v with rule %v, got %v with rule %v\n",
+				i, e.id, e.rule, s.ID, s.WindingRule)
+		}
+		mesh, err := s.Tessellate()
+		if err != nil {
+			t.Errorf("Shape %v: %v\n", s.ID, err)
+			continue
+		}
+		area := math.Abs(meshArea(mesh))
+		if e.area >= 0 && math.Abs(area-e.area) > 0.25 {
+			t.Errorf("Shape %v: expected area %v, got %v\n", s.ID, e.area, area)
+		}
+		if area == 0 {
+			t.Errorf("Shape %v: got no area\n", s.ID)
+		}
+	}
+}
+
+func TestReadSVGArc(t *testing.T) {
+	// Two half circle arcs making up a circle of radius 10.
+	svg := `<svg><path d="M-10,0 a10,10 0 1,0 20,0 a10 10 0 1 0-20 0z"/></svg>`
+	shapes, err := ReadSVG(strings.NewReader(svg), 0.001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shapes) != 1 || len(shapes[0].Contours) != 1 {
+		t.Fatalf("Expected a single contour\n")
+	}
+	for _, v := range shapes[0].Contours[0] {
+		if r := math.Hypot(v[0], v[1]); math.Abs(r-10) > 1e-9 {
+			t.Errorf("Expected all vertices on the circle, got radius %v\n", r)
+		}
+	}
+	if area := contourArea(shapes[0].Contours); math.Abs(math.Abs(area)-100*math.Pi) > 0.05 {
+		t.Errorf("Expected area %v, got %v\n", 100*math.Pi, area)
+	}
+}
+
+func TestReadSVGError(t *testing.T) {
+	for _, svg := range []string{
+		`<svg><path d="L 10 10"/></svg>`,
+		`<svg><path d="M 0 0 L 10"/></svg>`,
+		`<svg><g transform="spin(1)"/></svg>`,
+		`<svg><rect width="10%"/></svg>`,
+	} {
+		if _, err := ReadSVG(strings.NewReader(svg), 0.1); err == nil {
+			t.Errorf("Expected an error for %v\n", svg)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+
+package glu
+
+import (
+	"math"
+)
+
+// tessMaxCoord is the largest coordinate accepted by Tesselator.Vertex;
+// larger values are clamped and reported as TESS_COORD_TOO_LARGE.
+const tessMaxCoord = 1.0e150
+
+func vertEq(u, v *tessVertex) bool {
+	return u.s == v.s && u.t == v.t
+}
+
+// vertLeq orders vertices lexicographically by (s, t).
+func vertLeq(u, v *tessVertex) bool {
+	return u.s < v.s || (u.s == v.s && u.t <= v.t)
+}
+
+// transLeq is vertLeq with s and t transposed.
+func transLeq(u, v *tessVertex) bool {
+	return u.t < v.t || (u.t == v.t && u.s <= v.s)
+}
+
+func vertL1dist(u, v *tessVertex) float64 {
+	return math.Abs(u.s-v.s) + math.Abs(u.t-v.t)
+}
+
+// edgeEval evaluates the t-coord of the edge uw at the s-coord of the
+// vertex v, given vertLeq(u, v) && vertLeq(v, w). It returns
+// v.t - (uw)(v.s), ie. the signed distance from uw to v. If uw is vertical
+// (and thus passes thru v), the result is zero.
+//
+// The calculation is extremely accurate and stable, even when v is very
+// close to u or w.
+func edgeEval(u, v, w *tessVertex) float64 {
+	gapL := v.s - u.s
+	gapR := w.s - v.s
+
+	if gapL+gapR > 0 {
+		if gapL < gapR {
+			return (v.t - u.t) + (u.t-w.t)*(gapL/(gapL+gapR))
+		}
+		return (v.t - w.t) + (w.t-u.t)*(gapR/(gapL+gapR))
+	}
+	// vertical line
+	return 0
+}
+
+// edgeSign returns a number whose sign matches edgeEval(u, v, w) but which
+// is cheaper to evaluate.
+func edgeSign(u, v, w *tessVertex) float64 {
+	gapL := v.s - u.s
+	gapR := w.s - v.s
+
+	if gapL+gapR > 0 {
+		return (v.t-w.t)*gapL + (v.t-u.t)*gapR
+	}
+	// vertical line
+	return 0
+}
+
+// transEval is edgeEval with s and t transposed.
+func transEval(u, v, w *tessVertex) float64 {
+	gapL := v.t - u.t
+	gapR := w.t - v.t
+
+	if gapL+gapR > 0 {
+		if gapL < gapR {
+			return (v.s - u.s) + (u.s-w.s)*(gapL/(gapL+gapR))
+		}
+		return (v.s - w.s) + (w.s-u.s)*(gapR/(gapL+gapR))
+	}
+	// vertical line
+	return 0
+}
+
+// transSign is edgeSign with s and t transposed.
+func transSign(u, v, w *tessVertex) float64 {
+	gapL := v.t - u.t
+	gapR := w.t - v.t
+
+	if gapL+gapR > 0 {
+		return (v.s-w.s)*gapL + (v.s-u.s)*gapR
+	}
+	// vertical line
+	return 0
+}
+
+// interpolate returns (b*x+a*y)/(a+b), or (x+y)/2 if a==b==0. It requires
+// that a,b >= 0, and enforces this in the rare case that one argument is
+// slightly negative. The result r is guaranteed to satisfy
+// MIN(x,y) <= r <= MAX(x,y).
+func interpolate(a, x, b, y float64) float64 {
+	if a < 0 {
+		a = 0
+	}
+	if b < 0 {
+		b = 0
+	}
+	if a <= b {
+		if b == 0 {
+			return (x + y) / 2
+		}
+		return x + (y-x)*(a/(a+b))
+	}
+	return y + (x-y)*(b/(a+b))
+}
+
+// edgeIntersect computes the intersection of edges (o1,d1) and (o2,d2)
+// and stores it in v. The computed point is guaranteed to lie in the
+// intersection of the bounding rectangles defined by each edge.
+func edgeIntersect(o1, d1, o2, d2, v *tessVertex) {
+	// This is certainly not the most efficient way to find the
+	// intersection of two line segments, but it is very numerically
+	// stable.
+	//
+	// Strategy: find the two middle vertices in the vertLeq ordering, and
+	// interpolate the intersection s-value from these. Then repeat using
+	// the transLeq ordering to find the intersection t-value.
+	if !vertLeq(o1, d1) {
+		o1, d1 = d1, o1
+	}
+	if !vertLeq(o2, d2) {
+		o2, d2 = d2, o2
+	}
+	if !vertLeq(o1, o2) {
+		o1, o2 = o2, o1
+		d1, d2 = d2, d1
+	}
+
+	if !vertLeq(o2, d1) {
+		// Technically, no intersection -- do our best.
+		v.s = (o2.s + d1.s) / 2
+	} else if vertLeq(d1, d2) {
+		// Interpolate between o2 and d1.
+		z1 := edgeEval(o1, o2, d1)
+		z2 := edgeEval(o2, d1, d2)
+		if z1+z2 < 0 {
+			z1 = -z1
+			z2 = -z2
+		}
+		v.s = interpolate(z1, o2.s, z2, d1.s)
+	} else {
+		// Interpolate between o2 and d2.
+		z1 := edgeSign(o1, o2, d1)
+		z2 := -edgeSign(o1, d2, d1)
+		if z1+z2 < 0 {
+			z1 = -z1
+			z2 = -z2
+		}
+		v.s = interpolate(z1, o2.s, z2, d2.s)
+	}
+
+	// Now repeat the process for t.
+	if !transLeq(o1, d1) {
+		o1, d1 = d1, o1
+	}
+	if !transLeq(o2, d2) {
+		o2, d2 = d2, o2
+	}
+	if !transLeq(o1, o2) {
+		o1, o2 = o2, o1
+		d1, d2 = d2, d1
+	}
+
+	if !transLeq(o2, d1) {
+		// Technically, no intersection -- do our best.
+		v.t = (o2.t + d1.t) / 2
+	} else if transLeq(d1, d2) {
+		// Interpolate between o2 and d1.
+		z1 := transEval(o1, o2, d1)
+		z2 := transEval(o2, d1, d2)
+		if z1+z2 < 0 {
+			z1 = -z1
+			z2 = -z2
+		}
+		v.t = interpolate(z1, o2.t, z2, d1.t)
+	} else {
+		// Interpolate between o2 and d2.
+		z1 := transSign(o1, o2, d1)
+		z2 := -transSign(o1, d2, d1)
+		if z1+z2 < 0 {
+			z1 = -z1
+			z2 = -z2
+		}
+		v.t = interpolate(z1, o2.t, z2, d2.t)
+	}
+}
+
+// longAxis returns the index of the largest component of v.
+func longAxis(v [3]float64) int {
+	i := 0
+	if math.Abs(v[1]) > math.Abs(v[0]) {
+		i = 1
+	}
+	if math.Abs(v[2]) > math.Abs(v[i]) {
+		i = 2
+	}
+	return i
+}
+
+// computeNormal finds a normal for the polygon from the triangle of
+// maximum area spanned by its vertices.
+func (tess *Tesselator) computeNormal() (norm [3]float64) {
+	var maxVert, minVert [3]*tessVertex
+	maxVal := [3]float64{-2 * tessMaxCoord, -2 * tessMaxCoord, -2 * tessMaxCoord}
+	minVal := [3]float64{2 * tessMaxCoord, 2 * tessMaxCoord, 2 * tessMaxCoord}
+
+	vHead := &tess.mesh.vHead
+	for v := vHead.next; v != vHead; v = v.next {
+		for i := 0; i < 3; i++ {
+			c := v.coords[i]
+			if c < minVal[i] {
+				minVal[i] = c
+				minVert[i] = v
+			}
+			if c > maxVal[i] {
+				maxVal[i] = c
+				maxVert[i] = v
+			}
+		}
+	}
+
+	// Find two vertices separated by at least 1/sqrt(3) of the maximum
+	// distance between any two vertices.
+	i := 0
+	if maxVal[1]-minVal[1] > maxVal[0]-minVal[0] {
+		i = 1
+	}
+	if maxVal[2]-minVal[2] > maxVal[i]-minVal[i] {
+		i = 2
+	}
+	if minVal[i] >= maxVal[i] {
+		// All vertices are the same -- normal doesn't matter.
+		return [3]float64{0, 0, 1}
+	}
+
+	// Look for a third vertex which forms the triangle with maximum area
+	// (Length of normal == twice the triangle area).
+	maxLen2 := 0.0
+	v1 := minVert[i]
+	v2 := maxVert[i]
+	var d1, d2 [3]float64
+	for j := 0; j < 3; j++ {
+		d1[j] = v1.coords[j] - v2.coords[j]
+	}
+	for v := vHead.next; v != vHead; v = v.next {
+		for j := 0; j < 3; j++ {
+			d2[j] = v.coords[j] - v2.coords[j]
+		}
+		tNorm := [3]float64{
+			d1[1]*d2[2] - d1[2]*d2[1],
+			d1[2]*d2[0] - d1[0]*d2[2],
+			d1[0]*d2[1] - d1[1]*d2[0],
+		}
+		tLen2 := tNorm[0]*tNorm[0] + tNorm[1]*tNorm[1] + tNorm[2]*tNorm[2]
+		if tLen2 > maxLen2 {
+			maxLen2 = tLen2
+			norm = tNorm
+		}
+	}
+
+	if maxLen2 <= 0 {
+		// All points lie on a single line -- any decent normal will do.
+		norm = [3]float64{}
+		norm[longAxis(d1)] = 1
+	}
+	return norm
+}
+
+// checkOrientation flips the t axis if needed so that the sum of the signed
+// areas of all contours is non-negative.
+func (tess *Tesselator) checkOrientation() {
+	area := 0.0
+	fHead := &tess.mesh.fHead
+	for f := fHead.next; f != fHead; f = f.next {
+		e := f.anEdge
+		if e.winding <= 0 {
+			continue
+		}
+		for {
+			area += (e.org.s - e.dst().s) * (e.org.t + e.dst().t)
+			e = e.lnext
+			if e == f.anEdge {
+				break
+			}
+		}
+	}
+	if area < 0 {
+		// Reverse the orientation by flipping all the t-coordinates.
+		vHead := &tess.mesh.vHead
+		for v := vHead.next; v != vHead; v = v.next {
+			v.t = -v.t
+		}
+	}
+}
+
+// projectPolygon determines the polygon normal and projects the vertices
+// onto the plane of the polygon.
+func (tess *Tesselator) projectPolygon() {
+	norm := tess.normal
+	computedNormal := false
+	if norm[0] == 0 && norm[1] == 0 && norm[2] == 0 {
+		norm = tess.computeNormal()
+		computedNormal = true
+	}
+
+	// Project perpendicular to a coordinate axis -- better numerically.
+	var sUnit, tUnit [3]float64
+	i := longAxis(norm)
+	sUnit[(i+1)%3] = 1
+	if norm[i] > 0 {
+		tUnit[(i+2)%3] = 1
+	} else {
+		tUnit[(i+2)%3] = -1
+	}
+
+	vHead := &tess.mesh.vHead
+	for v := vHead.next; v != vHead; v = v.next {
+		v.s = v.coords[0]*sUnit[0] + v.coords[1]*sUnit[1] + v.coords[2]*sUnit[2]
+		v.t = v.coords[0]*tUnit[0] + v.coords[1]*tUnit[1] + v.coords[2]*tUnit[2]
+	}
+	if computedNormal {
+		tess.checkOrientation()
+	}
+}
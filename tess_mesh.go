@@ -0,0 +1,468 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+
+package glu
+
+// The pure Go tesselator is a port of the SGI libtess sources shipped with
+// libGLU. The mesh below is the half-edge structure from mesh.c: every edge
+// is stored as a pair of half-edges, and each half-edge knows its origin
+// vertex, its left face and the next edges around both.
+
+type tessVertex struct {
+	next     *tessVertex // next vertex (never nil)
+	prev     *tessVertex // previous vertex (never nil)
+	anEdge   *halfEdge   // a half-edge with this origin
+	data     interface{} // client's data
+	coords   [3]float64  // vertex location in 3D
+	s, t     float64     // projection onto the sweep plane
+	pqHandle int         // to allow deletion from priority queue
+}
+
+type tessFace struct {
+	next   *tessFace // next face (never nil)
+	prev   *tessFace // previous face (never nil)
+	anEdge *halfEdge // a half edge with this left face
+	trail  *tessFace // "stack" for conversion to strips
+	marked bool      // flag for conversion to strips
+	inside bool      // this face is in the polygon interior
+}
+
+type halfEdge struct {
+	next         *halfEdge     // doubly-linked list (prev==sym.next)
+	sym          *halfEdge     // same edge, opposite direction
+	onext        *halfEdge     // next edge CCW around origin
+	lnext        *halfEdge     // next edge CCW around left face
+	org          *tessVertex   // origin vertex
+	lface        *tessFace     // left face
+	activeRegion *activeRegion // a region with this upper edge (sweep)
+	winding      int           // winding change from right to left face
+	second       bool          // the second half-edge of its pair
+}
+
+func (e *halfEdge) rface() *tessFace     { return e.sym.lface }
+func (e *halfEdge) dst() *tessVertex     { return e.sym.org }
+func (e *halfEdge) oprev() *halfEdge     { return e.sym.lnext }
+func (e *halfEdge) lprev() *halfEdge     { return e.onext.sym }
+func (e *halfEdge) dprev() *halfEdge     { return e.lnext.sym }
+func (e *halfEdge) rprev() *halfEdge     { return e.sym.onext }
+func (e *halfEdge) dnext() *halfEdge     { return e.rprev().sym }
+func (e *halfEdge) rnext() *halfEdge     { return e.oprev().sym }
+func (e *halfEdge) goesLeft() bool       { return vertLeq(e.dst(), e.org) }
+func (e *halfEdge) goesRight() bool      { return vertLeq(e.org, e.dst()) }
+func (e *halfEdge) setDst(v *tessVertex) { e.sym.org = v }
+func (e *halfEdge) setRface(f *tessFace) { e.sym.lface = f }
+
+func addWinding(eDst, eSrc *halfEdge) {
+	eDst.winding += eSrc.winding
+	eDst.sym.winding += eSrc.sym.winding
+}
+
+type tessMesh struct {
+	vHead    tessVertex // dummy header for vertex list
+	fHead    tessFace   // dummy header for face list
+	eHead    halfEdge   // dummy header for edge list
+	eHeadSym halfEdge   // and its symmetric counterpart
+}
+
+// newTessMesh creates a mesh with no edges, vertices or faces.
+func newTessMesh() *tessMesh {
+	mesh := new(tessMesh)
+
+	v := &mesh.vHead
+	v.next = v
+	v.prev = v
+
+	f := &mesh.fHead
+	f.next = f
+	f.prev = f
+
+	e := &mesh.eHead
+	eSym := &mesh.eHeadSym
+	e.next = e
+	e.sym = eSym
+	eSym.next = eSym
+	eSym.sym = e
+	eSym.second = true
+
+	return mesh
+}
+
+// makeEdge creates a new pair of half-edges which form their own loop.
+// The edge is inserted in the edge list before eNext.
+func makeEdge(eNext *halfEdge) *halfEdge {
+	e := new(halfEdge)
+	eSym := &halfEdge{second: true}
+
+	// Make sure eNext points to the first edge of the edge pair.
+	if eNext.second {
+		eNext = eNext.sym
+	}
+
+	// Insert in circular doubly-linked list before eNext. Note that the
+	// prev pointer is stored in sym.next.
+	ePrev := eNext.sym.next
+	eSym.next = ePrev
+	ePrev.sym.next = e
+	e.next = eNext
+	eNext.sym.next = eSym
+
+	e.sym = eSym
+	e.onext = e
+	e.lnext = eSym
+
+	eSym.sym = e
+	eSym.onext = eSym
+	eSym.lnext = e
+
+	return e
+}
+
+// splice is the basic operation for changing the mesh connectivity and
+// topology. It changes the mesh so that
+//
+//	a.onext <- OLD(b.onext)
+//	b.onext <- OLD(a.onext)
+//	a.onext.sym.lnext <- b
+//	b.onext.sym.lnext <- a
+//
+// If a and b share an origin, the vertex is split in two; otherwise the
+// two origins are merged. Similarly for the left faces.
+func splice(a, b *halfEdge) {
+	aOnext := a.onext
+	bOnext := b.onext
+
+	aOnext.sym.lnext = b
+	bOnext.sym.lnext = a
+	a.onext = bOnext
+	b.onext = aOnext
+}
+
+// makeVertex attaches a new vertex and makes it the origin of all edges in
+// the vertex loop to which eOrig belongs. vNext gives a place to insert the
+// new vertex in the global vertex list.
+func makeVertex(eOrig *halfEdge, vNext *tessVertex) {
+	vNew := new(tessVertex)
+
+	// Insert in circular doubly-linked list before vNext.
+	vPrev := vNext.prev
+	vNew.prev = vPrev
+	vPrev.next = vNew
+	vNew.next = vNext
+	vNext.prev = vNew
+
+	vNew.anEdge = eOrig
+
+	// Fix other edges on this vertex loop.
+	e := eOrig
+	for {
+		e.org = vNew
+		e = e.onext
+		if e == eOrig {
+			break
+		}
+	}
+}
+
+// makeFace attaches a new face and makes it the left face of all edges in
+// the face loop to which eOrig belongs. fNext gives a place to insert the
+// new face in the global face list.
+func makeFace(eOrig *halfEdge, fNext *tessFace) {
+	fNew := new(tessFace)
+
+	// Insert in circular doubly-linked list before fNext.
+	fPrev := fNext.prev
+	fNew.prev = fPrev
+	fPrev.next = fNew
+	fNew.next = fNext
+	fNext.prev = fNew
+
+	fNew.anEdge = eOrig
+
+	// The new face is marked "inside" if the old one was. This is a
+	// convenience for the common case where a face has been split in two.
+	fNew.inside = fNext.inside
+
+	// Fix other edges on this face loop.
+	e := eOrig
+	for {
+		e.lface = fNew
+		e = e.lnext
+		if e == eOrig {
+			break
+		}
+	}
+}
+
+// killEdge removes the edge pair of eDel from the global edge list.
+func killEdge(eDel *halfEdge) {
+	if eDel.second {
+		eDel = eDel.sym
+	}
+
+	eNext := eDel.next
+	ePrev := eDel.sym.next
+	eNext.sym.next = ePrev
+	ePrev.sym.next = eNext
+}
+
+// killVertex destroys a vertex and removes it from the global vertex list.
+// It updates the vertex loop to point to newOrg.
+func killVertex(vDel *tessVertex, newOrg *tessVertex) {
+	eStart := vDel.anEdge
+
+	// Change the origin of all affected edges.
+	e := eStart
+	for {
+		e.org = newOrg
+		e = e.onext
+		if e == eStart {
+			break
+		}
+	}
+
+	vPrev := vDel.prev
+	vNext := vDel.next
+	vNext.prev = vPrev
+	vPrev.next = vNext
+}
+
+// killFace destroys a face and removes it from the global face list. It
+// updates the face loop to point to newLface.
+func killFace(fDel *tessFace, newLface *tessFace) {
+	eStart := fDel.anEdge
+
+	// Change the left face of all affected edges.
+	e := eStart
+	for {
+		e.lface = newLface
+		e = e.lnext
+		if e == eStart {
+			break
+		}
+	}
+
+	fPrev := fDel.prev
+	fNext := fDel.next
+	fNext.prev = fPrev
+	fPrev.next = fNext
+}
+
+// makeEdge creates one edge, two vertices, and a loop (face). The loop
+// consists of the two new half-edges.
+func (mesh *tessMesh) makeEdge() *halfEdge {
+	e := makeEdge(&mesh.eHead)
+
+	makeVertex(e, &mesh.vHead)
+	makeVertex(e.sym, &mesh.vHead)
+	makeFace(e, &mesh.fHead)
+	return e
+}
+
+// meshSplice joins or splits the origins and left faces of eOrg and eDst.
+// See splice for the exact topology change.
+func meshSplice(eOrg, eDst *halfEdge) {
+	joiningLoops := false
+	joiningVertices := false
+
+	if eOrg == eDst {
+		return
+	}
+
+	if eDst.org != eOrg.org {
+		// We are merging two disjoint vertices -- destroy eDst.org.
+		joiningVertices = true
+		killVertex(eDst.org, eOrg.org)
+	}
+	if eDst.lface != eOrg.lface {
+		// We are connecting two disjoint loops -- destroy eDst.lface.
+		joiningLoops = true
+		killFace(eDst.lface, eOrg.lface)
+	}
+
+	// Change the edge structure.
+	splice(eDst, eOrg)
+
+	if !joiningVertices {
+		// We split one vertex into two -- the new vertex is eDst.org.
+		// Make sure the old vertex points to a valid half-edge.
+		makeVertex(eDst, eOrg.org)
+		eOrg.org.anEdge = eOrg
+	}
+	if !joiningLoops {
+		// We split one loop into two -- the new loop is eDst.lface.
+		// Make sure the old face points to a valid half-edge.
+		makeFace(eDst, eOrg.lface)
+		eOrg.lface.anEdge = eOrg
+	}
+}
+
+// meshDelete removes the edge eDel. If this disconnects a vertex or face
+// the vertex or face is removed as well; if it joins two faces, one of
+// them is removed.
+func meshDelete(eDel *halfEdge) {
+	eDelSym := eDel.sym
+	joiningLoops := false
+
+	// First step: disconnect the origin vertex eDel.org. We make all
+	// changes to get a consistent mesh in this "intermediate" state.
+	if eDel.lface != eDel.rface() {
+		// We are joining two loops into one -- remove the left face.
+		joiningLoops = true
+		killFace(eDel.lface, eDel.rface())
+	}
+
+	if eDel.onext == eDel {
+		killVertex(eDel.org, nil)
+	} else {
+		// Make sure that eDel.org and eDel.rface point to valid
+		// half-edges.
+		eDel.rface().anEdge = eDel.oprev()
+		eDel.org.anEdge = eDel.onext
+
+		splice(eDel, eDel.oprev())
+		if !joiningLoops {
+			// We are splitting one loop into two -- create a new loop
+			// for eDel.
+			makeFace(eDel, eDel.lface)
+		}
+	}
+
+	// Claim: the mesh is now in a consistent state, except that eDel.org
+	// may have been deleted. Now we disconnect eDel.dst.
+	if eDelSym.onext == eDelSym {
+		killVertex(eDelSym.org, nil)
+		killFace(eDelSym.lface, nil)
+	} else {
+		// Make sure that eDel.dst and eDel.lface point to valid
+		// half-edges.
+		eDel.lface.anEdge = eDelSym.oprev()
+		eDelSym.org.anEdge = eDelSym.onext
+		splice(eDelSym, eDelSym.oprev())
+	}
+
+	// Any isolated vertices or faces have already been removed.
+	killEdge(eDel)
+}
+
+// meshAddEdgeVertex creates a new edge eNew such that eNew == eOrg.lnext
+// and eNew.dst is a newly created vertex. eOrg and eNew will have the same
+// left face.
+func meshAddEdgeVertex(eOrg *halfEdge) *halfEdge {
+	eNew := makeEdge(eOrg)
+	eNewSym := eNew.sym
+
+	// Connect the new edge appropriately.
+	splice(eNew, eOrg.lnext)
+
+	// Set the vertex and face information.
+	eNew.org = eOrg.dst()
+	makeVertex(eNewSym, eNew.org)
+	eNew.lface = eOrg.lface
+	eNewSym.lface = eOrg.lface
+
+	return eNew
+}
+
+// meshSplitEdge splits eOrg into two edges eOrg and eNew, such that
+// eNew == eOrg.lnext. The new vertex is eOrg.dst == eNew.org. eOrg and
+// eNew will have the same left face.
+func meshSplitEdge(eOrg *halfEdge) *halfEdge {
+	tempHalfEdge := meshAddEdgeVertex(eOrg)
+	eNew := tempHalfEdge.sym
+
+	// Disconnect eOrg from eOrg.dst and connect it to eNew.org.
+	splice(eOrg.sym, eOrg.sym.oprev())
+	splice(eOrg.sym, eNew)
+
+	// Set the vertex and face information.
+	eOrg.setDst(eNew.org)
+	eNew.dst().anEdge = eNew.sym // may have pointed to eOrg.sym
+	eNew.setRface(eOrg.rface())
+	eNew.winding = eOrg.winding // copy old winding information
+	eNew.sym.winding = eOrg.sym.winding
+
+	return eNew
+}
+
+// meshConnect creates a new edge from eOrg.dst to eDst.org, and returns
+// the corresponding half-edge eNew. If eOrg.lface == eDst.lface, this
+// splits one loop into two, and the newly created loop is eNew.lface.
+// Otherwise, two disjoint loops are merged into one, and the loop
+// eDst.lface is destroyed.
+func meshConnect(eOrg, eDst *halfEdge) *halfEdge {
+	joiningLoops := false
+	eNew := makeEdge(eOrg)
+	eNewSym := eNew.sym
+
+	if eDst.lface != eOrg.lface {
+		// We are connecting two disjoint loops -- destroy eDst.lface.
+		joiningLoops = true
+		killFace(eDst.lface, eOrg.lface)
+	}
+
+	// Connect the new edge appropriately.
+	splice(eNew, eOrg.lnext)
+	splice(eNewSym, eDst)
+
+	// Set the vertex and face information.
+	eNew.org = eOrg.dst()
+	eNewSym.org = eDst.org
+	eNew.lface = eOrg.lface
+	eNewSym.lface = eOrg.lface
+
+	// Make sure the old face points to a valid half-edge.
+	eOrg.lface.anEdge = eNewSym
+
+	if !joiningLoops {
+		// We split one loop into two -- the new loop is eNew.lface.
+		makeFace(eNew, eOrg.lface)
+	}
+	return eNew
+}
+
+// meshZapFace destroys a face and removes it from the global face list.
+// All edges of fZap will have a nil left face. Edges whose right face is
+// also nil are deleted entirely, as are any isolated vertices.
+func meshZapFace(fZap *tessFace) {
+	eStart := fZap.anEdge
+
+	// Walk around face, deleting edges whose right face is also nil.
+	eNext := eStart.lnext
+	for {
+		e := eNext
+		eNext = e.lnext
+
+		e.lface = nil
+		if e.rface() == nil {
+			// Delete the edge -- see meshDelete above.
+			if e.onext == e {
+				killVertex(e.org, nil)
+			} else {
+				// Make sure that e.org points to a valid half-edge.
+				e.org.anEdge = e.onext
+				splice(e, e.oprev())
+			}
+			eSym := e.sym
+			if eSym.onext == eSym {
+				killVertex(eSym.org, nil)
+			} else {
+				// Make sure that eSym.org points to a valid half-edge.
+				eSym.org.anEdge = eSym.onext
+				splice(eSym, eSym.oprev())
+			}
+			killEdge(e)
+		}
+		if e == eStart {
+			break
+		}
+	}
+
+	// Delete from circular doubly-linked list.
+	fPrev := fZap.prev
+	fNext := fZap.next
+	fNext.prev = fPrev
+	fPrev.next = fNext
+}
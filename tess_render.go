@@ -0,0 +1,354 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+
+package glu
+
+// Monotone region triangulation from tessmono.c and primitive output from
+// render.c.
+
+// tessellateMonoRegion tessellates a monotone region (what else would it
+// do??). The region must consist of a single loop of half-edges (see
+// mesh.go) oriented CCW. "Monotone" in this case means that any vertical
+// line intersects the interior of the region in a single interval.
+//
+// Tessellation consists of adding interior edges (actually pairs of
+// half-edges), to split the region into non-overlapping triangles.
+func tessellateMonoRegion(face *tessFace) {
+	// All edges are oriented CCW around the boundary of the region. First,
+	// find the half-edge whose origin vertex is rightmost. Since the sweep
+	// goes from left to right, face.anEdge should be close to the edge we
+	// want.
+	up := face.anEdge
+
+	for vertLeq(up.dst(), up.org) {
+		up = up.lprev()
+	}
+	for vertLeq(up.org, up.dst()) {
+		up = up.lnext
+	}
+	lo := up.lprev()
+
+	for up.lnext != lo {
+		if vertLeq(up.dst(), lo.org) {
+			// up.dst is on the left. It is safe to form triangles from
+			// lo.org. The goesLeft test guarantees progress even when some
+			// triangles are CW, given that the upper and lower chains are
+			// truly monotone.
+			for lo.lnext != up && (lo.lnext.goesLeft() ||
+				edgeSign(lo.org, lo.dst(), lo.lnext.dst()) <= 0) {
+				lo = meshConnect(lo.lnext, lo).sym
+			}
+			lo = lo.lprev()
+		} else {
+			// lo.org is on the left. We can make CCW triangles from up.dst.
+			for lo.lnext != up && (up.lprev().goesRight() ||
+				edgeSign(up.dst(), up.org, up.lprev().org) >= 0) {
+				up = meshConnect(up, up.lprev()).sym
+			}
+			up = up.lnext
+		}
+	}
+
+	// Now lo.org == up.dst == the leftmost vertex. The remaining region can
+	// be tessellated in a fan from this leftmost vertex.
+	for lo.lnext.lnext != up {
+		lo = meshConnect(lo.lnext, lo).sym
+	}
+}
+
+// tessellateInterior tessellates each region of the mesh which is marked
+// "inside" the polygon. Each such region must be monotone.
+func (mesh *tessMesh) tessellateInterior() {
+	var next *tessFace
+	for f := mesh.fHead.next; f != &mesh.fHead; f = next {
+		// Make sure we don't try to tessellate the new triangles.
+		next = f.next
+		if f.inside {
+			tessellateMonoRegion(f)
+		}
+	}
+}
+
+// setWindingNumber resets the winding numbers on all edges so that regions
+// marked "inside" the polygon have a winding number of value, and regions
+// outside have a winding number of 0.
+//
+// If keepOnlyBoundary is true, it also deletes all edges which do not
+// separate an interior region from an exterior one.
+func (mesh *tessMesh) setWindingNumber(value int, keepOnlyBoundary bool) {
+	var eNext *halfEdge
+	for e := mesh.eHead.next; e != &mesh.eHead; e = eNext {
+		eNext = e.next
+		if e.rface().inside != e.lface.inside {
+			// This is a boundary edge (one side is interior, one is
+			// exterior).
+			if e.lface.inside {
+				e.winding = value
+			} else {
+				e.winding = -value
+			}
+		} else {
+			// Both regions are interior, or both are exterior.
+			if !keepOnlyBoundary {
+				e.winding = 0
+			} else {
+				meshDelete(e)
+			}
+		}
+	}
+}
+
+// =============================================================================
+
+// faceCount describes a group of triangles and how to render it.
+type faceCount struct {
+	size   int
+	eStart *halfEdge
+	render func(tess *Tesselator, e *halfEdge, size int)
+}
+
+func isMarked(f *tessFace) bool {
+	return !f.inside || f.marked
+}
+
+func addToTrail(f *tessFace, t **tessFace) {
+	f.trail = *t
+	*t = f
+	f.marked = true
+}
+
+func freeTrail(t *tessFace) {
+	for t != nil {
+		t.marked = false
+		t = t.trail
+	}
+}
+
+// renderMesh takes a mesh and breaks it into triangle fans, strips, and
+// separate triangles. A substantial effort is made to use as few rendering
+// primitives as possible (ie. to make the fans and strips as large as
+// possible).
+func (tess *Tesselator) renderMesh(mesh *tessMesh) {
+	// Make a list of separate triangles so we can render them all at once.
+	tess.lonelyTriList = nil
+
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		f.marked = false
+	}
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		// We examine all faces in an arbitrary order. Whenever we find an
+		// unprocessed face F, we output a group of faces including F whose
+		// size is maximum.
+		if f.inside && !f.marked {
+			tess.renderMaximumFaceGroup(f)
+		}
+	}
+	if tess.lonelyTriList != nil {
+		tess.renderLonelyTriangles(tess.lonelyTriList)
+		tess.lonelyTriList = nil
+	}
+}
+
+// renderMaximumFaceGroup finds the largest triangle fan or strip of
+// unmarked faces which includes the given face fOrig. There are 3 possible
+// fans passing through fOrig (one centered at each vertex), and 3 possible
+// strips (one for each CCW permutation of the vertices). Our strategy is to
+// try all of these, and take the primitive which uses the most triangles
+// (a greedy approach).
+func (tess *Tesselator) renderMaximumFaceGroup(fOrig *tessFace) {
+	e := fOrig.anEdge
+	max := faceCount{1, e, renderTriangle}
+
+	if tess.edgeFlagData == nil {
+		for _, newFace := range []faceCount{
+			maximumFan(e),
+			maximumFan(e.lnext),
+			maximumFan(e.lprev()),
+			maximumStrip(e),
+			maximumStrip(e.lnext),
+			maximumStrip(e.lprev()),
+		} {
+			if newFace.size > max.size {
+				max = newFace
+			}
+		}
+	}
+	max.render(tess, max.eStart, max.size)
+}
+
+// maximumFan finds the size of a maximal fan around eOrig.org, for the face
+// eOrig.lface. To do this we just walk around the origin vertex as far as
+// possible in both directions.
+func maximumFan(eOrig *halfEdge) faceCount {
+	newFace := faceCount{0, nil, renderFan}
+	var trail *tessFace
+
+	e := eOrig
+	for ; !isMarked(e.lface); e = e.onext {
+		addToTrail(e.lface, &trail)
+		newFace.size++
+	}
+	for e = eOrig; !isMarked(e.rface()); e = e.oprev() {
+		addToTrail(e.rface(), &trail)
+		newFace.size++
+	}
+	newFace.eStart = e
+
+	freeTrail(trail)
+	return newFace
+}
+
+// maximumStrip looks for a maximal strip that contains the vertices
+// eOrig.org, eOrig.dst, eOrig.lnext.dst (in that order or the reverse, such
+// that all triangles are oriented CCW).
+//
+// Again we walk forward and backward as far as possible. However for strips
+// there is a twist: to get CCW orientations, there must be an *even* number
+// of triangles in the strip on one side of eOrig. We walk the strip
+// starting on a side with an even number of triangles; if both side have an
+// odd number, we are forced to shorten one side.
+func maximumStrip(eOrig *halfEdge) faceCount {
+	newFace := faceCount{0, nil, renderStrip}
+	headSize, tailSize := 0, 0
+	var trail *tessFace
+
+	e := eOrig
+	for ; !isMarked(e.lface); e = e.onext {
+		addToTrail(e.lface, &trail)
+		tailSize++
+		e = e.dprev()
+		if isMarked(e.lface) {
+			break
+		}
+		addToTrail(e.lface, &trail)
+		tailSize++
+	}
+	eTail := e
+
+	for e = eOrig; !isMarked(e.rface()); e = e.dnext() {
+		addToTrail(e.rface(), &trail)
+		headSize++
+		e = e.oprev()
+		if isMarked(e.rface()) {
+			break
+		}
+		addToTrail(e.rface(), &trail)
+		headSize++
+	}
+	eHead := e
+
+	newFace.size = tailSize + headSize
+	if tailSize%2 == 0 {
+		newFace.eStart = eTail.sym
+	} else if headSize%2 == 0 {
+		newFace.eStart = eHead
+	} else {
+		// Both sides have odd length, we must shorten one of them. In fact,
+		// we must start from eHead to guarantee inclusion of
+		// eOrig.lface.
+		newFace.size--
+		newFace.eStart = eHead.onext
+	}
+
+	freeTrail(trail)
+	return newFace
+}
+
+// renderTriangle just adds the triangle to a triangle list, so we can
+// render all the separate triangles at once.
+func renderTriangle(tess *Tesselator, e *halfEdge, size int) {
+	addToTrail(e.lface, &tess.lonelyTriList)
+}
+
+func (tess *Tesselator) renderLonelyTriangles(f *tessFace) {
+	// Force edge state output for first vertex.
+	edgeState := -1
+
+	tess.callBegin(TRIANGLES)
+	for ; f != nil; f = f.trail {
+		// Loop once for each edge (there will always be 3 edges).
+		e := f.anEdge
+		for {
+			if tess.edgeFlagData != nil {
+				// Set the "edge state" to true just before we output the
+				// first vertex of each edge on the polygon boundary.
+				newState := 0
+				if !e.rface().inside {
+					newState = 1
+				}
+				if edgeState != newState {
+					edgeState = newState
+					tess.edgeFlagData(edgeState == 1, tess.polyData)
+				}
+			}
+			tess.callVertex(e.org.data)
+
+			e = e.lnext
+			if e == f.anEdge {
+				break
+			}
+		}
+	}
+	tess.callEnd()
+}
+
+// renderFan renders as many CCW triangles as possible in a fan starting
+// from edge e. The fan *should* contain exactly size triangles.
+func renderFan(tess *Tesselator, e *halfEdge, size int) {
+	tess.callBegin(TRIANGLE_FAN)
+	tess.callVertex(e.org.data)
+	tess.callVertex(e.dst().data)
+
+	for !isMarked(e.lface) {
+		e.lface.marked = true
+		e = e.onext
+		tess.callVertex(e.dst().data)
+	}
+
+	tess.callEnd()
+}
+
+// renderStrip renders as many CCW triangles as possible in a strip
+// starting from edge e. The strip *should* contain exactly size triangles.
+func renderStrip(tess *Tesselator, e *halfEdge, size int) {
+	tess.callBegin(TRIANGLE_STRIP)
+	tess.callVertex(e.org.data)
+	tess.callVertex(e.dst().data)
+
+	for !isMarked(e.lface) {
+		e.lface.marked = true
+		e = e.dprev()
+		tess.callVertex(e.org.data)
+		if isMarked(e.lface) {
+			break
+		}
+
+		e.lface.marked = true
+		e = e.onext
+		tess.callVertex(e.dst().data)
+	}
+
+	tess.callEnd()
+}
+
+// renderBoundary takes a mesh, and outputs one contour for each face
+// marked "inside". The rendering output is provided as callbacks.
+func (tess *Tesselator) renderBoundary(mesh *tessMesh) {
+	for f := mesh.fHead.next; f != &mesh.fHead; f = f.next {
+		if f.inside {
+			tess.callBegin(LINE_LOOP)
+			e := f.anEdge
+			for {
+				tess.callVertex(e.org.data)
+				e = e.lnext
+				if e == f.anEdge {
+					break
+				}
+			}
+			tess.callEnd()
+		}
+	}
+}
@@ -0,0 +1,1174 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+
+package glu
+
+import (
+	"math"
+)
+
+// The sweep line algorithm from sweep.c. Vertices are processed in
+// lexicographic (s, t) order; the edges crossing the sweep line are kept in
+// a dictionary sorted from bottom to top, and the gaps between consecutive
+// edges are "active regions" whose winding numbers decide which parts of
+// the plane are inside the polygon.
+//
+// Invariants for the edge dictionary:
+//   - each pair of adjacent edges e2=succ(e1) satisfies edgeLeq(e1,e2) at
+//     any valid location of the sweep event
+//   - if edgeLeq(e2,e1) as well (at any valid sweep event), then e1 and e2
+//     share a common endpoint
+//   - for each e, e.dst has been processed, but not e.org
+//   - each edge e satisfies vertLeq(e.dst,event) && vertLeq(event,e.org)
+//     where "event" is the current sweep line event.
+//   - no edge e has zero length
+//
+// Invariants for the mesh (the processed portion):
+//   - the portion of the mesh left of the sweep line is a planar graph,
+//     ie. there is *some* way to embed it in the plane
+//   - no processed edge has zero length
+//   - no two processed vertices have identical coordinates
+//   - each "inside" region is monotone, ie. can be broken into two chains
+//     of monotonically increasing vertices according to vertLeq(v1,v2)
+//     - a non-invariant: these chains may intersect (very slightly)
+//
+// Invariants for the sweep:
+//   - if none of the edges incident to the event vertex have an
+//     activeRegion (ie. none of these edges are in the edge dictionary),
+//     then the vertex has only right-going edges.
+//   - if an edge is marked "fixUpperEdge" (it is a temporary edge
+//     introduced by connectRightVertex), then it is the only right-going
+//     edge from its associated vertex. (This says that these edges exist
+//     only when it is necessary.)
+
+// sentinelCoord is big enough that the sentinels will never be merged with
+// real input features.
+const sentinelCoord = 4 * tessMaxCoord
+
+type activeRegion struct {
+	eUp           *halfEdge // upper edge, directed right to left
+	nodeUp        *dictNode // dictionary node corresponding to eUp
+	windingNumber int       // used to determine which regions are inside
+	inside        bool      // is this region inside the polygon?
+	sentinel      bool      // marks fake edges at t = +/-infinity
+	dirty         bool      // upper or lower edge changed since checked
+	fixUpperEdge  bool      // temporary edge from connectRightVertex
+}
+
+func (reg *activeRegion) below() *activeRegion { return reg.nodeUp.prev.key }
+func (reg *activeRegion) above() *activeRegion { return reg.nodeUp.next.key }
+
+// =============================================================================
+
+// dictNode is an entry of the edge dictionary, a sorted doubly-linked list
+// of active regions.
+type dictNode struct {
+	key  *activeRegion
+	next *dictNode
+	prev *dictNode
+}
+
+type edgeDict struct {
+	head dictNode
+	tess *Tesselator
+}
+
+func newEdgeDict(tess *Tesselator) *edgeDict {
+	dict := &edgeDict{tess: tess}
+	dict.head.next = &dict.head
+	dict.head.prev = &dict.head
+	return dict
+}
+
+func (dict *edgeDict) min() *dictNode { return dict.head.next }
+
+func (dict *edgeDict) insert(key *activeRegion) *dictNode {
+	return dict.insertBefore(&dict.head, key)
+}
+
+func (dict *edgeDict) insertBefore(node *dictNode, key *activeRegion) *dictNode {
+	for {
+		node = node.prev
+		if node.key == nil || dict.tess.edgeLeq(node.key, key) {
+			break
+		}
+	}
+
+	newNode := &dictNode{key: key, next: node.next, prev: node}
+	node.next.prev = newNode
+	node.next = newNode
+	return newNode
+}
+
+func (dict *edgeDict) delete(node *dictNode) {
+	node.next.prev = node.prev
+	node.prev.next = node.next
+}
+
+func (dict *edgeDict) search(key *activeRegion) *dictNode {
+	node := &dict.head
+	for {
+		node = node.next
+		if node.key == nil || dict.tess.edgeLeq(key, node.key) {
+			return node
+		}
+	}
+}
+
+// =============================================================================
+
+// priorityQ orders the sweep events. It is a binary heap keyed by vertLeq
+// in which every vertex remembers its position, so that vertices merged
+// during the sweep can be removed.
+type priorityQ struct {
+	heap []*tessVertex
+}
+
+func (pq *priorityQ) isEmpty() bool {
+	return len(pq.heap) == 0
+}
+
+func (pq *priorityQ) insert(v *tessVertex) {
+	v.pqHandle = len(pq.heap)
+	pq.heap = append(pq.heap, v)
+	pq.up(v.pqHandle)
+}
+
+func (pq *priorityQ) minimum() *tessVertex {
+	if pq.isEmpty() {
+		return nil
+	}
+	return pq.heap[0]
+}
+
+func (pq *priorityQ) extractMin() *tessVertex {
+	if pq.isEmpty() {
+		return nil
+	}
+	v := pq.heap[0]
+	pq.delete(v)
+	return v
+}
+
+func (pq *priorityQ) delete(v *tessVertex) {
+	i := v.pqHandle
+	last := len(pq.heap) - 1
+	if i != last {
+		pq.swap(i, last)
+	}
+	pq.heap[last] = nil
+	pq.heap = pq.heap[:last]
+	if i != last {
+		pq.down(i)
+		pq.up(i)
+	}
+	v.pqHandle = -1
+}
+
+// less reports whether the vertex at i strictly precedes the one at j.
+func (pq *priorityQ) less(i, j int) bool {
+	return !vertLeq(pq.heap[j], pq.heap[i])
+}
+
+func (pq *priorityQ) swap(i, j int) {
+	pq.heap[i], pq.heap[j] = pq.heap[j], pq.heap[i]
+	pq.heap[i].pqHandle = i
+	pq.heap[j].pqHandle = j
+}
+
+func (pq *priorityQ) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(i, parent) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+func (pq *priorityQ) down(i int) {
+	n := len(pq.heap)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if child+1 < n && pq.less(child+1, child) {
+			child++
+		}
+		if !pq.less(child, i) {
+			break
+		}
+		pq.swap(i, child)
+		i = child
+	}
+}
+
+// =============================================================================
+
+// edgeLeq orders the upper edges of two regions at the current sweep
+// event. Both edges must be directed from right to left (this is the
+// canonical direction for the upper edge of each region).
+//
+// The strategy is to evaluate a "t" value for each edge at the current
+// sweep line position, given by tess.event. If both edge destinations are
+// at the sweep event, the edges are sorted by slope.
+func (tess *Tesselator) edgeLeq(reg1, reg2 *activeRegion) bool {
+	event := tess.event
+	e1 := reg1.eUp
+	e2 := reg2.eUp
+
+	if e1.dst() == event {
+		if e2.dst() == event {
+			// Two edges right of the sweep line which meet at the sweep
+			// event. Sort them by slope.
+			if vertLeq(e1.org, e2.org) {
+				return edgeSign(e2.dst(), e1.org, e2.org) <= 0
+			}
+			return edgeSign(e1.dst(), e2.org, e1.org) >= 0
+		}
+		return edgeSign(e2.dst(), event, e2.org) <= 0
+	}
+	if e2.dst() == event {
+		return edgeSign(e1.dst(), event, e1.org) >= 0
+	}
+
+	// General case - compute signed distance *from* e1, e2 to event.
+	t1 := edgeEval(e1.dst(), event, e1.org)
+	t2 := edgeEval(e2.dst(), event, e2.org)
+	return t1 >= t2
+}
+
+func (tess *Tesselator) deleteRegion(reg *activeRegion) {
+	reg.eUp.activeRegion = nil
+	tess.dict.delete(reg.nodeUp)
+}
+
+// fixUpperEdge replaces an upper edge which needs fixing (see
+// connectRightVertex).
+func fixUpperEdge(reg *activeRegion, newEdge *halfEdge) {
+	meshDelete(reg.eUp)
+	reg.fixUpperEdge = false
+	reg.eUp = newEdge
+	newEdge.activeRegion = reg
+}
+
+func topLeftRegion(reg *activeRegion) *activeRegion {
+	org := reg.eUp.org
+
+	// Find the region above the uppermost edge with the same origin.
+	for {
+		reg = reg.above()
+		if reg.eUp.org != org {
+			break
+		}
+	}
+
+	// If the edge above was a temporary edge introduced by
+	// connectRightVertex, now is the time to fix it.
+	if reg.fixUpperEdge {
+		e := meshConnect(reg.below().eUp.sym, reg.eUp.lnext)
+		fixUpperEdge(reg, e)
+		reg = reg.above()
+	}
+	return reg
+}
+
+func topRightRegion(reg *activeRegion) *activeRegion {
+	dst := reg.eUp.dst()
+
+	// Find the region above the uppermost edge with the same destination.
+	for {
+		reg = reg.above()
+		if reg.eUp.dst() != dst {
+			return reg
+		}
+	}
+}
+
+// addRegionBelow adds a new active region to the sweep line, *somewhere*
+// below regAbove (according to where the new edge belongs in the
+// sweep-line dictionary). The upper edge of the new region will be
+// eNewUp. Winding number and "inside" flag are not updated.
+func (tess *Tesselator) addRegionBelow(regAbove *activeRegion, eNewUp *halfEdge) *activeRegion {
+	regNew := &activeRegion{eUp: eNewUp}
+	regNew.nodeUp = tess.dict.insertBefore(regAbove.nodeUp, regNew)
+	eNewUp.activeRegion = regNew
+	return regNew
+}
+
+func (tess *Tesselator) isWindingInside(n int) bool {
+	switch tess.windingRule {
+	case TESS_WINDING_ODD:
+		return n&1 != 0
+	case TESS_WINDING_NONZERO:
+		return n != 0
+	case TESS_WINDING_POSITIVE:
+		return n > 0
+	case TESS_WINDING_NEGATIVE:
+		return n < 0
+	case TESS_WINDING_ABS_GEQ_TWO:
+		return n >= 2 || n <= -2
+	}
+	panic("glu: invalid winding rule")
+}
+
+func (tess *Tesselator) computeWinding(reg *activeRegion) {
+	reg.windingNumber = reg.above().windingNumber + reg.eUp.winding
+	reg.inside = tess.isWindingInside(reg.windingNumber)
+}
+
+// finishRegion deletes a region from the sweep line. This happens when the
+// upper and lower chains of a region meet (at a vertex on the sweep line).
+// The "inside" flag is copied to the appropriate mesh face (we could not do
+// this before -- since the structure of the mesh is always changing, this
+// face may not have even existed until now).
+func (tess *Tesselator) finishRegion(reg *activeRegion) {
+	e := reg.eUp
+	f := e.lface
+
+	f.inside = reg.inside
+	f.anEdge = e // optimization for tessellateMonoRegion
+	tess.deleteRegion(reg)
+}
+
+// finishLeftRegions is given a vertex with one or more left-going edges.
+// All affected edges should be in the edge dictionary. Starting at
+// regFirst.eUp, we walk down deleting all regions where both edges have
+// the same origin vOrg. At the same time we copy the "inside" flag from
+// the active region to the face, since at this point each face will belong
+// to at most one region. The walk stops at the region above regLast; if
+// regLast is nil we walk as far as possible. At the same time we relink the
+// mesh if necessary, so that the ordering of edges around vOrg is the same
+// as in the dictionary.
+func (tess *Tesselator) finishLeftRegions(regFirst, regLast *activeRegion) *halfEdge {
+	regPrev := regFirst
+	ePrev := regFirst.eUp
+	for regPrev != regLast {
+		regPrev.fixUpperEdge = false // placement was OK
+		reg := regPrev.below()
+		e := reg.eUp
+		if e.org != ePrev.org {
+			if !reg.fixUpperEdge {
+				// Remove the last left-going edge. Even though there are
+				// no further edges in the dictionary with this origin,
+				// there may be further such edges in the mesh (if we are
+				// adding left edges to a vertex that has already been
+				// processed). Thus it is important to call finishRegion
+				// rather than just deleteRegion.
+				tess.finishRegion(regPrev)
+				break
+			}
+			// If the edge below was a temporary edge introduced by
+			// connectRightVertex, now is the time to fix it.
+			e = meshConnect(ePrev.lprev(), e.sym)
+			fixUpperEdge(reg, e)
+		}
+
+		// Relink edges so that ePrev.onext == e.
+		if ePrev.onext != e {
+			meshSplice(e.oprev(), e)
+			meshSplice(ePrev, e)
+		}
+		tess.finishRegion(regPrev) // may change reg.eUp
+		ePrev = reg.eUp
+		regPrev = reg
+	}
+	return ePrev
+}
+
+// addRightEdges inserts right-going edges into the edge dictionary, and
+// updates winding numbers and mesh connectivity appropriately. All
+// right-going edges share a common origin vOrg. Edges are inserted CCW
+// starting at eFirst; the last edge inserted is eLast.oprev. If vOrg has
+// any left-going edges already processed, then eTopLeft must be the edge
+// such that an imaginary upward vertical segment from vOrg would be
+// contained between eTopLeft.oprev and eTopLeft; otherwise eTopLeft should
+// be nil.
+func (tess *Tesselator) addRightEdges(regUp *activeRegion, eFirst, eLast, eTopLeft *halfEdge, cleanUp bool) {
+	firstTime := true
+
+	// Insert the new right-going edges in the dictionary.
+	e := eFirst
+	for {
+		tess.addRegionBelow(regUp, e.sym)
+		e = e.onext
+		if e == eLast {
+			break
+		}
+	}
+
+	// Walk *all* right-going edges from e.org, in the dictionary order,
+	// updating the winding numbers of each region, and re-linking the mesh
+	// edges to match the dictionary ordering (if necessary).
+	if eTopLeft == nil {
+		eTopLeft = regUp.below().eUp.rprev()
+	}
+	regPrev := regUp
+	ePrev := eTopLeft
+	var reg *activeRegion
+	for {
+		reg = regPrev.below()
+		e = reg.eUp.sym
+		if e.org != ePrev.org {
+			break
+		}
+
+		if e.onext != ePrev {
+			// Unlink e from its current position, and relink below
+			// ePrev.
+			meshSplice(e.oprev(), e)
+			meshSplice(ePrev.oprev(), e)
+		}
+		// Compute the winding number and "inside" flag for the new
+		// regions.
+		reg.windingNumber = regPrev.windingNumber - e.winding
+		reg.inside = tess.isWindingInside(reg.windingNumber)
+
+		// Check for two outgoing edges with same slope -- process these
+		// before any intersection tests (see example in computeInterior).
+		regPrev.dirty = true
+		if !firstTime && tess.checkForRightSplice(regPrev) {
+			addWinding(e, ePrev)
+			tess.deleteRegion(regPrev)
+			meshDelete(ePrev)
+		}
+		firstTime = false
+		regPrev = reg
+		ePrev = e
+	}
+	regPrev.dirty = true
+
+	if cleanUp {
+		// Check for intersections between newly adjacent edges.
+		tess.walkDirtyRegions(regPrev)
+	}
+}
+
+// callCombine asks the client for the data of the vertex isect, which was
+// created by merging or intersecting the vertices in data.
+func (tess *Tesselator) callCombine(isect *tessVertex, data [4]interface{}, weights [4]float32, needed bool) {
+	// Mirror the cgo backend, which hands the first vertex to the
+	// callback in place of missing ones.
+	for i := range data {
+		if data[i] == nil {
+			data[i] = data[0]
+		}
+	}
+
+	if tess.combineData == nil {
+		if out, ok := interpolateVertexData(isect.coords, data, weights); ok {
+			isect.data = out
+		} else if !needed {
+			isect.data = data[0]
+		} else if !tess.fatalError {
+			// The only way fatal error is when two edges are found to
+			// intersect, but the user has not provided the callback
+			// necessary to handle generated intersection points.
+			tess.callError(TESS_NEED_COMBINE_CALLBACK)
+			tess.fatalError = true
+		}
+		return
+	}
+	isect.data = tess.combineData(isect.coords, data, weights, tess.polyData)
+}
+
+// spliceMergeVertices combines two vertices with identical coordinates
+// into one. e1.org is kept, while e2.org is discarded.
+func (tess *Tesselator) spliceMergeVertices(e1, e2 *halfEdge) {
+	data := [4]interface{}{e1.org.data, e2.org.data}
+	weights := [4]float32{0.5, 0.5, 0, 0}
+
+	tess.callCombine(e1.org, data, weights, false)
+	meshSplice(e1, e2)
+}
+
+// vertexWeights finds some weights which describe how the intersection
+// vertex is a linear combination of org and dst. Each of the two edges
+// which generated isect is allocated 50% of the weight; each edge splits
+// the weight between its org and dst according to the relative distance
+// to isect.
+func vertexWeights(isect, org, dst *tessVertex, weights []float32) {
+	t1 := vertL1dist(org, isect)
+	t2 := vertL1dist(dst, isect)
+
+	w0 := 0.5 * t2 / (t1 + t2)
+	w1 := 0.5 * t1 / (t1 + t2)
+	weights[0] = float32(w0)
+	weights[1] = float32(w1)
+	for i := 0; i < 3; i++ {
+		isect.coords[i] += w0*org.coords[i] + w1*dst.coords[i]
+	}
+}
+
+// getIntersectData asks the client for a data value for the intersection
+// isect, which we need in order to refer to the new vertex in the rendering
+// callbacks.
+func (tess *Tesselator) getIntersectData(isect, orgUp, dstUp, orgLo, dstLo *tessVertex) {
+	data := [4]interface{}{orgUp.data, dstUp.data, orgLo.data, dstLo.data}
+	var weights [4]float32
+
+	isect.coords = [3]float64{}
+	vertexWeights(isect, orgUp, dstUp, weights[0:2])
+	vertexWeights(isect, orgLo, dstLo, weights[2:4])
+
+	tess.callCombine(isect, data, weights, true)
+}
+
+// checkForRightSplice checks the upper and lower edge of regUp, to make
+// sure that the eUp.org is above eLo, or eLo.org is below eUp (depending on
+// which origin is leftmost).
+//
+// The main purpose is to splice right-going edges with the same dest vertex
+// and nearly identical slopes (ie. we can't distinguish the slopes
+// numerically). However the splicing can also help us to recover from
+// numerical errors. This is a guaranteed solution, no matter how
+// degenerate things get. Basically this is a combinatorial solution to a
+// numerical problem.
+func (tess *Tesselator) checkForRightSplice(regUp *activeRegion) bool {
+	regLo := regUp.below()
+	eUp := regUp.eUp
+	eLo := regLo.eUp
+
+	if vertLeq(eUp.org, eLo.org) {
+		if edgeSign(eLo.dst(), eUp.org, eLo.org) > 0 {
+			return false
+		}
+
+		// eUp.org appears to be below eLo.
+		if !vertEq(eUp.org, eLo.org) {
+			// Splice eUp.org into eLo.
+			meshSplitEdge(eLo.sym)
+			meshSplice(eUp, eLo.oprev())
+			regUp.dirty = true
+			regLo.dirty = true
+		} else if eUp.org != eLo.org {
+			// Merge the two vertices, discarding eUp.org.
+			tess.pq.delete(eUp.org)
+			tess.spliceMergeVertices(eLo.oprev(), eUp)
+		}
+	} else {
+		if edgeSign(eUp.dst(), eLo.org, eUp.org) < 0 {
+			return false
+		}
+
+		// eLo.org appears to be above eUp, so splice eLo.org into eUp.
+		regUp.above().dirty = true
+		regUp.dirty = true
+		meshSplitEdge(eUp.sym)
+		meshSplice(eLo.oprev(), eUp)
+	}
+	return true
+}
+
+// checkForLeftSplice checks the upper and lower edge of regUp, to make sure
+// that the eUp.dst is above eLo, or eLo.dst is below eUp (depending on
+// which destination is rightmost).
+//
+// Theoretically, this should always be true. However, splitting an edge
+// into two pieces can change the results of previous tests. We fix the
+// problem by just splicing the offending vertex into the other edge.
+func (tess *Tesselator) checkForLeftSplice(regUp *activeRegion) bool {
+	regLo := regUp.below()
+	eUp := regUp.eUp
+	eLo := regLo.eUp
+
+	if vertLeq(eUp.dst(), eLo.dst()) {
+		if edgeSign(eUp.dst(), eLo.dst(), eUp.org) < 0 {
+			return false
+		}
+
+		// eLo.dst is above eUp, so splice eLo.dst into eUp.
+		regUp.above().dirty = true
+		regUp.dirty = true
+		e := meshSplitEdge(eUp)
+		meshSplice(eLo.sym, e)
+		e.lface.inside = regUp.inside
+	} else {
+		if edgeSign(eLo.dst(), eUp.dst(), eLo.org) > 0 {
+			return false
+		}
+
+		// eUp.dst is below eLo, so splice eUp.dst into eLo.
+		regUp.dirty = true
+		regLo.dirty = true
+		e := meshSplitEdge(eLo)
+		meshSplice(eUp.lnext, eLo.sym)
+		e.rface().inside = regUp.inside
+	}
+	return true
+}
+
+// checkForIntersect checks the upper and lower edges of the given region to
+// see if they intersect. If so, create the intersection and add it to the
+// data structures.
+//
+// Returns true if adding the new intersection resulted in a recursive call
+// to addRightEdges(); in this case all "dirty" regions have been checked
+// for intersections, and possibly regUp has been deleted.
+func (tess *Tesselator) checkForIntersect(regUp *activeRegion) bool {
+	regLo := regUp.below()
+	eUp := regUp.eUp
+	eLo := regLo.eUp
+	orgUp := eUp.org
+	orgLo := eLo.org
+	dstUp := eUp.dst()
+	dstLo := eLo.dst()
+
+	if orgUp == orgLo {
+		return false // right endpoints are the same
+	}
+
+	tMinUp := math.Min(orgUp.t, dstUp.t)
+	tMaxLo := math.Max(orgLo.t, dstLo.t)
+	if tMinUp > tMaxLo {
+		return false // t ranges do not overlap
+	}
+
+	if vertLeq(orgUp, orgLo) {
+		if edgeSign(dstLo, orgUp, orgLo) > 0 {
+			return false
+		}
+	} else {
+		if edgeSign(dstUp, orgLo, orgUp) < 0 {
+			return false
+		}
+	}
+
+	// At this point the edges intersect, at least marginally.
+	var isect tessVertex
+	edgeIntersect(dstUp, orgUp, dstLo, orgLo, &isect)
+
+	if vertLeq(&isect, tess.event) {
+		// The intersection point lies slightly to the left of the sweep
+		// line, so move it until it's slightly to the right of the sweep
+		// line. (If we had perfect numerical precision, this would never
+		// happen in the first place). The easiest and safest thing to do
+		// is replace the intersection by tess.event.
+		isect.s = tess.event.s
+		isect.t = tess.event.t
+	}
+	// Similarly, if the computed intersection lies to the right of the
+	// rightmost origin (which should rarely happen), it can cause
+	// unbelievable inefficiency on sufficiently degenerate inputs.
+	orgMin := orgLo
+	if vertLeq(orgUp, orgLo) {
+		orgMin = orgUp
+	}
+	if vertLeq(orgMin, &isect) {
+		isect.s = orgMin.s
+		isect.t = orgMin.t
+	}
+
+	if vertEq(&isect, orgUp) || vertEq(&isect, orgLo) {
+		// Easy case -- intersection at one of the right endpoints.
+		tess.checkForRightSplice(regUp)
+		return false
+	}
+
+	if (!vertEq(dstUp, tess.event) && edgeSign(dstUp, tess.event, &isect) >= 0) ||
+		(!vertEq(dstLo, tess.event) && edgeSign(dstLo, tess.event, &isect) <= 0) {
+		// Very unusual -- the new upper or lower edge would pass on the
+		// wrong side of the sweep event, or through it. This can happen
+		// due to very small numerical errors in the intersection
+		// calculation.
+		if dstLo == tess.event {
+			// Splice dstLo into eUp, and process the new region(s).
+			meshSplitEdge(eUp.sym)
+			meshSplice(eLo.sym, eUp)
+			regUp = topLeftRegion(regUp)
+			eUp = regUp.below().eUp
+			tess.finishLeftRegions(regUp.below(), regLo)
+			tess.addRightEdges(regUp, eUp.oprev(), eUp, eUp, true)
+			return true
+		}
+		if dstUp == tess.event {
+			// Splice dstUp into eLo, and process the new region(s).
+			meshSplitEdge(eLo.sym)
+			meshSplice(eUp.lnext, eLo.oprev())
+			regLo = regUp
+			regUp = topRightRegion(regUp)
+			e := regUp.below().eUp.rprev()
+			regLo.eUp = eLo.oprev()
+			eLo = tess.finishLeftRegions(regLo, nil)
+			tess.addRightEdges(regUp, eLo.onext, eUp.rprev(), e, true)
+			return true
+		}
+		// Special case: called from connectRightVertex. If either edge
+		// passes on the wrong side of tess.event, split it (and wait for
+		// connectRightVertex to splice it appropriately).
+		if edgeSign(dstUp, tess.event, &isect) >= 0 {
+			regUp.above().dirty = true
+			regUp.dirty = true
+			meshSplitEdge(eUp.sym)
+			eUp.org.s = tess.event.s
+			eUp.org.t = tess.event.t
+		}
+		if edgeSign(dstLo, tess.event, &isect) <= 0 {
+			regUp.dirty = true
+			regLo.dirty = true
+			meshSplitEdge(eLo.sym)
+			eLo.org.s = tess.event.s
+			eLo.org.t = tess.event.t
+		}
+		// leave the rest for connectRightVertex
+		return false
+	}
+
+	// General case -- split both edges, splice into new vertex. When we do
+	// the splice operation, the order of the arguments is arbitrary as far
+	// as correctness goes. However, when the operation creates a new face,
+	// the work done is proportional to the size of the new face. We expect
+	// the faces in the processed part of the mesh (ie. eUp.lface) to be
+	// smaller than the faces in the unprocessed original contours (which
+	// will be eLo.oprev.lface).
+	meshSplitEdge(eUp.sym)
+	meshSplitEdge(eLo.sym)
+	meshSplice(eLo.oprev(), eUp)
+	eUp.org.s = isect.s
+	eUp.org.t = isect.t
+	tess.pq.insert(eUp.org)
+	tess.getIntersectData(eUp.org, orgUp, dstUp, orgLo, dstLo)
+	regUp.above().dirty = true
+	regUp.dirty = true
+	regLo.dirty = true
+	return false
+}
+
+// walkDirtyRegions walks through all the dirty regions and makes sure that
+// the dictionary invariants are satisfied (see the comments at the
+// beginning of this file). Of course new dirty regions can be created as
+// we make changes to restore the invariants.
+func (tess *Tesselator) walkDirtyRegions(regUp *activeRegion) {
+	regLo := regUp.below()
+
+	for {
+		// Find the lowest dirty region (we walk from the bottom up).
+		for regLo.dirty {
+			regUp = regLo
+			regLo = regLo.below()
+		}
+		if !regUp.dirty {
+			regLo = regUp
+			regUp = regUp.above()
+			if regUp == nil || !regUp.dirty {
+				// We've walked all the dirty regions.
+				return
+			}
+		}
+		regUp.dirty = false
+		eUp := regUp.eUp
+		eLo := regLo.eUp
+
+		if eUp.dst() != eLo.dst() {
+			// Check that the edge ordering is obeyed at the dst vertices.
+			if tess.checkForLeftSplice(regUp) {
+				// If the upper or lower edge was marked fixUpperEdge, then
+				// we no longer need it (since these edges are needed only
+				// for vertices which otherwise have no right-going edges).
+				if regLo.fixUpperEdge {
+					tess.deleteRegion(regLo)
+					meshDelete(eLo)
+					regLo = regUp.below()
+					eLo = regLo.eUp
+				} else if regUp.fixUpperEdge {
+					tess.deleteRegion(regUp)
+					meshDelete(eUp)
+					regUp = regLo.above()
+					eUp = regUp.eUp
+				}
+			}
+		}
+		if eUp.org != eLo.org {
+			if eUp.dst() != eLo.dst() &&
+				!regUp.fixUpperEdge && !regLo.fixUpperEdge &&
+				(eUp.dst() == tess.event || eLo.dst() == tess.event) {
+				// When all else fails in checkForIntersect(), it uses
+				// tess.event as the intersection location. To make this
+				// possible, it requires that tess.event lie between the
+				// upper and lower edges, and also that neither of these
+				// is marked fixUpperEdge (since in the worst case it might
+				// splice one of these edges into tess.event, and violate
+				// the invariant that fixable edges are the only
+				// right-going edge from their associated vertex).
+				if tess.checkForIntersect(regUp) {
+					// walkDirtyRegions() was called recursively; we're
+					// done.
+					return
+				}
+			} else {
+				// Even though we can't use checkForIntersect(), the org
+				// vertices may violate the dictionary edge ordering.
+				// Check and correct this.
+				tess.checkForRightSplice(regUp)
+			}
+		}
+		if eUp.org == eLo.org && eUp.dst() == eLo.dst() {
+			// A degenerate loop consisting of only two edges -- delete
+			// it.
+			addWinding(eLo, eUp)
+			tess.deleteRegion(regUp)
+			meshDelete(eUp)
+			regUp = regLo.above()
+		}
+	}
+}
+
+// connectRightVertex connects a "right" vertex vEvent (one where all edges
+// go left) to the unprocessed portion of the mesh. Since there are no
+// right-going edges, two regions (one above vEvent and one below) are being
+// merged into one. regUp is the upper of these two regions.
+//
+// There are two reasons for doing this (adding a right-going edge):
+//   - if the two regions being merged are "inside", we must add an edge
+//     to keep them separated (the combined region would not be monotone).
+//   - in any case, we must leave some record of vEvent in the dictionary,
+//     so that we can merge vEvent with features that we have not seen
+//     yet. For example, maybe there is a vertical edge which passes just
+//     to the right of vEvent; we would like to splice vEvent into this
+//     edge.
+//
+// Our eventual goal is to connect vEvent to the leftmost unprocessed
+// vertex of the combined region (the union of regUp and regLo). In the
+// meantime, we connect vEvent to the closest vertex of either chain, and
+// mark the region as "fixUpperEdge". This flag says to delete and
+// reconnect this edge to the next processed vertex on the boundary of the
+// combined region.
+func (tess *Tesselator) connectRightVertex(regUp *activeRegion, eBottomLeft *halfEdge) {
+	eTopLeft := eBottomLeft.onext
+	regLo := regUp.below()
+	eUp := regUp.eUp
+	eLo := regLo.eUp
+	degenerate := false
+
+	if eUp.dst() != eLo.dst() {
+		tess.checkForIntersect(regUp)
+	}
+
+	// Possible new degeneracies: upper or lower edge of regUp may pass
+	// through vEvent, or may coincide with new intersection vertex.
+	if vertEq(eUp.org, tess.event) {
+		meshSplice(eTopLeft.oprev(), eUp)
+		regUp = topLeftRegion(regUp)
+		eTopLeft = regUp.below().eUp
+		tess.finishLeftRegions(regUp.below(), regLo)
+		degenerate = true
+	}
+	if vertEq(eLo.org, tess.event) {
+		meshSplice(eBottomLeft, eLo.oprev())
+		eBottomLeft = tess.finishLeftRegions(regLo, nil)
+		degenerate = true
+	}
+	if degenerate {
+		tess.addRightEdges(regUp, eBottomLeft.onext, eTopLeft, eTopLeft, true)
+		return
+	}
+
+	// Non-degenerate situation -- need to add a temporary, fixable edge.
+	// Connect to the closer of eLo.org, eUp.org.
+	var eNew *halfEdge
+	if vertLeq(eLo.org, eUp.org) {
+		eNew = eLo.oprev()
+	} else {
+		eNew = eUp
+	}
+	eNew = meshConnect(eBottomLeft.lprev(), eNew)
+
+	// Prevent cleanup, otherwise eNew might disappear before we've even had
+	// a chance to mark it as a temporary edge.
+	tess.addRightEdges(regUp, eNew, eNew.onext, eNew.onext, false)
+	eNew.sym.activeRegion.fixUpperEdge = true
+	tess.walkDirtyRegions(regUp)
+}
+
+// connectLeftDegenerate handles an event vertex that lies exactly on an
+// already-processed edge or vertex. Adding the new vertex involves splicing
+// it into the already-processed part of the mesh.
+func (tess *Tesselator) connectLeftDegenerate(regUp *activeRegion, vEvent *tessVertex) {
+	e := regUp.eUp
+	if vertEq(e.org, vEvent) {
+		// e.org is an unprocessed vertex - just combine them, and wait
+		// for e.org to be pulled from the queue.
+		tess.spliceMergeVertices(e, vEvent.anEdge)
+		return
+	}
+
+	if !vertEq(e.dst(), vEvent) {
+		// General case -- splice vEvent into edge e which passes through
+		// it.
+		meshSplitEdge(e.sym)
+		if regUp.fixUpperEdge {
+			// This edge was fixable -- delete unused portion of original
+			// edge.
+			meshDelete(e.onext)
+			regUp.fixUpperEdge = false
+		}
+		meshSplice(vEvent.anEdge, e)
+		tess.sweepEvent(vEvent) // recurse
+		return
+	}
+
+	// vEvent coincides with e.dst, which has already been processed.
+	// Splice in the additional right-going edges.
+	regUp = topRightRegion(regUp)
+	reg := regUp.below()
+	eTopRight := reg.eUp.sym
+	eTopLeft := eTopRight.onext
+	eLast := eTopLeft
+	if reg.fixUpperEdge {
+		// Here e.dst has only a single fixable edge going right. We can
+		// delete it since now we have some real right-going edges.
+		tess.deleteRegion(reg)
+		meshDelete(eTopRight)
+		eTopRight = eTopLeft.oprev()
+	}
+	meshSplice(vEvent.anEdge, eTopRight)
+	if !eTopLeft.goesLeft() {
+		// e.dst had no left-going edges -- indicate this to
+		// addRightEdges().
+		eTopLeft = nil
+	}
+	tess.addRightEdges(regUp, eTopRight.onext, eLast, eTopLeft, true)
+}
+
+// connectLeftVertex connects a "left" vertex (one where both edges go
+// right) to the processed portion of the mesh. Let R be the active region
+// containing vEvent, and let U and L be the upper and lower edge chains of
+// R. There are two possibilities:
+//
+//   - the normal case: split R into two regions, by connecting vEvent to
+//     the rightmost vertex of U or L lying to the left of the sweep line
+//   - the degenerate case: if vEvent is close enough to U or L, we merge
+//     vEvent into that edge chain.
+func (tess *Tesselator) connectLeftVertex(vEvent *tessVertex) {
+	// Get a pointer to the active region containing vEvent.
+	tmp := activeRegion{eUp: vEvent.anEdge.sym}
+	regUp := tess.dict.search(&tmp).key
+	regLo := regUp.below()
+	eUp := regUp.eUp
+	eLo := regLo.eUp
+
+	// Try merging with U or L first.
+	if edgeSign(eUp.dst(), vEvent, eUp.org) == 0 {
+		tess.connectLeftDegenerate(regUp, vEvent)
+		return
+	}
+
+	// Connect to the rightmost vertex of U or L lying to the left of the
+	// sweep line.
+	reg := regLo
+	if vertLeq(eLo.dst(), eUp.dst()) {
+		reg = regUp
+	}
+
+	if regUp.inside || reg.fixUpperEdge {
+		var eNew *halfEdge
+		if reg == regUp {
+			eNew = meshConnect(vEvent.anEdge.sym, eUp.lnext)
+		} else {
+			eNew = meshConnect(eLo.dnext(), vEvent.anEdge).sym
+		}
+		if reg.fixUpperEdge {
+			fixUpperEdge(reg, eNew)
+		} else {
+			tess.computeWinding(tess.addRegionBelow(regUp, eNew))
+		}
+		tess.sweepEvent(vEvent)
+	} else {
+		// The new vertex is in a region which does not belong to the
+		// polygon. We don't need to connect this vertex to the rest of
+		// the mesh.
+		tess.addRightEdges(regUp, vEvent.anEdge, vEvent.anEdge, nil, true)
+	}
+}
+
+// sweepEvent does everything necessary when the sweep line crosses a
+// vertex. Updates the mesh and the edge dictionary.
+func (tess *Tesselator) sweepEvent(vEvent *tessVertex) {
+	tess.event = vEvent
+
+	// Check if this vertex is the right endpoint of an edge that is
+	// already in the dictionary. In this case we don't need to waste time
+	// searching for the location to insert new edges.
+	e := vEvent.anEdge
+	for e.activeRegion == nil {
+		e = e.onext
+		if e == vEvent.anEdge {
+			// All edges go right -- not incident to any processed edges.
+			tess.connectLeftVertex(vEvent)
+			return
+		}
+	}
+
+	// Processing consists of two phases: first we "finish" all the active
+	// regions where both the upper and lower edges terminate at vEvent
+	// (ie. vEvent is closing off these regions). We mark these faces
+	// "inside" or "outside" the polygon according to their winding
+	// number, and delete the edges from the dictionary. This takes care of
+	// all the left-going edges from vEvent.
+	regUp := topLeftRegion(e.activeRegion)
+	reg := regUp.below()
+	eTopLeft := reg.eUp
+	eBottomLeft := tess.finishLeftRegions(reg, nil)
+
+	// Next we process all the right-going edges from vEvent. This involves
+	// adding the edges to the dictionary, and creating the associated
+	// "active regions" which record information about the regions between
+	// adjacent dictionary edges.
+	if eBottomLeft.onext == eTopLeft {
+		// No right-going edges -- add a temporary "fixable" edge.
+		tess.connectRightVertex(regUp, eBottomLeft)
+	} else {
+		tess.addRightEdges(regUp, eBottomLeft.onext, eTopLeft, eTopLeft, true)
+	}
+}
+
+// addSentinel adds two sentinel edges above and below all other edges, to
+// avoid special cases at the top and bottom.
+func (tess *Tesselator) addSentinel(t float64) {
+	e := tess.mesh.makeEdge()
+	e.org.s = sentinelCoord
+	e.org.t = t
+	e.dst().s = -sentinelCoord
+	e.dst().t = t
+	tess.event = e.dst() // initialize it
+
+	reg := &activeRegion{eUp: e, sentinel: true}
+	reg.nodeUp = tess.dict.insert(reg)
+}
+
+// initEdgeDict creates the dictionary in which we maintain an ordering of
+// edge intersections with the sweep line.
+func (tess *Tesselator) initEdgeDict() {
+	tess.dict = newEdgeDict(tess)
+
+	tess.addSentinel(-sentinelCoord)
+	tess.addSentinel(sentinelCoord)
+}
+
+func (tess *Tesselator) doneEdgeDict() {
+	// At the end of all processing, the dictionary should contain only the
+	// two sentinel edges, plus at most one "fixable" edge created by
+	// connectRightVertex().
+	for {
+		reg := tess.dict.min().key
+		if reg == nil {
+			break
+		}
+		tess.deleteRegion(reg)
+	}
+	tess.dict = nil
+}
+
+// removeDegenerateEdges removes zero-length edges, and contours with fewer
+// than 3 vertices.
+func (tess *Tesselator) removeDegenerateEdges() {
+	eHead := &tess.mesh.eHead
+
+	var eNext *halfEdge
+	for e := eHead.next; e != eHead; e = eNext {
+		eNext = e.next
+		eLnext := e.lnext
+
+		if vertEq(e.org, e.dst()) && e.lnext.lnext != e {
+			// Zero-length edge, contour has at least 3 edges.
+			tess.spliceMergeVertices(eLnext, e) // deletes e.org
+			meshDelete(e)                       // e is a self-loop
+			e = eLnext
+			eLnext = e.lnext
+		}
+		if eLnext.lnext == e {
+			// Degenerate contour (one or two edges).
+			if eLnext != e {
+				if eLnext == eNext || eLnext == eNext.sym {
+					eNext = eNext.next
+				}
+				meshDelete(eLnext)
+			}
+			if e == eNext || e == eNext.sym {
+				eNext = eNext.next
+			}
+			meshDelete(e)
+		}
+	}
+}
+
+// initPriorityQ inserts all vertices into the priority queue which
+// determines the order in which vertices cross the sweep line.
+func (tess *Tesselator) initPriorityQ() {
+	tess.pq = new(priorityQ)
+
+	vHead := &tess.mesh.vHead
+	for v := vHead.next; v != vHead; v = v.next {
+		tess.pq.insert(v)
+	}
+}
+
+// removeDegenerateFaces deletes any degenerate faces with only two edges.
+// walkDirtyRegions() will catch almost all of these, but it won't catch
+// degenerate faces produced by splice operations on already-processed
+// edges.
+func (tess *Tesselator) removeDegenerateFaces() {
+	fHead := &tess.mesh.fHead
+
+	var fNext *tessFace
+	for f := fHead.next; f != fHead; f = fNext {
+		fNext = f.next
+		e := f.anEdge
+
+		if e.lnext.lnext == e {
+			// A face with only two edges.
+			addWinding(e.onext, e)
+			meshDelete(e)
+		}
+	}
+}
+
+// computeInterior computes the planar arrangement specified by the given
+// contours, and further subdivides this arrangement into regions. Each
+// region is marked "inside" if it belongs to the polygon, according to the
+// rule given by tess.windingRule. Each interior region is guaranteed to be
+// monotone.
+func (tess *Tesselator) computeInterior() {
+	tess.fatalError = false
+
+	// Each vertex defines an event for our sweep line. Start by inserting
+	// all the vertices in a priority queue. Events are processed in
+	// lexicographic order, ie.
+	//
+	//	e1 < e2  iff  e1.x < e2.x || (e1.x == e2.x && e1.y < e2.y)
+	tess.removeDegenerateEdges()
+	tess.initPriorityQ()
+	tess.initEdgeDict()
+
+	for {
+		v := tess.pq.extractMin()
+		if v == nil {
+			break
+		}
+		for {
+			vNext := tess.pq.minimum()
+			if vNext == nil || !vertEq(vNext, v) {
+				break
+			}
+
+			// Merge together all vertices at exactly the same location.
+			// This is more efficient than processing them one at a time,
+			// simplifies the code (see connectLeftDegenerate), and is
+			// also important for correct handling of certain degenerate
+			// cases.
+			vNext = tess.pq.extractMin()
+			tess.spliceMergeVertices(v.anEdge, vNext.anEdge)
+		}
+		tess.sweepEvent(v)
+	}
+
+	tess.doneEdgeDict()
+	tess.pq = nil
+
+	tess.removeDegenerateFaces()
+}
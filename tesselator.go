@@ -11,9 +11,10 @@ package glu
 //   #include <GL/glu.h>
 // #endif
 // #include <stdlib.h>
+// #include "callback.h"
 import "C"
 import (
-	"unsafe"
+	"runtime/cgo"
 )
 
 // Opaque object used for book keeping on the go side.
@@ -22,15 +23,23 @@ type Tesselator struct {
 
 	polyData interface{}
 
-	cData []unsafe.Pointer
+	// handle identifies the tesselator to callbacks, as GLU's polygon data.
+	handle cgo.Handle
 
-	// vertData keeps references to the vertices specifed by TessVertex
-	// so that the garbage collector does not invalidate them.
-	vertData []*vertexDataWrapper
+	// buffer records callbacks for NewBufferedTess, and is GLU's polygon
+	// data in its place.
+	buffer *C.gluEventBuffer
+
+	// vertData holds the handles of the vertex data specified by
+	// TessVertex or returned by the combine callback, which GLU hands back
+	// as vertex data. They are released by EndPolygon.
+	vertData []cgo.Handle
 
 	// vertLocs stores a copy of the vertices' locations as specified
-	// to TessVertex. Again, so the garbage collector doesn't get them.
-	vertLocs [][3]float64
+	// to TessVertex, at addresses that stay valid until EndPolygon.
+	vertLocs vertexArena
+
+	errs tessErrors
 
 	beginData    TessBeginHandler
 	vertexData   TessVertexHandler
@@ -40,11 +49,6 @@ type Tesselator struct {
 	combineData  TessCombineHandler
 }
 
-// Wrapper around an interface. Does go not support (*interface{})(ptr)?
-type vertexDataWrapper struct {
-	data interface{}
-}
-
 // Create a new tesselator.
 func NewTess() (tess *Tesselator) {
 	tess = new(Tesselator)
@@ -53,6 +57,14 @@ func NewTess() (tess *Tesselator) {
 	if tess.tess == nil {
 		panic("Out of memory.")
 	}
+	tess.handle = cgo.NewHandle(tess)
+
+	// Errors are always recorded so they can be returned, whether or not
+	// a TessErrorHandler is set. Combining always goes through Go so that
+	// Interpolatable vertex data can be blended without a handler.
+	tess.SetErrorCallback(nil)
+	tess.SetCombineCallback(nil)
+	tess.errs.reset()
 
 	return
 }
@@ -61,58 +73,102 @@ func NewTess() (tess *Tesselator) {
 // do this automatically.
 func (tess *Tesselator) Delete() {
 	C.gluDeleteTess(tess.tess)
-	for _, ptr := range tess.cData {
-		C.free(ptr)
+	tess.freeVertexHandles()
+	tess.vertLocs.free()
+	if tess.buffer != nil {
+		C.freeGluEventBuffer(tess.buffer)
 	}
+	tess.handle.Delete()
 	tess.tess = nil
 }
 
 // Begin the drawing of the polygon, with the data parameter that will
 // be provided to callbacks.
 func (tess *Tesselator) BeginPolygon(data interface{}) {
+	tess.BeginPolygonChecked(data)
+}
+
+// BeginPolygonChecked is like BeginPolygon, but returns the error GLU
+// reported, as a *TessError.
+func (tess *Tesselator) BeginPolygonChecked(data interface{}) error {
 	tess.polyData = data
-	C.gluTessBeginPolygon(tess.tess, unsafe.Pointer(tess))
+	tess.errs.reset()
+	if tess.buffer != nil {
+		C.beginGluTessPolygonBuffered(tess.tess, tess.buffer)
+	} else {
+		C.beginGluTessPolygon(tess.tess, C.uintptr_t(tess.handle))
+	}
+
+	// GLU has discarded any polygon that was not ended.
+	tess.freeVertexHandles()
+	tess.vertLocs.reset()
+	return tess.errs.take()
 }
 
-// End the drawing of the polygon.
-func (tess *Tesselator) EndPolygon() {
-	C.gluTessEndPolygon(tess.tess)
+// End the drawing of the polygon. The returned error is the first
+// *TessError reported since BeginPolygon, if any, including those the
+// other methods returned or would have returned from their checked
+// variants.
+func (tess *Tesselator) EndPolygon() error {
+	tess.errs.contour = -1
+	C.endGluTessPolygon(tess.tess, C.uintptr_t(tess.handle))
+	if tess.buffer != nil {
+		tess.replayEvents()
+	}
 
 	// Free memory that we were safeguarding on the go side.
-	tess.vertData = []*vertexDataWrapper{}
-	tess.vertLocs = [][3]float64{}
+	tess.freeVertexHandles()
+	tess.vertLocs.reset()
+
+	err := tess.errs.first
+	tess.errs.reset()
+	tess.errs.take()
+	return err
 }
 
 // Begin a contour within the polygon.
 func (tess *Tesselator) BeginContour() {
-	C.gluTessBeginContour(tess.tess)
+	tess.BeginContourChecked()
+}
+
+// BeginContourChecked is like BeginContour, but returns the error GLU
+// reported, as a *TessError.
+func (tess *Tesselator) BeginContourChecked() error {
+	tess.errs.beginContour()
+	C.beginGluTessContour(tess.tess, C.uintptr_t(tess.handle))
+	return tess.errs.take()
 }
 
 // End a contour within the polygon.
 func (tess *Tesselator) EndContour() {
-	C.gluTessEndContour(tess.tess)
+	tess.EndContourChecked()
+}
+
+// EndContourChecked is like EndContour, but returns the error GLU
+// reported, as a *TessError.
+func (tess *Tesselator) EndContourChecked() error {
+	C.endGluTessContour(tess.tess, C.uintptr_t(tess.handle))
+	return tess.errs.take()
 }
 
 // Add a vertex to the polygon, with the data parameter that will be
 // provided to callbacks.
 func (tess *Tesselator) Vertex(location [3]float64, data interface{}) {
-	// Wrap and safeguard data pointer.
-	_data := &vertexDataWrapper{data}
-	tess.vertData = append(tess.vertData, _data)
-
-	// Copy location to a safe memory location.
-	tess.vertLocs = append(tess.vertLocs, location)
-	_location := unsafe.Pointer(&tess.vertLocs[len(tess.vertLocs)-1])
+	tess.VertexChecked(location, data)
+}
 
-	// Convert Go pointer to C pointer which is a requirement for cgo.
-	dataBytes := (*[unsafe.Sizeof(*_data)]byte)(unsafe.Pointer(_data))
-	dataSlice := dataBytes[:]
-	_dataC := C.CBytes(dataSlice)
+// VertexChecked is like Vertex, but returns the error GLU reported, as a
+// *TessError.
+func (tess *Tesselator) VertexChecked(location [3]float64, data interface{}) error {
+	tess.errs.beginVertex()
+	defer tess.errs.endVertex()
 
-	// Keep track of C pointer so we can free it later.
-	tess.cData = append(tess.cData, _dataC)
+	// Copy location to a safe memory location.
+	_location := tess.vertLocs.alloc(location)
 
-	C.gluTessVertex(tess.tess, (*C.GLdouble)(_location), _dataC)
+	_data := tess.newVertexHandle(data)
+	C.addGluTessVertex(tess.tess, _location, C.uintptr_t(_data), C.uintptr_t(tess.handle))
+	return tess.errs.take()
 }
 
 // Set the normal of the plane onto which points are projected onto before tesselation.
@@ -125,5 +181,27 @@ func (tess *Tesselator) Normal(valueX, valueY, valueZ float64) {
 
 // Set a property of the tesselator.
 func (tess *Tesselator) Property(which uint32, data float64) {
-	C.gluTessProperty(tess.tess, C.GLenum(which), C.GLdouble(data))
+	tess.PropertyChecked(which, data)
+}
+
+// PropertyChecked is like Property, but returns the error GLU reported, as
+// a *TessError.
+func (tess *Tesselator) PropertyChecked(which uint32, data float64) error {
+	C.setGluTessProperty(tess.tess, C.GLenum(which), C.GLdouble(data), C.uintptr_t(tess.handle))
+	return tess.errs.take()
+}
+
+// newVertexHandle returns a handle for vertex data to be handed to GLU,
+// which stays valid until EndPolygon.
+func (tess *Tesselator) newVertexHandle(data interface{}) cgo.Handle {
+	h := cgo.NewHandle(data)
+	tess.vertData = append(tess.vertData, h)
+	return h
+}
+
+func (tess *Tesselator) freeVertexHandles() {
+	for _, h := range tess.vertData {
+		h.Delete()
+	}
+	tess.vertData = tess.vertData[:0]
 }
@@ -0,0 +1,196 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+// Typed tesselator callbacks. V is the vertex data type and P the polygon
+// data type of a TypedTesselator.
+
+type TypedTessBeginHandler[P any] func(tessType uint32, polygonData P)
+
+type TypedTessVertexHandler[V, P any] func(vertexData V, polygonData P)
+
+type TypedTessEndHandler[P any] func(polygonData P)
+
+type TypedTessErrorHandler[P any] func(errorNumber uint32, polygonData P)
+
+type TypedTessEdgeFlagHandler[P any] func(flag bool, polygonData P)
+
+// The entries of vertexData beyond the vertices being combined are the zero
+// V, with a weight of zero.
+type TypedTessCombineHandler[V, P any] func(coords [3]float64,
+	vertexData [4]V,
+	weight [4]float32,
+	polygonData P) (outData V)
+
+// TypedTesselator is a Tesselator whose vertex and polygon data have the
+// static types V and P, so handlers need no type assertions.
+type TypedTesselator[V, P any] struct {
+	tess *Tesselator
+}
+
+// Create a new typed tesselator.
+func NewTypedTess[V, P any]() *TypedTesselator[V, P] {
+	return &TypedTesselator[V, P]{tess: NewTess()}
+}
+
+// Tesselator returns the underlying untyped tesselator. Data seen through
+// it are of the dynamic types V and P.
+func (t *TypedTesselator[V, P]) Tesselator() *Tesselator {
+	return t.tess
+}
+
+// Clean up resources held by the tesselator.
+func (t *TypedTesselator[V, P]) Delete() {
+	t.tess.Delete()
+}
+
+// Begin the drawing of the polygon, with the data parameter that will
+// be provided to callbacks.
+func (t *TypedTesselator[V, P]) BeginPolygon(data P) {
+	t.tess.BeginPolygon(data)
+}
+
+// BeginPolygonChecked is like BeginPolygon, but returns the error GLU
+// reported, as a *TessError.
+func (t *TypedTesselator[V, P]) BeginPolygonChecked(data P) error {
+	return t.tess.BeginPolygonChecked(data)
+}
+
+// End the drawing of the polygon. The returned error is the first
+// *TessError reported since BeginPolygon, if any.
+func (t *TypedTesselator[V, P]) EndPolygon() error {
+	return t.tess.EndPolygon()
+}
+
+// Begin a contour within the polygon.
+func (t *TypedTesselator[V, P]) BeginContour() {
+	t.tess.BeginContour()
+}
+
+// BeginContourChecked is like BeginContour, but returns the error GLU
+// reported, as a *TessError.
+func (t *TypedTesselator[V, P]) BeginContourChecked() error {
+	return t.tess.BeginContourChecked()
+}
+
+// End a contour within the polygon.
+func (t *TypedTesselator[V, P]) EndContour() {
+	t.tess.EndContour()
+}
+
+// EndContourChecked is like EndContour, but returns the error GLU
+// reported, as a *TessError.
+func (t *TypedTesselator[V, P]) EndContourChecked() error {
+	return t.tess.EndContourChecked()
+}
+
+// Add a vertex to the polygon, with the data parameter that will be
+// provided to callbacks.
+func (t *TypedTesselator[V, P]) Vertex(location [3]float64, data V) {
+	t.tess.Vertex(location, data)
+}
+
+// VertexChecked is like Vertex, but returns the error GLU reported, as a
+// *TessError.
+func (t *TypedTesselator[V, P]) VertexChecked(location [3]float64, data V) error {
+	return t.tess.VertexChecked(location, data)
+}
+
+// Set the normal of the plane onto which points are projected onto before tesselation.
+func (t *TypedTesselator[V, P]) Normal(valueX, valueY, valueZ float64) {
+	t.tess.Normal(valueX, valueY, valueZ)
+}
+
+// Set a property of the tesselator.
+func (t *TypedTesselator[V, P]) Property(which uint32, data float64) {
+	t.tess.Property(which, data)
+}
+
+// PropertyChecked is like Property, but returns the error GLU reported, as
+// a *TessError.
+func (t *TypedTesselator[V, P]) PropertyChecked(which uint32, data float64) error {
+	return t.tess.PropertyChecked(which, data)
+}
+
+// Sets the callback for TESS_BEGIN_DATA.
+func (t *TypedTesselator[V, P]) SetBeginCallback(f TypedTessBeginHandler[P]) {
+	if f == nil {
+		t.tess.SetBeginCallback(nil)
+		return
+	}
+	t.tess.SetBeginCallback(func(tessType uint32, polygonData interface{}) {
+		f(tessType, typed[P](polygonData))
+	})
+}
+
+// Sets the callback for TESS_VERTEX_DATA.
+func (t *TypedTesselator[V, P]) SetVertexCallback(f TypedTessVertexHandler[V, P]) {
+	if f == nil {
+		t.tess.SetVertexCallback(nil)
+		return
+	}
+	t.tess.SetVertexCallback(func(vertexData interface{}, polygonData interface{}) {
+		f(typed[V](vertexData), typed[P](polygonData))
+	})
+}
+
+// Sets the callback for TESS_END_DATA.
+func (t *TypedTesselator[V, P]) SetEndCallback(f TypedTessEndHandler[P]) {
+	if f == nil {
+		t.tess.SetEndCallback(nil)
+		return
+	}
+	t.tess.SetEndCallback(func(polygonData interface{}) {
+		f(typed[P](polygonData))
+	})
+}
+
+// Sets the callback for TESS_ERROR_DATA.
+func (t *TypedTesselator[V, P]) SetErrorCallback(f TypedTessErrorHandler[P]) {
+	if f == nil {
+		t.tess.SetErrorCallback(nil)
+		return
+	}
+	t.tess.SetErrorCallback(func(errorNumber uint32, polygonData interface{}) {
+		f(errorNumber, typed[P](polygonData))
+	})
+}
+
+// Sets the callback for TESS_EDGE_FLAG_DATA.
+func (t *TypedTesselator[V, P]) SetEdgeFlagCallback(f TypedTessEdgeFlagHandler[P]) {
+	if f == nil {
+		t.tess.SetEdgeFlagCallback(nil)
+		return
+	}
+	t.tess.SetEdgeFlagCallback(func(flag bool, polygonData interface{}) {
+		f(flag, typed[P](polygonData))
+	})
+}
+
+// Sets the callback for TESS_COMBINE_DATA.
+func (t *TypedTesselator[V, P]) SetCombineCallback(f TypedTessCombineHandler[V, P]) {
+	if f == nil {
+		t.tess.SetCombineCallback(nil)
+		return
+	}
+	t.tess.SetCombineCallback(func(coords [3]float64,
+		vertexData [4]interface{},
+		weight [4]float32,
+		polygonData interface{}) (outData interface{}) {
+
+		var data [4]V
+		for i, d := range vertexData {
+			data[i] = typed[V](d)
+		}
+		return f(coords, data, weight, typed[P](polygonData))
+	})
+}
+
+// typed converts data handed back by a Tesselator to T. Only values of
+// type T are ever handed in, but a nil interface stands for the zero T.
+func typed[T any](data interface{}) T {
+	v, _ := data.(T)
+	return v
+}
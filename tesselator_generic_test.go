@@ -0,0 +1,65 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"testing"
+)
+
+func TestTypedTesselatorStar(t *testing.T) {
+	poly := new(PolygonData)
+
+	for _, v := range StarContour {
+		poly.Vertices = append(poly.Vertices, VertexData{Location: v})
+	}
+
+	tess := NewTypedTess[*VertexData, *PolygonData]()
+
+	tess.SetBeginCallback(func(tessType uint32, polygonData *PolygonData) {
+		polygonData.BeginCount += 1
+	})
+	tess.SetVertexCallback(func(vertexData *VertexData, polygonData *PolygonData) {
+		polygonData.VertexCount += 1
+		vertexData.VertexHits += 1
+	})
+	tess.SetEndCallback(func(polygonData *PolygonData) {
+		polygonData.EndCount += 1
+	})
+	tess.SetErrorCallback(func(errno uint32, polygonData *PolygonData) {
+		polygonData.ErrorCount += 1
+	})
+	tess.SetEdgeFlagCallback(func(flag bool, polygonData *PolygonData) {
+		if flag {
+			polygonData.EdgeFlagCount += 1
+		}
+	})
+	tess.SetCombineCallback(func(coords [3]float64,
+		vertexData [4]*VertexData,
+		weight [4]float32,
+		polygonData *PolygonData) (outData *VertexData) {
+
+		polygonData.CombineCount += 1
+		for _, v := range vertexData {
+			v.CombineHits += 1
+		}
+		return &VertexData{Location: coords}
+	})
+
+	tess.Normal(0, 0, 1)
+
+	tess.BeginPolygon(poly)
+	tess.BeginContour()
+
+	for v := range poly.Vertices {
+		tess.Vertex(poly.Vertices[v].Location, &poly.Vertices[v])
+	}
+
+	tess.EndContour()
+	tess.EndPolygon()
+
+	checkPoly(t, poly, 1, 5*3, 1, 0, 1, 5)
+
+	tess.Delete()
+}
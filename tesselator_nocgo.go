@@ -0,0 +1,365 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !cgo
+
+package glu
+
+// This file provides the Tesselator when cgo is unavailable. It has the same
+// surface as the libGLU backend in tesselator.go, but runs a Go port of the
+// SGI libtess sweep-line algorithm.
+
+type tessState int
+
+const (
+	tessDormant tessState = iota
+	tessInPolygon
+	tessInContour
+)
+
+// Opaque object used for book keeping on the go side.
+type Tesselator struct {
+	state tessState
+
+	polyData interface{}
+
+	mesh     *tessMesh
+	lastEdge *halfEdge // lastEdge.org is the most recent vertex
+
+	normal      [3]float64
+	windingRule uint32
+	boundary    bool
+
+	// Sweep state, only valid during EndPolygon.
+	dict       *edgeDict
+	pq         *priorityQ
+	event      *tessVertex
+	fatalError bool
+
+	lonelyTriList *tessFace
+
+	errs tessErrors
+
+	beginData    TessBeginHandler
+	vertexData   TessVertexHandler
+	endData      TessEndHandler
+	errorData    TessErrorHandler
+	edgeFlagData TessEdgeFlagHandler
+	combineData  TessCombineHandler
+}
+
+// Create a new tesselator.
+func NewTess() (tess *Tesselator) {
+	tess = new(Tesselator)
+	tess.windingRule = TESS_WINDING_ODD
+	tess.errs.reset()
+	return
+}
+
+// Create a new tesselator. The pure Go tesselator calls handlers without
+// crossing into C, so this is the same as NewTess.
+func NewBufferedTess() (tess *Tesselator) {
+	return NewTess()
+}
+
+// Clean up resources held by the tesselator. The pure Go tesselator holds
+// no resources outside the Go heap, but Delete is kept for compatibility.
+func (tess *Tesselator) Delete() {
+	tess.requireState(tessDormant)
+	tess.polyData = nil
+}
+
+// Begin the drawing of the polygon, with the data parameter that will
+// be provided to callbacks.
+func (tess *Tesselator) BeginPolygon(data interface{}) {
+	tess.BeginPolygonChecked(data)
+}
+
+// BeginPolygonChecked is like BeginPolygon, but returns the error it
+// reported, as a *TessError.
+func (tess *Tesselator) BeginPolygonChecked(data interface{}) error {
+	tess.errs.reset()
+	tess.beginPolygon(data)
+	return tess.errs.take()
+}
+
+func (tess *Tesselator) beginPolygon(data interface{}) {
+	tess.requireState(tessDormant)
+
+	tess.state = tessInPolygon
+	tess.mesh = nil
+	tess.polyData = data
+}
+
+// End the drawing of the polygon. The returned error is the first
+// *TessError reported since BeginPolygon, if any, including those the
+// other methods returned or would have returned from their checked
+// variants.
+func (tess *Tesselator) EndPolygon() error {
+	tess.errs.contour = -1
+	tess.requireState(tessInPolygon)
+	tess.state = tessDormant
+
+	if tess.mesh == nil {
+		tess.mesh = newTessMesh()
+	}
+
+	// Determine the polygon normal and project vertices onto the plane of
+	// the polygon.
+	tess.projectPolygon()
+
+	// computeInterior computes the planar arrangement specified by the
+	// given contours, and further subdivides this arrangement into
+	// regions. Each region is marked "inside" if it belongs to the
+	// polygon, according to the rule given by tess.windingRule. Each
+	// interior region is guaranteed to be monotone.
+	tess.computeInterior()
+
+	mesh := tess.mesh
+	if !tess.fatalError {
+		// If the user wants only the boundary contours, we throw away all
+		// edges except those which separate the interior from the
+		// exterior. Otherwise we tessellate all the regions marked
+		// "inside".
+		if tess.boundary {
+			mesh.setWindingNumber(1, true)
+			tess.renderBoundary(mesh)
+		} else {
+			mesh.tessellateInterior()
+			tess.renderMesh(mesh)
+		}
+	}
+
+	tess.mesh = nil
+	tess.polyData = nil
+
+	err := tess.errs.first
+	tess.errs.reset()
+	tess.errs.take()
+	return err
+}
+
+// Begin a contour within the polygon.
+func (tess *Tesselator) BeginContour() {
+	tess.BeginContourChecked()
+}
+
+// BeginContourChecked is like BeginContour, but returns the error it
+// reported, as a *TessError.
+func (tess *Tesselator) BeginContourChecked() error {
+	tess.beginContour()
+	return tess.errs.take()
+}
+
+func (tess *Tesselator) beginContour() {
+	tess.requireState(tessInPolygon)
+
+	tess.state = tessInContour
+	tess.lastEdge = nil
+	tess.errs.beginContour()
+}
+
+// End a contour within the polygon.
+func (tess *Tesselator) EndContour() {
+	tess.EndContourChecked()
+}
+
+// EndContourChecked is like EndContour, but returns the error it reported,
+// as a *TessError.
+func (tess *Tesselator) EndContourChecked() error {
+	tess.endContour()
+	return tess.errs.take()
+}
+
+func (tess *Tesselator) endContour() {
+	tess.requireState(tessInContour)
+	tess.state = tessInPolygon
+}
+
+// Add a vertex to the polygon, with the data parameter that will be
+// provided to callbacks.
+func (tess *Tesselator) Vertex(location [3]float64, data interface{}) {
+	tess.VertexChecked(location, data)
+}
+
+// VertexChecked is like Vertex, but returns the error it reported, as a
+// *TessError.
+func (tess *Tesselator) VertexChecked(location [3]float64, data interface{}) error {
+	tess.requireState(tessInContour)
+	tess.errs.beginVertex()
+	defer tess.errs.endVertex()
+
+	tooLarge := false
+	for i, x := range location {
+		if x < -tessMaxCoord {
+			location[i] = -tessMaxCoord
+			tooLarge = true
+		}
+		if x > tessMaxCoord {
+			location[i] = tessMaxCoord
+			tooLarge = true
+		}
+	}
+	if tooLarge {
+		tess.callError(TESS_COORD_TOO_LARGE)
+	}
+
+	if tess.mesh == nil {
+		tess.mesh = newTessMesh()
+	}
+
+	e := tess.lastEdge
+	if e == nil {
+		// Make a self-loop (one vertex, one edge).
+		e = tess.mesh.makeEdge()
+		meshSplice(e, e.sym)
+	} else {
+		// Create a new vertex and edge which immediately follow e in the
+		// ordering around the left face.
+		meshSplitEdge(e)
+		e = e.lnext
+	}
+
+	// The new vertex is now e.org.
+	e.org.data = data
+	e.org.coords = location
+
+	// The winding of an edge says how the winding number changes as we
+	// cross from the edge's right face to its left face. We add the
+	// vertices in such an order that a CCW contour will add +1 to the
+	// winding number of the region inside the contour.
+	e.winding = 1
+	e.sym.winding = -1
+
+	tess.lastEdge = e
+	return tess.errs.take()
+}
+
+// Set the normal of the plane onto which points are projected onto before tesselation.
+func (tess *Tesselator) Normal(valueX, valueY, valueZ float64) {
+	tess.normal = [3]float64{valueX, valueY, valueZ}
+}
+
+// Set a property of the tesselator.
+func (tess *Tesselator) Property(which uint32, data float64) {
+	tess.PropertyChecked(which, data)
+}
+
+// PropertyChecked is like Property, but returns the error it reported, as
+// a *TessError.
+func (tess *Tesselator) PropertyChecked(which uint32, data float64) error {
+	switch which {
+	case TESS_TOLERANCE:
+		// The tolerance is a hint which, as in libGLU, is not used.
+		if data < 0 || data > 1 {
+			break
+		}
+		return nil
+	case TESS_WINDING_RULE:
+		rule := uint32(data)
+		if float64(rule) != data {
+			break // not an integer
+		}
+		switch rule {
+		case TESS_WINDING_ODD, TESS_WINDING_NONZERO, TESS_WINDING_POSITIVE,
+			TESS_WINDING_NEGATIVE, TESS_WINDING_ABS_GEQ_TWO:
+			tess.windingRule = rule
+			return nil
+		}
+	case TESS_BOUNDARY_ONLY:
+		tess.boundary = data != 0
+		return nil
+	default:
+		tess.callError(INVALID_ENUM)
+		return tess.errs.take()
+	}
+	tess.callError(INVALID_VALUE)
+	return tess.errs.take()
+}
+
+// Sets the callback for TESS_BEGIN_DATA.
+func (tess *Tesselator) SetBeginCallback(f TessBeginHandler) {
+	tess.beginData = f
+}
+
+// Sets the callback for TESS_VERTEX_DATA.
+func (tess *Tesselator) SetVertexCallback(f TessVertexHandler) {
+	tess.vertexData = f
+}
+
+// Sets the callback for TESS_END_DATA.
+func (tess *Tesselator) SetEndCallback(f TessEndHandler) {
+	tess.endData = f
+}
+
+// Sets the callback for TESS_ERROR_DATA.
+func (tess *Tesselator) SetErrorCallback(f TessErrorHandler) {
+	tess.errorData = f
+}
+
+// Sets the callback for TESS_EDGE_FLAG_DATA.
+func (tess *Tesselator) SetEdgeFlagCallback(f TessEdgeFlagHandler) {
+	tess.edgeFlagData = f
+}
+
+// Sets the callback for TESS_COMBINE_DATA.
+func (tess *Tesselator) SetCombineCallback(f TessCombineHandler) {
+	tess.combineData = f
+}
+
+// requireState reports the missing calls needed to get from the current
+// state to s, and performs them.
+func (tess *Tesselator) requireState(s tessState) {
+	for tess.state != s {
+		// We change the current state one level at a time, to get to the
+		// desired state.
+		if tess.state < s {
+			switch tess.state {
+			case tessDormant:
+				tess.callError(TESS_MISSING_BEGIN_POLYGON)
+				tess.beginPolygon(nil)
+			case tessInPolygon:
+				tess.callError(TESS_MISSING_BEGIN_CONTOUR)
+				tess.beginContour()
+			}
+		} else {
+			switch tess.state {
+			case tessInContour:
+				tess.callError(TESS_MISSING_END_CONTOUR)
+				tess.endContour()
+			case tessInPolygon:
+				tess.callError(TESS_MISSING_END_POLYGON)
+				// EndPolygon is too much work!
+				tess.state = tessDormant
+				tess.lastEdge = nil
+				tess.mesh = nil
+			}
+		}
+	}
+}
+
+func (tess *Tesselator) callBegin(tessType uint32) {
+	if tess.beginData != nil {
+		tess.beginData(tessType, tess.polyData)
+	}
+}
+
+func (tess *Tesselator) callVertex(data interface{}) {
+	if tess.vertexData != nil {
+		tess.vertexData(data, tess.polyData)
+	}
+}
+
+func (tess *Tesselator) callEnd() {
+	if tess.endData != nil {
+		tess.endData(tess.polyData)
+	}
+}
+
+func (tess *Tesselator) callError(errorNumber uint32) {
+	tess.errs.record(errorNumber)
+	if tess.errorData != nil {
+		tess.errorData(errorNumber, tess.polyData)
+	}
+}
@@ -5,6 +5,7 @@
 package glu
 
 import (
+	"math"
 	"testing"
 )
 
@@ -275,3 +276,209 @@ func checkPoly(t *testing.T, poly *PolygonData, expectedBegins, expectedVertices
 			poly.CombineCount)
 	}
 }
+
+// Tesselators running in parallel must not see each other's callbacks.
+// Run with -race, and GOEXPERIMENT=cgocheck2 for the cgo backend.
+func TestTesselatorConcurrent(t *testing.T) {
+	done := make(chan *PolygonData)
+	for i := 0; i < 8; i++ {
+		go func() {
+			poly := new(PolygonData)
+			for _, v := range StarContour {
+				poly.Vertices = append(poly.Vertices, VertexData{Location: v})
+			}
+
+			tess := NewTess()
+			tess.SetBeginCallback(tessBeginDataHandler)
+			tess.SetVertexCallback(tessVertexDataHandler)
+			tess.SetEndCallback(tessEndDataHandler)
+			tess.SetErrorCallback(tessErrorDataHandler)
+			tess.SetEdgeFlagCallback(tessEdgeFlagDataHandler)
+			tess.SetCombineCallback(func(coords [3]float64,
+				vertexData [4]interface{},
+				weight [4]float32,
+				polygonData interface{}) (outData interface{}) {
+
+				polygonData.(*PolygonData).CombineCount += 1
+				return &VertexData{Location: coords}
+			})
+			tess.Normal(0, 0, 1)
+
+			for j := 0; j < 10; j++ {
+				tess.BeginPolygon(poly)
+				tess.BeginContour()
+				for v := range StarContour {
+					tess.Vertex(poly.Vertices[v].Location, &poly.Vertices[v])
+				}
+				tess.EndContour()
+				tess.EndPolygon()
+			}
+
+			tess.Delete()
+			done <- poly
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		poly := <-done
+		checkPoly(t, poly, 10, 10*5*3, 10, 0, 10, 10*5)
+	}
+}
+
+// A polygon larger than any internal buffer, tessellated repeatedly by one
+// tesselator, must keep every vertex and its data intact.
+func TestTesselatorLarge(t *testing.T) {
+	const n = 50000
+
+	contour := make([][3]float64, n)
+	for i := range contour {
+		a := 2 * math.Pi * float64(i) / n
+		contour[i] = [3]float64{math.Cos(a), math.Sin(a), 0}
+	}
+
+	tess := NewTess()
+	defer tess.Delete()
+
+	var vertices int
+	var bad int
+	tess.SetVertexCallback(func(vertexData interface{}, polygonData interface{}) {
+		i := vertexData.(int)
+		if i < 0 || i >= n {
+			bad++
+		}
+		vertices++
+	})
+	tess.SetEdgeFlagCallback(func(flag bool, polygonData interface{}) {})
+	tess.Normal(0, 0, 1)
+
+	for j := 0; j < 3; j++ {
+		vertices = 0
+		tess.BeginPolygon(nil)
+		tess.BeginContour()
+		for i, v := range contour {
+			tess.Vertex(v, i)
+		}
+		tess.EndContour()
+		if err := tess.EndPolygon(); err != nil {
+			t.Fatal(err)
+		}
+
+		if vertices != (n-2)*3 {
+			t.Errorf("Expected %v vertices, got %v\n", (n-2)*3, vertices)
+		}
+	}
+	if bad != 0 {
+		t.Errorf("Got %v vertices with invalid data\n", bad)
+	}
+}
+
+func TestBufferedTesselatorData(t *testing.T) {
+	poly := new(PolygonData)
+
+	for _, v := range OuterContour {
+		poly.Vertices = append(poly.Vertices, VertexData{Location: v})
+	}
+	for _, v := range InnerContour {
+		poly.Vertices = append(poly.Vertices, VertexData{Location: v})
+	}
+
+	tess := NewBufferedTess()
+
+	tess.SetBeginCallback(tessBeginDataHandler)
+	tess.SetVertexCallback(tessVertexDataHandler)
+	tess.SetEndCallback(tessEndDataHandler)
+	tess.SetErrorCallback(tessErrorDataHandler)
+	tess.SetEdgeFlagCallback(tessEdgeFlagDataHandler)
+	tess.SetCombineCallback(tessCombineDataHandler)
+
+	tess.Normal(0, 0, 1)
+
+	tess.BeginPolygon(poly)
+	tess.BeginContour()
+	for v := 0; v < 4; v += 1 {
+		tess.Vertex(poly.Vertices[v].Location, &poly.Vertices[v])
+	}
+	tess.EndContour()
+	tess.BeginContour()
+	for v := 4; v < 8; v += 1 {
+		tess.Vertex(poly.Vertices[v].Location, &poly.Vertices[v])
+	}
+	tess.EndContour()
+	tess.EndPolygon()
+
+	checkPoly(t, poly, 1, 8*3, 1, 0, 8, 0)
+
+	tess.Delete()
+}
+
+func TestBufferedTesselatorMissingBeginPolygon(t *testing.T) {
+	tess := NewBufferedTess()
+	defer tess.Delete()
+
+	var vertices int
+	tess.SetBeginCallback(func(tessType uint32, polygonData interface{}) {})
+	tess.SetVertexCallback(func(vertexData, polygonData interface{}) { vertices++ })
+	tess.SetEndCallback(func(polygonData interface{}) {})
+	tess.SetEdgeFlagCallback(func(flag bool, polygonData interface{}) {})
+
+	tess.BeginContour()
+	for _, v := range OuterContour {
+		tess.Vertex(v, nil)
+	}
+	tess.EndContour()
+	if err := tess.EndPolygon(); err == nil {
+		t.Errorf("Expected an error\n")
+	}
+
+	// The tesselator is usable afterwards.
+	tess.BeginPolygon(nil)
+	tess.BeginContour()
+	for _, v := range OuterContour {
+		tess.Vertex(v, nil)
+	}
+	tess.EndContour()
+	vertices = 0
+	if err := tess.EndPolygon(); err != nil || vertices != 6 {
+		t.Errorf("Expected 6 vertices and no error, got %d and %v\n", vertices, err)
+	}
+}
+
+func BenchmarkTesselator(b *testing.B) {
+	benchmarkTesselator(b, NewTess)
+}
+
+func BenchmarkBufferedTesselator(b *testing.B) {
+	benchmarkTesselator(b, NewBufferedTess)
+}
+
+func benchmarkTesselator(b *testing.B, newTess func() *Tesselator) {
+	const n = 10000
+
+	contour := make([][3]float64, n)
+	for i := range contour {
+		a := 2 * math.Pi * float64(i) / n
+		contour[i] = [3]float64{math.Cos(a), math.Sin(a), 0}
+	}
+
+	tess := newTess()
+	defer tess.Delete()
+
+	vertices := 0
+	tess.SetBeginCallback(func(tessType uint32, polygonData interface{}) {})
+	tess.SetVertexCallback(func(vertexData interface{}, polygonData interface{}) {
+		vertices++
+	})
+	tess.SetEndCallback(func(polygonData interface{}) {})
+	tess.SetEdgeFlagCallback(func(flag bool, polygonData interface{}) {})
+	tess.Normal(0, 0, 1)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tess.BeginPolygon(nil)
+		tess.BeginContour()
+		for j, v := range contour {
+			tess.Vertex(v, j)
+		}
+		tess.EndContour()
+		tess.EndPolygon()
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+// Contour is a closed loop of vertex locations. The last vertex is
+// implicitly connected to the first.
+type Contour [][3]float64
+
+// Tessellate triangulates the polygon made of the given contours using a
+// TESS_WINDING_* rule. The normal is handed to Tesselator.Normal; a zero
+// normal lets the tesselator compute one itself.
+//
+// Coincident vertices are merged, so triangles sharing a corner share an
+// index. Fans and strips are expanded into independent triangles. The
+// error returned by Tesselator.EndPolygon, if any, is returned instead of
+// the mesh.
+func Tessellate(contours []Contour, windingRule uint32, normal [3]float64) (*Mesh, error) {
+	b := &meshBuilder{index: make(map[[3]float64]uint32)}
+
+	tess := NewTess()
+	defer tess.Delete()
+
+	tess.SetBeginCallback(b.begin)
+	tess.SetVertexCallback(b.vertex)
+	tess.SetEndCallback(b.end)
+	tess.SetCombineCallback(b.combine)
+
+	tess.Property(TESS_WINDING_RULE, float64(windingRule))
+	tess.Normal(normal[0], normal[1], normal[2])
+
+	for _, c := range contours {
+		b.locs = append(b.locs, c...)
+	}
+
+	n := 0
+	tess.BeginPolygon(nil)
+	for _, c := range contours {
+		tess.BeginContour()
+		for _, v := range c {
+			tess.Vertex(v, n)
+			n++
+		}
+		tess.EndContour()
+	}
+	if err := tess.EndPolygon(); err != nil {
+		return nil, err
+	}
+	return &b.mesh, nil
+}
+
+// meshBuilder collects the output of a Tesselator into a Mesh. Vertex data
+// handed to the tesselator are indices into locs.
+type meshBuilder struct {
+	mesh  Mesh
+	locs  [][3]float64
+	index map[[3]float64]uint32
+	prims primitiveAssembler
+}
+
+func (b *meshBuilder) begin(tessType uint32, polygonData interface{}) {
+	b.prims.begin(tessType)
+}
+
+func (b *meshBuilder) vertex(vertexData interface{}, polygonData interface{}) {
+	loc := b.locs[vertexData.(int)]
+	i, ok := b.index[loc]
+	if !ok {
+		i = uint32(len(b.mesh.Positions))
+		b.index[loc] = i
+		b.mesh.Positions = append(b.mesh.Positions, loc)
+	}
+	b.mesh.Indices = b.prims.vertex(b.mesh.Indices, i)
+}
+
+func (b *meshBuilder) end(polygonData interface{}) {
+	b.prims.end()
+}
+
+func (b *meshBuilder) combine(coords [3]float64,
+	vertexData [4]interface{},
+	weight [4]float32,
+	polygonData interface{}) (outData interface{}) {
+
+	b.locs = append(b.locs, coords)
+	return len(b.locs) - 1
+}
+
+// primitiveAssembler expands the GL primitives emitted between begin and end
+// callbacks into independent triangles.
+type primitiveAssembler struct {
+	mode    uint32
+	n       int
+	a, b, c uint32
+}
+
+func (p *primitiveAssembler) begin(mode uint32) {
+	p.mode = mode
+	p.n = 0
+}
+
+// vertex appends the triangles completed by index i to indices.
+func (p *primitiveAssembler) vertex(indices []uint32, i uint32) []uint32 {
+	switch p.mode {
+	case TRIANGLES:
+		indices = append(indices, i)
+	case TRIANGLE_FAN, POLYGON:
+		if p.n >= 2 {
+			indices = append(indices, p.a, p.b, i)
+		}
+		if p.n == 0 {
+			p.a = i
+		} else {
+			p.b = i
+		}
+	case TRIANGLE_STRIP:
+		if p.n >= 2 {
+			// Every other triangle of a strip has reversed winding.
+			if p.n%2 == 0 {
+				indices = append(indices, p.a, p.b, i)
+			} else {
+				indices = append(indices, p.b, p.a, i)
+			}
+		}
+		p.a, p.b = p.b, i
+	case QUAD_STRIP:
+		// Quad k is made of vertices 2k, 2k+1, 2k+3 and 2k+2.
+		switch {
+		case p.n == 0:
+			p.a = i
+		case p.n == 1:
+			p.b = i
+		case p.n%2 == 0:
+			p.c = i
+		default:
+			indices = append(indices, p.a, p.b, i, p.a, i, p.c)
+			p.a, p.b = p.c, i
+		}
+	}
+	p.n++
+	return indices
+}
+
+func (p *primitiveAssembler) end() {
+	p.n = 0
+}
@@ -0,0 +1,104 @@
+// Copyright 2012 The go-gl Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package glu
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTessellateSquareWithHole(t *testing.T) {
+	contours := []Contour{OuterContour[:], InnerContour[:]}
+
+	mesh, err := Tessellate(contours, TESS_WINDING_ODD, [3]float64{0, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkMesh(t, mesh, 8, 8)
+	if area := meshArea(mesh); area != 12 {
+		t.Errorf("Expected area == 12, got %v\n", area)
+	}
+}
+
+func TestTessellateStar(t *testing.T) {
+	contours := []Contour{StarContour[:]}
+
+	mesh, err := Tessellate(contours, TESS_WINDING_ODD, [3]float64{0, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Five points plus the five intersections created by combine.
+	checkMesh(t, mesh, 10, 5)
+
+	mesh, err = Tessellate(contours, TESS_WINDING_NONZERO, [3]float64{0, 0, 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMesh(t, mesh, 10, 8)
+}
+
+func TestTessellateError(t *testing.T) {
+	contours := []Contour{{{0, 0, 0}, {1e200, 0, 0}, {0, 1, 0}}}
+
+	_, err := Tessellate(contours, TESS_WINDING_ODD, [3]float64{0, 0, 1})
+	if err == nil {
+		t.Errorf("Expected an error for coordinates out of range")
+	}
+}
+
+func TestPrimitiveAssemblerQuadStrip(t *testing.T) {
+	var p primitiveAssembler
+	var indices []uint32
+	p.begin(QUAD_STRIP)
+	for i := uint32(0); i < 6; i++ {
+		indices = p.vertex(indices, i)
+	}
+	p.end()
+
+	expected := []uint32{0, 1, 3, 0, 3, 2, 2, 3, 5, 2, 5, 4}
+	if len(indices) != len(expected) {
+		t.Fatalf("Expected %v, got %v\n", expected, indices)
+	}
+	for i := range expected {
+		if indices[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v\n", expected, indices)
+		}
+	}
+}
+
+func checkMesh(t *testing.T, mesh *Mesh, expectedVertices, expectedTriangles int) {
+	if len(mesh.Positions) != expectedVertices {
+		t.Errorf("Expected %v vertices, got %v\n",
+			expectedVertices,
+			len(mesh.Positions))
+	}
+	if mesh.TriangleCount() != expectedTriangles {
+		t.Errorf("Expected %v triangles, got %v\n",
+			expectedTriangles,
+			mesh.TriangleCount())
+	}
+	for i := 0; i < mesh.TriangleCount(); i++ {
+		if triangleArea(mesh.Triangle(i)) <= 0 {
+			t.Errorf("Triangle %v is not counterclockwise: %v\n",
+				i,
+				mesh.Triangle(i))
+		}
+	}
+}
+
+func meshArea(mesh *Mesh) float64 {
+	var area float64
+	for i := 0; i < mesh.TriangleCount(); i++ {
+		area += triangleArea(mesh.Triangle(i))
+	}
+	return math.Round(area*1e9) / 1e9
+}
+
+// triangleArea returns the signed area of a triangle in the xy plane.
+func triangleArea(tri [3][3]float64) float64 {
+	return ((tri[1][0]-tri[0][0])*(tri[2][1]-tri[0][1]) -
+		(tri[2][0]-tri[0][0])*(tri[1][1]-tri[0][1])) / 2
+}